@@ -0,0 +1,477 @@
+// Package api is a small client for the parts of the Amazon EC2 Query API that cogo uses
+// requests are signed and retried with the AWS SDK's signer and standard retryer
+// https://docs.aws.amazon.com/AWSEC2/latest/APIReference/Welcome.html
+package api
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/aws/retry"
+	v4 "github.com/aws/aws-sdk-go-v2/aws/signer/v4"
+)
+
+// apiVersion is the EC2 API version requests are made against
+const apiVersion = "2016-11-15"
+
+// service is the name requests are signed for
+const service = "ec2"
+
+// maxResults is the largest page size accepted by every Describe action used
+const maxResults = 100
+
+// maxImages stops DescribeImages paging through an owner with a very large number of images
+const maxImages = 1000
+
+// Client sends signed requests to the EC2 API of a single region
+type Client struct {
+	baseURL    string
+	region     string
+	creds      aws.Credentials
+	signer     *v4.Signer
+	retryer    aws.Retryer
+	httpClient *http.Client
+	now        func() time.Time
+}
+
+// Option configures a Client
+type Option func(*Client)
+
+// WithBaseURL sends requests to another endpoint, e.g. LocalStack, moto or an httptest server
+func WithBaseURL(baseURL string) Option {
+	return func(c *Client) {
+		c.baseURL = strings.TrimSuffix(baseURL, "/")
+	}
+}
+
+// WithHTTPClient sends requests with the given http client
+func WithHTTPClient(httpClient *http.Client) Option {
+	return func(c *Client) {
+		c.httpClient = httpClient
+	}
+}
+
+// WithRetryer retries failed requests with the given retryer instead of the SDK's standard one
+func WithRetryer(retryer aws.Retryer) Option {
+	return func(c *Client) {
+		c.retryer = retryer
+	}
+}
+
+// NewClient creates a client for the given region that signs requests with creds
+func NewClient(creds aws.Credentials, region string, opts ...Option) *Client {
+	client := &Client{
+		baseURL:    "https://ec2." + region + ".amazonaws.com",
+		region:     region,
+		creds:      creds,
+		signer:     v4.NewSigner(),
+		retryer:    retry.NewStandard(),
+		httpClient: http.DefaultClient,
+		now:        time.Now,
+	}
+
+	for _, opt := range opts {
+		opt(client)
+	}
+
+	return client
+}
+
+// Region returns the region the client sends requests to
+func (c *Client) Region() string {
+	return c.region
+}
+
+// Error is returned when the API responds with an error
+type Error struct {
+	StatusCode int
+	Code       string
+	Message    string
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("ec2: %s (%s, status %d)", e.Message, e.Code, e.StatusCode)
+}
+
+// HTTPStatusCode lets the retryer retry server errors
+func (e *Error) HTTPStatusCode() int {
+	return e.StatusCode
+}
+
+// ErrorCode lets the retryer retry throttling errors
+func (e *Error) ErrorCode() string {
+	return e.Code
+}
+
+// DescribeInstances returns the instances matching the filters
+func (c *Client) DescribeInstances(ctx context.Context, filters ...Filter) ([]Instance, error) {
+	params := url.Values{}
+	params.Set("MaxResults", strconv.Itoa(maxResults))
+	addFilters(params, filters)
+
+	instances := []Instance{}
+
+	err := c.describeAll(ctx, "DescribeInstances", params, func(data []byte) (string, error) {
+		var response struct {
+			Reservations []struct {
+				Instances []Instance `xml:"instancesSet>item"`
+			} `xml:"reservationSet>item"`
+			NextToken string `xml:"nextToken"`
+		}
+		if err := xml.Unmarshal(data, &response); err != nil {
+			return "", err
+		}
+
+		for _, reservation := range response.Reservations {
+			instances = append(instances, reservation.Instances...)
+		}
+
+		return response.NextToken, nil
+	})
+
+	return instances, err
+}
+
+// GetInstance returns the instance with the given ID
+func (c *Client) GetInstance(ctx context.Context, id string) (*Instance, error) {
+	params := url.Values{}
+	params.Set("InstanceId.1", id)
+
+	var response struct {
+		Reservations []struct {
+			Instances []Instance `xml:"instancesSet>item"`
+		} `xml:"reservationSet>item"`
+	}
+	if err := c.call(ctx, "DescribeInstances", params, &response); err != nil {
+		return nil, err
+	}
+
+	for _, reservation := range response.Reservations {
+		for _, instance := range reservation.Instances {
+			if instance.InstanceID == id {
+				return &instance, nil
+			}
+		}
+	}
+
+	return nil, &Error{StatusCode: http.StatusNotFound, Code: "InvalidInstanceID.NotFound", Message: fmt.Sprintf("the instance ID '%s' does not exist", id)}
+}
+
+// RunInstance launches a single instance, tagging it with its name
+func (c *Client) RunInstance(ctx context.Context, request RunInstanceRequest) (*Instance, error) {
+	params := url.Values{}
+	params.Set("MinCount", "1")
+	params.Set("MaxCount", "1")
+	params.Set("ImageId", request.ImageID)
+	params.Set("InstanceType", request.InstanceType)
+
+	if request.AvailabilityZone != "" {
+		params.Set("Placement.AvailabilityZone", request.AvailabilityZone)
+	}
+	if request.KeyName != "" {
+		params.Set("KeyName", request.KeyName)
+	}
+	for i, id := range request.SecurityGroupIDs {
+		params.Set(fmt.Sprintf("SecurityGroupId.%d", i+1), id)
+	}
+	if request.UserData != "" {
+		params.Set("UserData", base64.StdEncoding.EncodeToString([]byte(request.UserData)))
+	}
+	if request.Name != "" {
+		params.Set("TagSpecification.1.ResourceType", "instance")
+		params.Set("TagSpecification.1.Tag.1.Key", "Name")
+		params.Set("TagSpecification.1.Tag.1.Value", request.Name)
+	}
+
+	var response struct {
+		Instances []Instance `xml:"instancesSet>item"`
+	}
+	if err := c.call(ctx, "RunInstances", params, &response); err != nil {
+		return nil, err
+	}
+
+	if len(response.Instances) == 0 {
+		return nil, fmt.Errorf("ec2: RunInstances did not return an instance")
+	}
+
+	return &response.Instances[0], nil
+}
+
+// TerminateInstance terminates the instance with the given ID
+func (c *Client) TerminateInstance(ctx context.Context, id string) error {
+	params := url.Values{}
+	params.Set("InstanceId.1", id)
+
+	return c.call(ctx, "TerminateInstances", params, nil)
+}
+
+// DescribeRegions returns the regions enabled for the account
+func (c *Client) DescribeRegions(ctx context.Context) ([]Region, error) {
+	var response struct {
+		Regions []Region `xml:"regionInfo>item"`
+	}
+	if err := c.call(ctx, "DescribeRegions", url.Values{}, &response); err != nil {
+		return nil, err
+	}
+
+	return response.Regions, nil
+}
+
+// DescribeAvailabilityZones returns the available zones in the client's region
+func (c *Client) DescribeAvailabilityZones(ctx context.Context) ([]AvailabilityZone, error) {
+	params := url.Values{}
+	addFilters(params, []Filter{{Name: "state", Values: []string{"available"}}})
+
+	var response struct {
+		Zones []AvailabilityZone `xml:"availabilityZoneInfo>item"`
+	}
+	if err := c.call(ctx, "DescribeAvailabilityZones", params, &response); err != nil {
+		return nil, err
+	}
+
+	return response.Zones, nil
+}
+
+// DescribeImages returns the images owned by any of the owners that match the filters
+// at most maxImages are returned
+func (c *Client) DescribeImages(ctx context.Context, owners []string, filters ...Filter) ([]Image, error) {
+	params := url.Values{}
+	params.Set("MaxResults", strconv.Itoa(maxResults))
+	for i, owner := range owners {
+		params.Set(fmt.Sprintf("Owner.%d", i+1), owner)
+	}
+	addFilters(params, filters)
+
+	images := []Image{}
+
+	err := c.describeAll(ctx, "DescribeImages", params, func(data []byte) (string, error) {
+		var response struct {
+			Images    []Image `xml:"imagesSet>item"`
+			NextToken string  `xml:"nextToken"`
+		}
+		if err := xml.Unmarshal(data, &response); err != nil {
+			return "", err
+		}
+
+		images = append(images, response.Images...)
+
+		if len(images) >= maxImages {
+			images = images[:maxImages]
+			return "", nil
+		}
+
+		return response.NextToken, nil
+	})
+
+	return images, err
+}
+
+// DescribeInstanceTypes returns the instance types offered in the client's region that match the filters
+func (c *Client) DescribeInstanceTypes(ctx context.Context, filters ...Filter) ([]InstanceType, error) {
+	params := url.Values{}
+	params.Set("MaxResults", strconv.Itoa(maxResults))
+	addFilters(params, filters)
+
+	instanceTypes := []InstanceType{}
+
+	err := c.describeAll(ctx, "DescribeInstanceTypes", params, func(data []byte) (string, error) {
+		var response struct {
+			InstanceTypes []InstanceType `xml:"instanceTypeSet>item"`
+			NextToken     string         `xml:"nextToken"`
+		}
+		if err := xml.Unmarshal(data, &response); err != nil {
+			return "", err
+		}
+
+		instanceTypes = append(instanceTypes, response.InstanceTypes...)
+
+		return response.NextToken, nil
+	})
+
+	return instanceTypes, err
+}
+
+// DescribeKeyPairs returns the key pairs in the client's region
+func (c *Client) DescribeKeyPairs(ctx context.Context) ([]KeyPair, error) {
+	var response struct {
+		KeyPairs []KeyPair `xml:"keySet>item"`
+	}
+	if err := c.call(ctx, "DescribeKeyPairs", url.Values{}, &response); err != nil {
+		return nil, err
+	}
+
+	return response.KeyPairs, nil
+}
+
+// DescribeSecurityGroups returns the security groups in the client's region
+func (c *Client) DescribeSecurityGroups(ctx context.Context) ([]SecurityGroup, error) {
+	params := url.Values{}
+	params.Set("MaxResults", strconv.Itoa(maxResults))
+
+	securityGroups := []SecurityGroup{}
+
+	err := c.describeAll(ctx, "DescribeSecurityGroups", params, func(data []byte) (string, error) {
+		var response struct {
+			SecurityGroups []SecurityGroup `xml:"securityGroupInfo>item"`
+			NextToken      string          `xml:"nextToken"`
+		}
+		if err := xml.Unmarshal(data, &response); err != nil {
+			return "", err
+		}
+
+		securityGroups = append(securityGroups, response.SecurityGroups...)
+
+		return response.NextToken, nil
+	})
+
+	return securityGroups, err
+}
+
+// addFilters adds the filters to the params as Filter.N.Name and Filter.N.Value.M
+func addFilters(params url.Values, filters []Filter) {
+	for i, filter := range filters {
+		prefix := fmt.Sprintf("Filter.%d.", i+1)
+		params.Set(prefix+"Name", filter.Name)
+		for j, value := range filter.Values {
+			params.Set(fmt.Sprintf("%sValue.%d", prefix, j+1), value)
+		}
+	}
+}
+
+// describeAll will call a Describe action for every page of results
+// decode is given each page's response and returns the token of the next page
+func (c *Client) describeAll(ctx context.Context, action string, params url.Values, decode func(data []byte) (string, error)) error {
+	for {
+		data, err := c.send(ctx, action, params)
+		if err != nil {
+			return err
+		}
+
+		nextToken, err := decode(data)
+		if err != nil {
+			return err
+		}
+
+		// if we are at the last page, we are done
+		if nextToken == "" {
+			return nil
+		}
+
+		params.Set("NextToken", nextToken)
+	}
+}
+
+// call sends an action and decodes the xml response into out, when out is not nil
+func (c *Client) call(ctx context.Context, action string, params url.Values, out interface{}) error {
+	data, err := c.send(ctx, action, params)
+	if err != nil {
+		return err
+	}
+
+	if out == nil {
+		return nil
+	}
+
+	return xml.Unmarshal(data, out)
+}
+
+// send posts an action with its params, retrying it while the retryer allows, returning the raw response body
+func (c *Client) send(ctx context.Context, action string, params url.Values) ([]byte, error) {
+	form := url.Values{}
+	for key, values := range params {
+		form[key] = values
+	}
+	form.Set("Action", action)
+	form.Set("Version", apiVersion)
+
+	body := []byte(form.Encode())
+
+	for attempt := 1; ; attempt++ {
+		data, err := c.post(ctx, body)
+		if err == nil {
+			return data, nil
+		}
+
+		if attempt >= c.retryer.MaxAttempts() || !c.retryer.IsErrorRetryable(err) {
+			return nil, err
+		}
+
+		delay, delayErr := c.retryer.RetryDelay(attempt, err)
+		if delayErr != nil {
+			return nil, err
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(delay):
+		}
+	}
+}
+
+// post signs and posts a single request
+func (c *Client) post(ctx context.Context, body []byte) ([]byte, error) {
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, c.baseURL+"/", bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+
+	request.Header.Set("Content-Type", "application/x-www-form-urlencoded; charset=utf-8")
+
+	payloadHash := sha256.Sum256(body)
+	if err := c.signer.SignHTTP(ctx, c.creds, request, hex.EncodeToString(payloadHash[:]), service, c.region, c.now()); err != nil {
+		return nil, err
+	}
+
+	response, err := c.httpClient.Do(request)
+	if err != nil {
+		return nil, err
+	}
+	defer response.Body.Close()
+
+	data, err := io.ReadAll(response.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	if response.StatusCode >= 300 {
+		// EC2 wraps errors in Response>Errors>Error, some stand-ins use ErrorResponse>Error
+		var errorResponse struct {
+			Errors []struct {
+				Code    string `xml:"Code"`
+				Message string `xml:"Message"`
+			} `xml:"Errors>Error"`
+			Error struct {
+				Code    string `xml:"Code"`
+				Message string `xml:"Message"`
+			} `xml:"Error"`
+		}
+		apiError := &Error{StatusCode: response.StatusCode}
+		if xml.Unmarshal(data, &errorResponse) == nil {
+			apiError.Code = errorResponse.Error.Code
+			apiError.Message = errorResponse.Error.Message
+			if len(errorResponse.Errors) > 0 {
+				apiError.Code = errorResponse.Errors[0].Code
+				apiError.Message = errorResponse.Errors[0].Message
+			}
+		}
+		if apiError.Message == "" {
+			apiError.Message = http.StatusText(response.StatusCode)
+		}
+		return nil, apiError
+	}
+
+	return data, nil
+}
@@ -0,0 +1,197 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/aws/retry"
+)
+
+var testCredentials = aws.Credentials{AccessKeyID: "AKIDEXAMPLE", SecretAccessKey: "wJalrXUtnFEMI/K7MDENG+bPxRfiCYEXAMPLEKEY"}
+
+func newTestClient(t *testing.T, handler http.HandlerFunc) *Client {
+	t.Helper()
+
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+
+	// retry straight away so the tests don't wait for the backoff
+	retryer := retry.NewStandard(func(o *retry.StandardOptions) {
+		o.Backoff = retry.BackoffDelayerFunc(func(int, error) (time.Duration, error) { return 0, nil })
+	})
+
+	return NewClient(testCredentials, "us-east-1", WithBaseURL(server.URL), WithRetryer(retryer))
+}
+
+func TestClient_DescribeInstances_Pagination(t *testing.T) {
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if !strings.HasPrefix(r.Header.Get("Authorization"), "AWS4-HMAC-SHA256 Credential=AKIDEXAMPLE/") {
+			t.Errorf("expected signed request, got %q", r.Header.Get("Authorization"))
+		}
+
+		if r.FormValue("Action") != "DescribeInstances" || r.FormValue("Version") != apiVersion {
+			t.Errorf("unexpected action %q version %q", r.FormValue("Action"), r.FormValue("Version"))
+		}
+
+		switch r.FormValue("NextToken") {
+		case "":
+			fmt.Fprint(w, `<DescribeInstancesResponse><reservationSet><item><instancesSet><item><instanceId>i-1</instanceId><tagSet><item><key>Name</key><value>one</value></item></tagSet></item></instancesSet></item></reservationSet><nextToken>page-2</nextToken></DescribeInstancesResponse>`)
+		case "page-2":
+			fmt.Fprint(w, `<DescribeInstancesResponse><reservationSet><item><instancesSet><item><instanceId>i-2</instanceId><tagSet><item><key>Name</key><value>two</value></item></tagSet></item></instancesSet></item></reservationSet></DescribeInstancesResponse>`)
+		default:
+			t.Errorf("unexpected token %q", r.FormValue("NextToken"))
+		}
+	})
+
+	instances, err := client.DescribeInstances(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(instances) != 2 || instances[0].Name() != "one" || instances[1].Name() != "two" {
+		t.Errorf("expected instances from both pages, got %+v", instances)
+	}
+}
+
+func TestClient_RunInstance(t *testing.T) {
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		expected := map[string]string{
+			"Action":                          "RunInstances",
+			"ImageId":                         "ami-123",
+			"InstanceType":                    "t3.micro",
+			"Placement.AvailabilityZone":      "us-east-1a",
+			"KeyName":                         "laptop",
+			"SecurityGroupId.1":               "sg-123",
+			"TagSpecification.1.ResourceType": "instance",
+			"TagSpecification.1.Tag.1.Key":    "Name",
+			"TagSpecification.1.Tag.1.Value":  "web-1",
+		}
+		for key, value := range expected {
+			if r.FormValue(key) != value {
+				t.Errorf("expected %s=%q, got %q", key, value, r.FormValue(key))
+			}
+		}
+
+		fmt.Fprint(w, `<RunInstancesResponse><instancesSet><item><instanceId>i-42</instanceId><instanceState><code>0</code><name>pending</name></instanceState></item></instancesSet></RunInstancesResponse>`)
+	})
+
+	instance, err := client.RunInstance(context.Background(), RunInstanceRequest{
+		Name:             "web-1",
+		ImageID:          "ami-123",
+		InstanceType:     "t3.micro",
+		AvailabilityZone: "us-east-1a",
+		KeyName:          "laptop",
+		SecurityGroupIDs: []string{"sg-123"},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if instance.InstanceID != "i-42" || instance.State.Name != "pending" {
+		t.Errorf("unexpected instance %+v", instance)
+	}
+}
+
+func TestClient_Error(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+	}{
+		{
+			name: "ec2 errors",
+			body: `<Response><Errors><Error><Code>AuthFailure</Code><Message>AWS was not able to validate the provided access credentials</Message></Error></Errors><RequestID>1</RequestID></Response>`,
+		},
+		{
+			name: "error response",
+			body: `<ErrorResponse><Error><Code>AuthFailure</Code><Message>AWS was not able to validate the provided access credentials</Message></Error></ErrorResponse>`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusUnauthorized)
+				fmt.Fprint(w, tt.body)
+			})
+
+			_, err := client.DescribeRegions(context.Background())
+
+			var apiError *Error
+			if !errors.As(err, &apiError) {
+				t.Fatalf("expected *Error, got %v", err)
+			}
+
+			if apiError.StatusCode != http.StatusUnauthorized || apiError.Code != "AuthFailure" {
+				t.Errorf("unexpected error %+v", apiError)
+			}
+		})
+	}
+}
+
+func TestClient_DescribeImages_Pagination(t *testing.T) {
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.FormValue("MaxResults") == "" || r.FormValue("Owner.1") != "amazon" {
+			t.Errorf("expected a paged request for amazon's images, got %v", r.Form)
+		}
+
+		switch r.FormValue("NextToken") {
+		case "":
+			fmt.Fprint(w, `<DescribeImagesResponse><imagesSet><item><imageId>ami-1</imageId></item></imagesSet><nextToken>page-2</nextToken></DescribeImagesResponse>`)
+		case "page-2":
+			fmt.Fprint(w, `<DescribeImagesResponse><imagesSet><item><imageId>ami-2</imageId></item></imagesSet></DescribeImagesResponse>`)
+		default:
+			t.Errorf("unexpected token %q", r.FormValue("NextToken"))
+		}
+	})
+
+	images, err := client.DescribeImages(context.Background(), []string{"amazon"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(images) != 2 || images[0].ImageID != "ami-1" || images[1].ImageID != "ami-2" {
+		t.Errorf("expected images from both pages, got %+v", images)
+	}
+}
+
+func TestClient_Retry(t *testing.T) {
+	tests := []struct {
+		name          string
+		status        int
+		code          string
+		expectedCalls int32
+	}{
+		{name: "server error", status: http.StatusServiceUnavailable, code: "Unavailable", expectedCalls: 2},
+		{name: "throttled", status: http.StatusBadRequest, code: "RequestLimitExceeded", expectedCalls: 2},
+		{name: "client error", status: http.StatusBadRequest, code: "InvalidParameterValue", expectedCalls: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var calls atomic.Int32
+
+			client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+				if calls.Add(1) == 1 {
+					w.WriteHeader(tt.status)
+					fmt.Fprintf(w, `<Response><Errors><Error><Code>%s</Code><Message>failed</Message></Error></Errors></Response>`, tt.code)
+					return
+				}
+				fmt.Fprint(w, `<DescribeRegionsResponse><regionInfo><item><regionName>us-east-1</regionName></item></regionInfo></DescribeRegionsResponse>`)
+			})
+
+			client.DescribeRegions(context.Background())
+
+			if calls.Load() != tt.expectedCalls {
+				t.Errorf("expected %d calls, got %d", tt.expectedCalls, calls.Load())
+			}
+		})
+	}
+}
@@ -0,0 +1,110 @@
+package api
+
+// Instance is an EC2 instance
+type Instance struct {
+	InstanceID       string        `xml:"instanceId"`
+	ImageID          string        `xml:"imageId"`
+	State            InstanceState `xml:"instanceState"`
+	InstanceType     string        `xml:"instanceType"`
+	KeyName          string        `xml:"keyName"`
+	LaunchTime       string        `xml:"launchTime"`
+	Placement        Placement     `xml:"placement"`
+	PrivateIPAddress string        `xml:"privateIpAddress"`
+	PublicIPAddress  string        `xml:"ipAddress"`
+	IPv6Address      string        `xml:"ipv6Address"`
+	VPCID            string        `xml:"vpcId"`
+	Tags             []Tag         `xml:"tagSet>item"`
+}
+
+// Name returns the value of the instance's Name tag
+func (i Instance) Name() string {
+	for _, tag := range i.Tags {
+		if tag.Key == "Name" {
+			return tag.Value
+		}
+	}
+	return ""
+}
+
+// InstanceState is the lifecycle state of an instance, e.g. pending or running
+type InstanceState struct {
+	Code int    `xml:"code"`
+	Name string `xml:"name"`
+}
+
+// Placement is where an instance is running
+type Placement struct {
+	AvailabilityZone string `xml:"availabilityZone"`
+}
+
+// Tag is a key value pair attached to a resource
+type Tag struct {
+	Key   string `xml:"key"`
+	Value string `xml:"value"`
+}
+
+// Region is an AWS region
+type Region struct {
+	RegionName     string `xml:"regionName"`
+	RegionEndpoint string `xml:"regionEndpoint"`
+	OptInStatus    string `xml:"optInStatus"`
+}
+
+// AvailabilityZone is an isolated location within a region
+type AvailabilityZone struct {
+	ZoneName   string `xml:"zoneName"`
+	ZoneID     string `xml:"zoneId"`
+	ZoneState  string `xml:"zoneState"`
+	RegionName string `xml:"regionName"`
+}
+
+// Image is an Amazon Machine Image (AMI)
+type Image struct {
+	ImageID      string `xml:"imageId"`
+	Name         string `xml:"name"`
+	Description  string `xml:"description"`
+	OwnerID      string `xml:"imageOwnerId"`
+	State        string `xml:"imageState"`
+	Architecture string `xml:"architecture"`
+	CreationDate string `xml:"creationDate"`
+}
+
+// InstanceType is the size of an instance
+type InstanceType struct {
+	InstanceType      string `xml:"instanceType"`
+	CurrentGeneration bool   `xml:"currentGeneration"`
+	VCPUs             int    `xml:"vCpuInfo>defaultVCpus"`
+	MemoryMiB         int    `xml:"memoryInfo>sizeInMiB"`
+}
+
+// KeyPair is an SSH key pair that can be used to log in to an instance
+type KeyPair struct {
+	KeyPairID      string `xml:"keyPairId"`
+	KeyName        string `xml:"keyName"`
+	KeyFingerprint string `xml:"keyFingerprint"`
+}
+
+// SecurityGroup is a set of firewall rules for instances
+type SecurityGroup struct {
+	GroupID     string `xml:"groupId"`
+	GroupName   string `xml:"groupName"`
+	Description string `xml:"groupDescription"`
+	VPCID       string `xml:"vpcId"`
+}
+
+// Filter narrows down the results of a Describe action
+type Filter struct {
+	Name   string
+	Values []string
+}
+
+// RunInstanceRequest describes a single instance to launch
+type RunInstanceRequest struct {
+	Name             string
+	ImageID          string
+	InstanceType     string
+	AvailabilityZone string
+	KeyName          string
+	SecurityGroupIDs []string
+	UserData         string
+}
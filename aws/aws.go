@@ -0,0 +1,473 @@
+// Package aws is used for interacting with Amazon EC2.
+// currently allows for creation, listing and destroying of EC2 instances
+package aws
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sort"
+
+	ec2 "github.com/Joel-Valentine/cogo/aws/api"
+	"github.com/Joel-Valentine/cogo/cloud"
+	"github.com/Joel-Valentine/cogo/credentials"
+	"github.com/Joel-Valentine/cogo/utils"
+	awssdk "github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/fatih/color"
+)
+
+// defaultRegion is used to look up the available regions when none is configured
+const defaultRegion = "us-east-1"
+
+// endpointEnvVars override the EC2 endpoint, e.g. http://localhost:4566 for LocalStack
+// These are the same variables the AWS CLI and SDKs read
+var endpointEnvVars = []string{"AWS_ENDPOINT_URL_EC2", "AWS_ENDPOINT_URL"}
+
+// regionEnvVars are checked for the region before asking for one
+var regionEnvVars = []string{"AWS_REGION", "AWS_DEFAULT_REGION"}
+
+// activeStates are the instance states shown by list, terminated instances are left out
+var activeStates = []string{"pending", "running", "shutting-down", "stopping", "stopped"}
+
+// imageFamily is a public AMI offered in the image step, only its newest image is shown
+type imageFamily struct {
+	owner   string
+	pattern string
+}
+
+// publicImageFamilies are offered alongside the images owned by the account
+var publicImageFamilies = []imageFamily{
+	{owner: "amazon", pattern: "al2023-ami-2023*-x86_64"},
+	{owner: "099720109477", pattern: "ubuntu/images/hvm-ssd-gp3/ubuntu-noble-24.04-amd64-server-*"},
+	{owner: "099720109477", pattern: "ubuntu/images/hvm-ssd/ubuntu-jammy-22.04-amd64-server-*"},
+	{owner: "136693071363", pattern: "debian-12-amd64-*"},
+}
+
+// Provider makes Amazon EC2 available to the commands through the cloud registry
+type Provider struct {
+	client *ec2.Client
+	region string
+}
+
+var _ cloud.Provider = &Provider{}
+var _ cloud.SecurityGroupProvider = &Provider{}
+var _ cloud.SingleSSHKeyProvider = &Provider{}
+var _ cloud.UserDataProvider = &Provider{}
+
+// NewProvider creates the AWS provider
+func NewProvider() *Provider {
+	return &Provider{}
+}
+
+// Key returns the identifier used to select AWS
+func (p *Provider) Key() string {
+	return "aws"
+}
+
+// Name returns the provider name
+func (p *Provider) Name() string {
+	return "AWS EC2"
+}
+
+// ServerNoun returns what EC2 calls its servers
+func (p *Provider) ServerNoun() string {
+	return "instance"
+}
+
+// HasCredentials returns true if an access key can be found without prompting for one
+func (p *Provider) HasCredentials(ctx context.Context) bool {
+	return credentials.AWS.HasToken(ctx)
+}
+
+// CredentialService returns where the AWS access key is kept
+func (p *Provider) CredentialService() credentials.Service {
+	return credentials.AWS
+}
+
+// SingleSSHKey returns true as instances are launched with a single key pair
+func (p *Provider) SingleSSHKey() bool {
+	return true
+}
+
+// UserDataLimit returns the largest user data an instance accepts, 16 KB before base64 encoding
+func (p *Provider) UserDataLimit() int {
+	return 16 * 1024
+}
+
+// Create asks the create wizard questions then launches the instance
+// waiting for it to be running if opts.Wait is set
+// The region step picks an availability zone, which also decides the region when given up front
+func (p *Provider) Create(ctx context.Context, opts cloud.CreateOptions) (*cloud.Server, error) {
+	if opts.Region != "" && p.client == nil {
+		p.region = regionOfZone(opts.Region)
+	}
+
+	answers, err := cloud.AskCreateQuestions(ctx, p, opts)
+
+	if err != nil || answers == nil {
+		return nil, err
+	}
+
+	client, err := p.getClient(ctx)
+
+	if err != nil {
+		return nil, err
+	}
+
+	request := ec2.RunInstanceRequest{
+		Name:             answers.Name,
+		ImageID:          answers.Image,
+		InstanceType:     answers.Size,
+		AvailabilityZone: answers.Region,
+		UserData:         answers.UserData,
+	}
+
+	if len(answers.SSHKeys) > 0 {
+		request.KeyName = answers.SSHKeys[0]
+	}
+
+	if answers.SecurityGroup != "" {
+		request.SecurityGroupIDs = []string{answers.SecurityGroup}
+	}
+
+	instance, err := client.RunInstance(ctx, request)
+
+	if err != nil {
+		return nil, err
+	}
+
+	server := instanceToServer(*instance)
+
+	// The Name tag is set by RunInstances but not always echoed back
+	if server.Name == instance.InstanceID {
+		server.Name = answers.Name
+	}
+
+	if !opts.Wait {
+		return &server, nil
+	}
+
+	color.Green("Instance [%s] was created!", server.Name)
+
+	active, err := cloud.WaitForServer(ctx, p.ServerNoun(), "running", opts.WaitTimeout, func(ctx context.Context) (*cloud.Server, error) {
+		latest, err := client.GetInstance(ctx, instance.InstanceID)
+
+		if err != nil {
+			return nil, err
+		}
+
+		server := instanceToServer(*latest)
+		return &server, nil
+	})
+
+	// the server exists even if waiting for it failed, so it is returned along with the error
+	if err != nil {
+		return &server, err
+	}
+
+	return active, nil
+}
+
+// List returns the instances in the region that haven't been terminated
+func (p *Provider) List(ctx context.Context, opts cloud.ListOptions) ([]cloud.Server, error) {
+	client, err := p.getClient(ctx)
+
+	if err != nil {
+		return nil, err
+	}
+
+	instances, err := client.DescribeInstances(ctx, ec2.Filter{Name: "instance-state-name", Values: activeStates})
+
+	if err != nil {
+		return nil, err
+	}
+
+	servers := []cloud.Server{}
+	for _, instance := range instances {
+		servers = append(servers, instanceToServer(instance))
+	}
+
+	return servers, nil
+}
+
+// Destroy will show the user a list of instances and ask the same three
+// confirmations as the DigitalOcean destroy before terminating the selected one
+func (p *Provider) Destroy(ctx context.Context, opts cloud.DestroyOptions) (*cloud.Server, error) {
+	servers, err := p.List(ctx, cloud.ListOptions{})
+
+	if err != nil {
+		return nil, err
+	}
+
+	selectedIndex, err := cloud.SelectServerIndex(servers, opts.ServerSelector, "Select instance to terminate")
+
+	if err != nil {
+		return nil, err
+	}
+
+	selectedServer := servers[selectedIndex]
+
+	shouldDestroy, err := cloud.ConfirmDestroy(selectedServer, p.ServerNoun(), opts)
+
+	if err != nil || !shouldDestroy {
+		return nil, err
+	}
+
+	client, err := p.getClient(ctx)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if err := client.TerminateInstance(ctx, selectedServer.ID); err != nil {
+		fmt.Printf("Something went wrong terminating instance: %s", err)
+		return nil, err
+	}
+
+	return &selectedServer, nil
+}
+
+// Regions returns the availability zones of the region instances are created in
+func (p *Provider) Regions(ctx context.Context) ([]utils.SelectItem, error) {
+	client, err := p.getClient(ctx)
+
+	if err != nil {
+		return nil, err
+	}
+
+	zones, err := client.DescribeAvailabilityZones(ctx)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return utils.ParseAWSAvailabilityZoneListResults(zones), nil
+}
+
+// Sizes returns the current generation x86 instance types, smallest first
+func (p *Provider) Sizes(ctx context.Context) ([]utils.SelectItem, error) {
+	client, err := p.getClient(ctx)
+
+	if err != nil {
+		return nil, err
+	}
+
+	instanceTypes, err := client.DescribeInstanceTypes(ctx,
+		ec2.Filter{Name: "current-generation", Values: []string{"true"}},
+		ec2.Filter{Name: "processor-info.supported-architecture", Values: []string{"x86_64"}},
+	)
+
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Slice(instanceTypes, func(i, j int) bool {
+		if instanceTypes[i].VCPUs != instanceTypes[j].VCPUs {
+			return instanceTypes[i].VCPUs < instanceTypes[j].VCPUs
+		}
+		if instanceTypes[i].MemoryMiB != instanceTypes[j].MemoryMiB {
+			return instanceTypes[i].MemoryMiB < instanceTypes[j].MemoryMiB
+		}
+		return instanceTypes[i].InstanceType < instanceTypes[j].InstanceType
+	})
+
+	return utils.ParseAWSInstanceTypeListResults(instanceTypes), nil
+}
+
+// Images returns the newest image of each public image family followed by the account's own images
+func (p *Provider) Images(ctx context.Context) ([]utils.SelectItem, error) {
+	client, err := p.getClient(ctx)
+
+	if err != nil {
+		return nil, err
+	}
+
+	available := ec2.Filter{Name: "state", Values: []string{"available"}}
+	images := []ec2.Image{}
+
+	for _, family := range publicImageFamilies {
+		familyImages, err := client.DescribeImages(ctx, []string{family.owner}, available, ec2.Filter{Name: "name", Values: []string{family.pattern}})
+
+		if err != nil {
+			return nil, err
+		}
+
+		if newest, ok := newestImage(familyImages); ok {
+			images = append(images, newest)
+		}
+	}
+
+	ownImages, err := client.DescribeImages(ctx, []string{"self"}, available)
+
+	if err != nil {
+		return nil, err
+	}
+
+	images = append(images, ownImages...)
+
+	return utils.ParseAWSImageListResults(images), nil
+}
+
+// SSHKeys returns the key pairs in the region
+func (p *Provider) SSHKeys(ctx context.Context) ([]utils.SelectItem, error) {
+	client, err := p.getClient(ctx)
+
+	if err != nil {
+		return nil, err
+	}
+
+	keyPairs, err := client.DescribeKeyPairs(ctx)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return utils.ParseAWSKeyPairListResults(keyPairs), nil
+}
+
+// SecurityGroups returns the security groups in the region
+func (p *Provider) SecurityGroups(ctx context.Context) ([]utils.SelectItem, error) {
+	client, err := p.getClient(ctx)
+
+	if err != nil {
+		return nil, err
+	}
+
+	securityGroups, err := client.DescribeSecurityGroups(ctx)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return utils.ParseAWSSecurityGroupListResults(securityGroups), nil
+}
+
+// getClient returns the API client, asking for the access key and region the first time it is needed
+func (p *Provider) getClient(ctx context.Context) (*ec2.Client, error) {
+	if p.client != nil {
+		return p.client, nil
+	}
+
+	key, err := credentials.AWS.GetAccessKey(ctx)
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to get access key: %w", err)
+	}
+
+	creds := awssdk.Credentials{
+		AccessKeyID:     key.ID,
+		SecretAccessKey: key.Secret,
+		SessionToken:    key.SessionToken,
+	}
+
+	opts := []ec2.Option{}
+	if endpoint := firstEnv(endpointEnvVars); endpoint != "" {
+		opts = append(opts, ec2.WithBaseURL(endpoint))
+	}
+
+	region := p.region
+	if region == "" {
+		region = firstEnv(regionEnvVars)
+	}
+
+	if region == "" {
+		region, err = askRegion(ctx, ec2.NewClient(creds, defaultRegion, opts...))
+
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	p.region = region
+	p.client = ec2.NewClient(creds, region, opts...)
+
+	return p.client, nil
+}
+
+// askRegion will ask the user which of the account's regions to use
+func askRegion(ctx context.Context, client *ec2.Client) (string, error) {
+	regions, err := client.DescribeRegions(ctx)
+
+	if err != nil {
+		return "", fmt.Errorf("failed to get region list: %w", err)
+	}
+
+	return utils.AskAndAnswerCustomSelect("AWS Region Select", utils.ParseAWSRegionListResults(regions))
+}
+
+// regionOfZone returns the region an availability zone is in, e.g. us-east-1 for us-east-1a
+// anything that isn't a zone name is assumed to be a region already
+func regionOfZone(zone string) string {
+	if len(zone) < 2 {
+		return zone
+	}
+
+	last := zone[len(zone)-1]
+	previous := zone[len(zone)-2]
+
+	if last >= 'a' && last <= 'z' && previous >= '0' && previous <= '9' {
+		return zone[:len(zone)-1]
+	}
+
+	return zone
+}
+
+// newestImage returns the image with the latest creation date
+func newestImage(images []ec2.Image) (ec2.Image, bool) {
+	if len(images) == 0 {
+		return ec2.Image{}, false
+	}
+
+	newest := images[0]
+	for _, image := range images[1:] {
+		// creation dates are ISO 8601 so compare as strings
+		if image.CreationDate > newest.CreationDate {
+			newest = image
+		}
+	}
+
+	return newest, true
+}
+
+// firstEnv returns the value of the first environment variable that is set
+func firstEnv(envVars []string) string {
+	for _, envVar := range envVars {
+		if value := os.Getenv(envVar); value != "" {
+			return value
+		}
+	}
+	return ""
+}
+
+// instanceToServer converts an EC2 instance into the provider independent format
+func instanceToServer(instance ec2.Instance) cloud.Server {
+	server := cloud.Server{
+		ID:          instance.InstanceID,
+		Name:        instance.Name(),
+		Status:      instance.State.Name,
+		Region:      instance.Placement.AvailabilityZone,
+		Size:        instance.InstanceType,
+		Image:       instance.ImageID,
+		PublicIPv4:  instance.PublicIPAddress,
+		PrivateIPv4: instance.PrivateIPAddress,
+		PublicIPv6:  instance.IPv6Address,
+		VPC:         instance.VPCID,
+		CreatedAt:   instance.LaunchTime,
+		Tags:        []string{},
+	}
+
+	// Instances don't have to be named, so fall back to the ID
+	if server.Name == "" {
+		server.Name = instance.InstanceID
+	}
+
+	for _, tag := range instance.Tags {
+		if tag.Key == "Name" {
+			continue
+		}
+		server.Tags = append(server.Tags, tag.Key+"="+tag.Value)
+	}
+	sort.Strings(server.Tags)
+
+	return server
+}
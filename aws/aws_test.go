@@ -0,0 +1,215 @@
+package aws
+
+import (
+	"context"
+	"encoding/xml"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+
+	ec2 "github.com/Joel-Valentine/cogo/aws/api"
+	"github.com/Joel-Valentine/cogo/cloud"
+	"github.com/Joel-Valentine/cogo/cloud/cloudtest"
+	awssdk "github.com/aws/aws-sdk-go-v2/aws"
+)
+
+type reservation struct {
+	Instances []ec2.Instance `xml:"instancesSet>item"`
+}
+
+// instanceID is the cloudtest ID of an EC2 instance ID, e.g. 1 for i-1
+func instanceID(id string) string {
+	return strings.TrimPrefix(id, "i-")
+}
+
+func newTestProvider(t *testing.T) (*Provider, *cloudtest.Servers[ec2.Instance]) {
+	t.Helper()
+
+	instances := &cloudtest.Servers[ec2.Instance]{}
+
+	// every EC2 action is posted to the same path
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.FormValue("Action") {
+		case "DescribeAvailabilityZones":
+			cloudtest.WriteXML(w, http.StatusOK, struct {
+				XMLName xml.Name               `xml:"DescribeAvailabilityZonesResponse"`
+				Zones   []ec2.AvailabilityZone `xml:"availabilityZoneInfo>item"`
+			}{Zones: []ec2.AvailabilityZone{{ZoneName: "us-east-1a"}, {ZoneName: "us-east-1b"}}})
+		case "DescribeInstanceTypes":
+			cloudtest.WriteXML(w, http.StatusOK, struct {
+				XMLName       xml.Name           `xml:"DescribeInstanceTypesResponse"`
+				InstanceTypes []ec2.InstanceType `xml:"instanceTypeSet>item"`
+			}{InstanceTypes: []ec2.InstanceType{{InstanceType: "t3.micro", VCPUs: 2, MemoryMiB: 1024}}})
+		case "DescribeImages":
+			images := []ec2.Image{}
+			if r.FormValue("Owner.1") == "amazon" {
+				images = []ec2.Image{
+					{ImageID: "ami-old", Name: "al2023-ami-2023.5-x86_64", CreationDate: "2024-06-01T00:00:00.000Z"},
+					{ImageID: "ami-new", Name: "al2023-ami-2023.6-x86_64", CreationDate: "2024-10-01T00:00:00.000Z"},
+				}
+			}
+			cloudtest.WriteXML(w, http.StatusOK, struct {
+				XMLName xml.Name    `xml:"DescribeImagesResponse"`
+				Images  []ec2.Image `xml:"imagesSet>item"`
+			}{Images: images})
+		case "DescribeKeyPairs":
+			cloudtest.WriteXML(w, http.StatusOK, struct {
+				XMLName  xml.Name      `xml:"DescribeKeyPairsResponse"`
+				KeyPairs []ec2.KeyPair `xml:"keySet>item"`
+			}{KeyPairs: []ec2.KeyPair{{KeyName: "laptop"}}})
+		case "DescribeSecurityGroups":
+			cloudtest.WriteXML(w, http.StatusOK, struct {
+				XMLName        xml.Name            `xml:"DescribeSecurityGroupsResponse"`
+				SecurityGroups []ec2.SecurityGroup `xml:"securityGroupInfo>item"`
+			}{SecurityGroups: []ec2.SecurityGroup{{GroupID: "sg-123", GroupName: "web"}}})
+		case "RunInstances":
+			instance := instances.Add(func(id int) ec2.Instance {
+				return ec2.Instance{
+					InstanceID:       fmt.Sprintf("i-%d", id),
+					ImageID:          r.FormValue("ImageId"),
+					State:            ec2.InstanceState{Name: "running"},
+					InstanceType:     r.FormValue("InstanceType"),
+					KeyName:          r.FormValue("KeyName"),
+					Placement:        ec2.Placement{AvailabilityZone: r.FormValue("Placement.AvailabilityZone")},
+					PrivateIPAddress: "10.0.0.5",
+					PublicIPAddress:  "203.0.113.10",
+					Tags:             []ec2.Tag{{Key: "Name", Value: r.FormValue("TagSpecification.1.Tag.1.Value")}},
+				}
+			})
+
+			cloudtest.WriteXML(w, http.StatusOK, struct {
+				XMLName   xml.Name       `xml:"RunInstancesResponse"`
+				Instances []ec2.Instance `xml:"instancesSet>item"`
+			}{Instances: []ec2.Instance{instance}})
+		case "DescribeInstances":
+			found := instances.List()
+			if id := r.FormValue("InstanceId.1"); id != "" {
+				found = []ec2.Instance{}
+				if instance, ok := instances.Get(instanceID(id)); ok {
+					found = append(found, instance)
+				}
+			}
+			cloudtest.WriteXML(w, http.StatusOK, struct {
+				XMLName      xml.Name      `xml:"DescribeInstancesResponse"`
+				Reservations []reservation `xml:"reservationSet>item"`
+			}{Reservations: []reservation{{Instances: found}}})
+		case "TerminateInstances":
+			instances.Delete(instanceID(r.FormValue("InstanceId.1")))
+			cloudtest.WriteXML(w, http.StatusOK, struct {
+				XMLName xml.Name `xml:"TerminateInstancesResponse"`
+			}{})
+		default:
+			w.WriteHeader(http.StatusBadRequest)
+			fmt.Fprintf(w, `<Response><Errors><Error><Code>InvalidAction</Code><Message>%s is not supported</Message></Error></Errors></Response>`, r.FormValue("Action"))
+		}
+	})
+
+	server := cloudtest.NewServer(t, handler)
+
+	creds := awssdk.Credentials{AccessKeyID: "AKIDEXAMPLE", SecretAccessKey: "secret"}
+
+	return &Provider{client: ec2.NewClient(creds, "us-east-1", ec2.WithBaseURL(server.URL))}, instances
+}
+
+func TestProvider_CreateListDestroy(t *testing.T) {
+	ctx := context.Background()
+	provider, instances := newTestProvider(t)
+
+	created, err := provider.Create(ctx, cloud.CreateOptions{
+		Name:          "web-1",
+		Image:         "ami-new",
+		Size:          "t3.micro",
+		Region:        "us-east-1b",
+		SSHKeys:       []string{"laptop"},
+		SecurityGroup: "sg-123",
+		Yes:           true,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error creating instance: %v", err)
+	}
+
+	if created.Name != "web-1" || created.Region != "us-east-1b" {
+		t.Errorf("unexpected created instance %+v", created)
+	}
+
+	servers, err := provider.List(ctx, cloud.ListOptions{})
+	if err != nil {
+		t.Fatalf("unexpected error listing instances: %v", err)
+	}
+
+	if len(servers) != 1 || servers[0].PublicIPv4 != "203.0.113.10" || servers[0].PrivateIPv4 != "10.0.0.5" {
+		t.Errorf("unexpected instances %+v", servers)
+	}
+
+	destroyed, err := provider.Destroy(ctx, cloud.DestroyOptions{
+		ServerSelector: cloud.ServerSelector{Name: "web-1"},
+		ConfirmName:    "web-1",
+		Yes:            true,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error terminating instance: %v", err)
+	}
+
+	if destroyed.ID != created.ID {
+		t.Errorf("expected instance %s to be terminated, got %s", created.ID, destroyed.ID)
+	}
+
+	if instances.Len() != 0 {
+		t.Errorf("expected no instances left, got %d", instances.Len())
+	}
+}
+
+func TestProvider_CreateInvalidOptions(t *testing.T) {
+	tests := []struct {
+		name string
+		opts cloud.CreateOptions
+	}{
+		{
+			name: "old image",
+			opts: cloud.CreateOptions{Name: "web-1", Image: "ami-old", Size: "t3.micro", Region: "us-east-1a", SSHKeys: []string{"laptop"}, SecurityGroup: "sg-123", Yes: true},
+		},
+		{
+			name: "more than one key pair",
+			opts: cloud.CreateOptions{Name: "web-1", Image: "ami-new", Size: "t3.micro", Region: "us-east-1a", SSHKeys: []string{"laptop", "laptop"}, SecurityGroup: "sg-123", Yes: true},
+		},
+		{
+			name: "unknown security group",
+			opts: cloud.CreateOptions{Name: "web-1", Image: "ami-new", Size: "t3.micro", Region: "us-east-1a", SSHKeys: []string{"laptop"}, SecurityGroup: "sg-999", Yes: true},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			provider, instances := newTestProvider(t)
+
+			if _, err := provider.Create(context.Background(), tt.opts); err == nil {
+				t.Error("expected error, got nil")
+			}
+
+			if instances.Len() != 0 {
+				t.Errorf("expected no instance to be created, got %d", instances.Len())
+			}
+		})
+	}
+}
+
+func TestRegionOfZone(t *testing.T) {
+	tests := []struct {
+		zone     string
+		expected string
+	}{
+		{zone: "us-east-1a", expected: "us-east-1"},
+		{zone: "eu-west-2c", expected: "eu-west-2"},
+		{zone: "eu-west-2", expected: "eu-west-2"},
+		{zone: "", expected: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.zone, func(t *testing.T) {
+			if got := regionOfZone(tt.zone); got != tt.expected {
+				t.Errorf("expected %q, got %q", tt.expected, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,81 @@
+package cloud
+
+import (
+	"fmt"
+	"strings"
+	"text/template"
+
+	"github.com/Joel-Valentine/cogo/utils"
+)
+
+// BulkServer is the name and region of one of several servers being created at once
+type BulkServer struct {
+	// Index counts up from 1
+	Index  int
+	Name   string
+	Region string
+
+	// UserData is the user data rendered with this server's name and region, empty for none
+	UserData string
+}
+
+// CreateResult is the outcome of creating one of several servers
+// Err is set when the server couldn't be created, or didn't become active when waiting for it
+// in which case Server is still set as the server exists
+type CreateResult struct {
+	Name   string
+	Region string
+	Server *Server
+	Err    error
+}
+
+// DefaultNameTemplate returns the name template used when only a name is given, e.g. worker-1, worker-2...
+func DefaultNameTemplate(name string) string {
+	return name + "-{{.Index}}"
+}
+
+// PlanBulkCreate works out the name and region of each of count servers
+// Names come from nameTemplate, a text/template given the server's .Index and .Region,
+// and the servers are spread round-robin across the regions
+func PlanBulkCreate(nameTemplate string, count int, regions []string) ([]BulkServer, error) {
+	if count < 1 {
+		return nil, fmt.Errorf("count must be at least 1, got %d", count)
+	}
+
+	if len(regions) == 0 {
+		return nil, fmt.Errorf("at least one region is needed")
+	}
+
+	nameTmpl, err := template.New("name").Option("missingkey=error").Parse(nameTemplate)
+
+	if err != nil {
+		return nil, fmt.Errorf("invalid name template %q: %w", nameTemplate, err)
+	}
+
+	plan := []BulkServer{}
+	seen := map[string]bool{}
+
+	for index := 1; index <= count; index++ {
+		server := BulkServer{Index: index, Region: regions[(index-1)%len(regions)]}
+
+		var name strings.Builder
+		if err := nameTmpl.Execute(&name, server); err != nil {
+			return nil, fmt.Errorf("failed to render name template %q: %w", nameTemplate, err)
+		}
+
+		server.Name = name.String()
+
+		if err := utils.ValidateDropletName(server.Name); err != nil {
+			return nil, fmt.Errorf("invalid name %q from template %q: %w", server.Name, nameTemplate, err)
+		}
+
+		if seen[server.Name] {
+			return nil, fmt.Errorf("name template %q gives more than one server the name %q, use {{.Index}} in it", nameTemplate, server.Name)
+		}
+		seen[server.Name] = true
+
+		plan = append(plan, server)
+	}
+
+	return plan, nil
+}
@@ -0,0 +1,101 @@
+package cloud
+
+import "testing"
+
+func TestPlanBulkCreate(t *testing.T) {
+	tests := []struct {
+		name            string
+		nameTemplate    string
+		count           int
+		regions         []string
+		expectedNames   []string
+		expectedRegions []string
+		expectError     bool
+	}{
+		{
+			name:            "single region",
+			nameTemplate:    "worker-{{.Index}}",
+			count:           3,
+			regions:         []string{"lon1"},
+			expectedNames:   []string{"worker-1", "worker-2", "worker-3"},
+			expectedRegions: []string{"lon1", "lon1", "lon1"},
+		},
+		{
+			name:            "round robin regions",
+			nameTemplate:    "worker-{{.Region}}-{{.Index}}",
+			count:           3,
+			regions:         []string{"lon1", "ams3"},
+			expectedNames:   []string{"worker-lon1-1", "worker-ams3-2", "worker-lon1-3"},
+			expectedRegions: []string{"lon1", "ams3", "lon1"},
+		},
+		{
+			name:            "default template",
+			nameTemplate:    DefaultNameTemplate("web"),
+			count:           2,
+			regions:         []string{"lon1"},
+			expectedNames:   []string{"web-1", "web-2"},
+			expectedRegions: []string{"lon1", "lon1"},
+		},
+		{
+			name:         "duplicate names",
+			nameTemplate: "worker",
+			count:        2,
+			regions:      []string{"lon1"},
+			expectError:  true,
+		},
+		{
+			name:         "invalid name",
+			nameTemplate: "worker {{.Index}}",
+			count:        2,
+			regions:      []string{"lon1"},
+			expectError:  true,
+		},
+		{
+			name:         "unknown field",
+			nameTemplate: "worker-{{.Size}}",
+			count:        2,
+			regions:      []string{"lon1"},
+			expectError:  true,
+		},
+		{
+			name:         "no regions",
+			nameTemplate: "worker-{{.Index}}",
+			count:        2,
+			expectError:  true,
+		},
+		{
+			name:         "zero count",
+			nameTemplate: "worker-{{.Index}}",
+			count:        0,
+			regions:      []string{"lon1"},
+			expectError:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			plan, err := PlanBulkCreate(tt.nameTemplate, tt.count, tt.regions)
+
+			if tt.expectError {
+				if err == nil {
+					t.Errorf("expected error, got plan %+v", plan)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if len(plan) != len(tt.expectedNames) {
+				t.Fatalf("expected %d servers, got %d", len(tt.expectedNames), len(plan))
+			}
+
+			for i, server := range plan {
+				if server.Index != i+1 || server.Name != tt.expectedNames[i] || server.Region != tt.expectedRegions[i] {
+					t.Errorf("unexpected server %d: %+v", i, server)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,116 @@
+// Package cloudtest has helpers for testing providers against an httptest stand-in of their API
+package cloudtest
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strconv"
+	"sync"
+	"testing"
+
+	"github.com/Joel-Valentine/cogo/config"
+)
+
+// NewServer starts a stand-in API serving the handler, which is closed when the test finishes
+// The wizard history is kept in a temporary config directory so tests don't touch the user's
+func NewServer(t *testing.T, handler http.Handler) *httptest.Server {
+	t.Helper()
+
+	t.Setenv(config.DirEnvVar, t.TempDir())
+
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+
+	return server
+}
+
+// WriteJSON writes v as the json response with the status code
+func WriteJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+// WriteXML writes v as the xml response with the status code
+func WriteXML(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "text/xml")
+	w.WriteHeader(status)
+	xml.NewEncoder(w).Encode(v)
+}
+
+// Servers are the servers a stand-in API has created, safe to use from its handlers
+// IDs are handed out from 1 in the order the servers are added
+type Servers[T any] struct {
+	mu      sync.Mutex
+	servers map[int]T
+	nextID  int
+}
+
+// Add creates a server with the next ID and keeps it
+func (s *Servers[T]) Add(create func(id int) T) T {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.servers == nil {
+		s.servers = map[int]T{}
+	}
+
+	s.nextID++
+	server := create(s.nextID)
+	s.servers[s.nextID] = server
+
+	return server
+}
+
+// Get returns the server with the ID, which can be given as a path value
+func (s *Servers[T]) Get(id string) (T, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	key, _ := strconv.Atoi(id)
+	server, ok := s.servers[key]
+
+	return server, ok
+}
+
+// Delete removes the server with the ID, returning false if there isn't one
+func (s *Servers[T]) Delete(id string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	key, _ := strconv.Atoi(id)
+	_, ok := s.servers[key]
+	delete(s.servers, key)
+
+	return ok
+}
+
+// List returns the servers in the order they were added
+func (s *Servers[T]) List() []T {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	ids := []int{}
+	for id := range s.servers {
+		ids = append(ids, id)
+	}
+	sort.Ints(ids)
+
+	servers := []T{}
+	for _, id := range ids {
+		servers = append(servers, s.servers[id])
+	}
+
+	return servers
+}
+
+// Len returns how many servers there are
+func (s *Servers[T]) Len() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return len(s.servers)
+}
@@ -0,0 +1,31 @@
+package cloud
+
+import (
+	"github.com/Joel-Valentine/cogo/history"
+	"github.com/fatih/color"
+)
+
+// OpenHistory returns the recent answers to the create wizard, or nil when opts.NoHistory is set
+// A history that can't be read is warned about and ignored, it never stops a server being created
+func OpenHistory(opts CreateOptions) *history.History {
+	if opts.NoHistory {
+		return nil
+	}
+
+	recent, err := history.Load()
+
+	if err != nil {
+		color.Yellow("Ignoring the wizard history: %s\n", err)
+		return nil
+	}
+
+	return recent
+}
+
+// SaveHistory saves the answers remembered in the history
+// A history that can't be saved is warned about, the server is created either way
+func SaveHistory(recent *history.History) {
+	if err := recent.Save(); err != nil {
+		color.Yellow("Failed to save the wizard history: %s\n", err)
+	}
+}
@@ -0,0 +1,27 @@
+package cloud
+
+import (
+	"errors"
+	"fmt"
+	"os"
+)
+
+// ErrNoSSHKey is returned when a server would only be reachable with its root password
+// and CreateOptions.ShowRootPassword wasn't set to be shown it
+var ErrNoSSHKey = errors.New("no SSH key was chosen, add one with --ssh-key or pass --show-root-password to be shown the root password")
+
+// RequireSSHKey returns ErrNoSSHKey when no SSH keys were chosen, unless the root password will be shown
+func RequireSSHKey(sshKeys []string, opts CreateOptions) error {
+	if len(sshKeys) == 0 && !opts.ShowRootPassword {
+		return ErrNoSSHKey
+	}
+
+	return nil
+}
+
+// PrintRootPassword prints the root password of a new server to stderr
+// so it isn't captured along with the rest of the output
+func PrintRootPassword(noun string, name string, password string) {
+	fmt.Fprintf(os.Stderr, "The root password of %s [%s] is: %s\n", noun, name, password)
+	fmt.Fprintf(os.Stderr, "It will not be shown again\n")
+}
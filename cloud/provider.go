@@ -0,0 +1,351 @@
+// Package cloud defines what cogo needs from a cloud provider and keeps a registry
+// of the providers that the create, list and destroy commands can dispatch to
+package cloud
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Joel-Valentine/cogo/credentials"
+	"github.com/Joel-Valentine/cogo/utils"
+)
+
+// Provider is implemented by each supported cloud provider
+type Provider interface {
+	// Key is the short identifier used to select the provider, e.g. with --provider
+	Key() string
+
+	// Name is the human readable name shown when asking for a provider
+	Name() string
+
+	// ServerNoun is what the provider calls a server (droplet, instance...)
+	ServerNoun() string
+
+	// HasCredentials returns true if credentials can be found without asking the user
+	HasCredentials(ctx context.Context) bool
+
+	// Create walks the user through creating a server, skipping any question answered in opts
+	// returns nil without an error if the user decided not to create it. When the server was created
+	// but waiting for it to become active failed, it is returned along with the error
+	Create(ctx context.Context, opts CreateOptions) (*Server, error)
+
+	// List returns all of the servers on the account
+	List(ctx context.Context, opts ListOptions) ([]Server, error)
+
+	// Destroy walks the user through destroying a server, skipping any question answered in opts
+	// returns nil without an error if the user decided not to destroy it
+	Destroy(ctx context.Context, opts DestroyOptions) (*Server, error)
+
+	// Regions, Sizes, Images and SSHKeys list what can be used to create a server
+	// The Value of each item is what should be passed in CreateOptions
+	Regions(ctx context.Context) ([]utils.SelectItem, error)
+	Sizes(ctx context.Context) ([]utils.SelectItem, error)
+	Images(ctx context.Context) ([]utils.SelectItem, error)
+	SSHKeys(ctx context.Context) ([]utils.SelectItem, error)
+}
+
+// CredentialProvider is implemented by providers that authenticate with an API token
+// stored through the credentials package, so it can be managed with cogo config
+type CredentialProvider interface {
+	CredentialService() credentials.Service
+}
+
+// SecurityGroupProvider is implemented by providers that place servers in security groups
+// The create wizard asks for one after the SSH key
+type SecurityGroupProvider interface {
+	SecurityGroups(ctx context.Context) ([]utils.SelectItem, error)
+}
+
+// SingleSSHKeyProvider is implemented by providers that can only add one SSH key to a server
+// The create wizard asks for a single key instead of letting several be selected
+type SingleSSHKeyProvider interface {
+	SingleSSHKey() bool
+}
+
+// UserDataProvider is implemented by providers that can pass cloud-init user data to new servers
+// UserDataLimit is the largest user data, in bytes, the provider accepts
+type UserDataProvider interface {
+	UserDataLimit() int
+}
+
+// VPCProvider is implemented by providers that can place servers in a VPC
+// The create wizard asks for one of the VPCs in the chosen region after the region
+type VPCProvider interface {
+	VPCs(ctx context.Context, region string) ([]utils.SelectItem, error)
+}
+
+// FeatureProvider is implemented by providers with optional extras that can be turned on for a server,
+// such as backups. Features returns them for the chosen size, with any extra cost in their names
+type FeatureProvider interface {
+	Features(ctx context.Context, size string) ([]utils.SelectItem, error)
+}
+
+// VolumeProvider is implemented by providers that can attach block storage volumes to new servers
+// Volumes returns the volumes in the region that aren't attached to a server yet
+type VolumeProvider interface {
+	Volumes(ctx context.Context, region string) ([]utils.SelectItem, error)
+}
+
+// TagProvider is implemented by providers that can tag servers when they are created
+// and list only the servers with a tag. The create wizard asks for tags after the SSH keys
+type TagProvider interface {
+	SupportsTags() bool
+}
+
+// PriceProvider is implemented by providers that know what their sizes cost, so the create
+// summary can estimate the monthly cost. SizePrice returns the hourly and monthly price of the size
+// and Surcharges returns what the extras in the summary, such as backups, add to the monthly cost
+// of every server when each costs monthly
+type PriceProvider interface {
+	SizePrice(ctx context.Context, size string) (hourly float64, monthly float64, err error)
+	Surcharges(summary CreateSummary, monthly float64) []Surcharge
+}
+
+// PowerProvider is implemented by providers that can power servers on and off
+// Power runs opts.Action on the server selected by opts and waits for it to finish
+// returns the server with its status once the action has finished
+type PowerProvider interface {
+	Power(ctx context.Context, opts PowerOptions) (*Server, error)
+}
+
+// ResizeProvider is implemented by providers that can change the size of a server
+// Resize asks for or validates the new size of the server selected by opts, then resizes it
+// returns nil without an error if the user decided not to resize it
+type ResizeProvider interface {
+	Resize(ctx context.Context, opts ResizeOptions) (*Server, error)
+}
+
+// RebuildProvider is implemented by providers that can reinstall a server from an image
+// Rebuild asks for or validates the image, confirms like Destroy, then rebuilds the server selected by opts
+// returns nil without an error if the user decided not to rebuild it
+type RebuildProvider interface {
+	Rebuild(ctx context.Context, opts RebuildOptions) (*Server, error)
+}
+
+// BulkCreateProvider is implemented by providers that can create several identical servers at once
+// CreateMany asks the create wizard questions once then creates opts.Count servers named from
+// opts.NameTemplate, returning the outcome for each. returns nil without an error if the user decided not to create them
+type BulkCreateProvider interface {
+	CreateMany(ctx context.Context, opts CreateOptions) ([]CreateResult, error)
+}
+
+// Server is the provider independent description of a server
+type Server struct {
+	ID           string   `json:"id" yaml:"id"`
+	Name         string   `json:"name" yaml:"name"`
+	Status       string   `json:"status" yaml:"status"`
+	Region       string   `json:"region" yaml:"region"`
+	Size         string   `json:"size" yaml:"size"`
+	Image        string   `json:"image" yaml:"image"`
+	PublicIPv4   string   `json:"public_ipv4" yaml:"public_ipv4"`
+	PrivateIPv4  string   `json:"private_ipv4" yaml:"private_ipv4"`
+	PublicIPv6   string   `json:"public_ipv6" yaml:"public_ipv6"`
+	Tags         []string `json:"tags" yaml:"tags"`
+	VPC          string   `json:"vpc" yaml:"vpc"`
+	CreatedAt    string   `json:"created_at" yaml:"created_at"`
+	PriceMonthly float64  `json:"price_monthly" yaml:"price_monthly"`
+}
+
+// FilterByTag returns the servers that have the tag, or all of them when tag is empty
+// for providers whose API can't list by tag
+func FilterByTag(servers []Server, tag string) []Server {
+	if tag == "" {
+		return servers
+	}
+
+	filtered := []Server{}
+	for _, server := range servers {
+		for _, serverTag := range server.Tags {
+			if serverTag == tag {
+				filtered = append(filtered, server)
+				break
+			}
+		}
+	}
+
+	return filtered
+}
+
+// CreateOptions holds answers to the create wizard that were given up front,
+// usually from command line flags. Any field left empty is asked for interactively
+type CreateOptions struct {
+	Name   string
+	Image  string
+	Size   string
+	Region string
+	Yes    bool
+
+	// SSHKeys are the IDs of the SSH keys to add, or fingerprints where the provider supports them
+	SSHKeys []string
+
+	// VPC is the ID of the VPC to put the server in, the region's default VPC is used when empty
+	// Only used by providers that implement VPCProvider
+	VPC string
+
+	// Features are the optional extras to turn on, only used by providers that implement FeatureProvider
+	// nil means they haven't been chosen yet, an empty list turns all of them off
+	Features []string
+
+	// Volumes are the IDs of existing volumes to attach and NewVolume is a volume to create and attach
+	// Only used by providers that implement VolumeProvider
+	Volumes   []string
+	NewVolume *NewVolume
+
+	// Tags are added to the server, only used by providers that implement TagProvider
+	Tags []string
+
+	// SecurityGroup is only used by providers that implement SecurityGroupProvider
+	SecurityGroup string
+
+	// ShowRootPassword lets a server be created without an SSH key, by printing its root password to stderr
+	// Only used by providers that give servers a root password, otherwise an SSH key is required
+	ShowRootPassword bool
+
+	// UserDataFile is a user data template to render and give to the server, see the userdata package
+	// Only used by providers that implement UserDataProvider
+	UserDataFile string
+
+	// Count is how many servers to create, with a provider that implements BulkCreateProvider
+	// Each is named from NameTemplate, see PlanBulkCreate, which defaults to DefaultNameTemplate(Name)
+	// and they are spread round-robin across Regions when it is set instead of Region
+	Count        int
+	NameTemplate string
+	Regions      []string
+
+	// Wait polls the new server until it is active and has an IP, for at most WaitTimeout
+	Wait        bool
+	WaitTimeout time.Duration
+
+	// NoHistory stops the wizard pinning the recent answers to the top of each list, and remembering these
+	NoHistory bool
+
+	// DryRun writes the create summary as JSON in place of asking if you are sure, and nothing is created
+	DryRun bool
+}
+
+// NewVolume describes a block storage volume to create and attach to a new server
+type NewVolume struct {
+	// Name defaults to the server's name followed by -data
+	Name    string `json:"name"`
+	SizeGiB int64  `json:"size_gib"`
+
+	// FilesystemType is ext4 or xfs, or empty to leave the volume unformatted
+	FilesystemType  string `json:"filesystem_type,omitempty"`
+	FilesystemLabel string `json:"filesystem_label,omitempty"`
+}
+
+// Validate checks that the volume can be created
+func (v NewVolume) Validate() error {
+	if err := utils.ValidateVolumeName(v.Name); err != nil {
+		return fmt.Errorf("invalid volume name %q: %w", v.Name, err)
+	}
+
+	if err := utils.ValidateVolumeSize(strconv.FormatInt(v.SizeGiB, 10)); err != nil {
+		return fmt.Errorf("invalid volume size %d: %w", v.SizeGiB, err)
+	}
+
+	if _, ok := utils.FilesystemLabelLimits[v.FilesystemType]; v.FilesystemType != "" && !ok {
+		return fmt.Errorf("invalid volume filesystem %q, must be ext4 or xfs", v.FilesystemType)
+	}
+
+	if err := utils.ValidateFilesystemLabel(v.FilesystemType, v.FilesystemLabel); err != nil {
+		return fmt.Errorf("invalid volume label %q: %w", v.FilesystemLabel, err)
+	}
+
+	return nil
+}
+
+// ListOptions narrows down which servers are listed
+type ListOptions struct {
+	// Tag only lists servers with this tag, only used by providers that implement TagProvider
+	Tag string
+}
+
+// ServerSelector picks an existing server by ID or name instead of asking the user to select one
+// When both are set they must refer to the same server
+type ServerSelector struct {
+	ID   string
+	Name string
+}
+
+// Power actions that can be run on a server with a PowerProvider
+const (
+	PowerOn       = "on"
+	PowerOff      = "off"
+	PowerCycle    = "cycle"
+	PowerShutdown = "shutdown"
+	PowerReboot   = "reboot"
+)
+
+// PowerActions lists every power action
+var PowerActions = []string{PowerOn, PowerOff, PowerCycle, PowerShutdown, PowerReboot}
+
+// PowerOptions holds the power action to run and the answers to its questions that were given up front
+type PowerOptions struct {
+	ServerSelector
+
+	// Action is one of PowerActions
+	Action string
+
+	// Timeout is how long to wait for the action to finish
+	Timeout time.Duration
+
+	// ShutdownTimeout is how long a graceful shutdown is given before the server is powered off instead
+	ShutdownTimeout time.Duration
+}
+
+// ValidatePowerAction will check whether action is one of PowerActions
+func ValidatePowerAction(action string) error {
+	if !slices.Contains(PowerActions, action) {
+		return fmt.Errorf("unknown power action %q, must be one of: %s", action, strings.Join(PowerActions, ", "))
+	}
+	return nil
+}
+
+// ResizeOptions holds answers to the resize questions that were given up front
+type ResizeOptions struct {
+	ServerSelector
+
+	// Size is the size to resize to, asked for when empty
+	Size string
+
+	// Disk grows the disk to the new size's disk as well, which can't be undone
+	// When it isn't set the user is asked, unless Yes is set in which case only the CPU and memory change
+	Disk bool
+
+	// Yes skips the y/n questions
+	Yes bool
+
+	// Timeout is how long to wait for each action of the resize to finish
+	Timeout time.Duration
+
+	// ShutdownTimeout is how long a graceful shutdown is given before the server is powered off instead
+	ShutdownTimeout time.Duration
+}
+
+// RebuildOptions holds answers to the rebuild questions that were given up front
+// ConfirmName replaces re-entering the server name and Yes skips the y/n questions
+type RebuildOptions struct {
+	ServerSelector
+
+	// Image is the image to rebuild from, as given to CreateOptions, asked for when empty
+	Image string
+
+	ConfirmName string
+	Yes         bool
+
+	// Timeout is how long to wait for the rebuild to finish
+	Timeout time.Duration
+}
+
+// DestroyOptions holds answers to the destroy questions that were given up front, usually from
+// command line flags. ConfirmName replaces re-entering the server name and Yes skips the y/n questions
+type DestroyOptions struct {
+	ServerSelector
+	ConfirmName string
+	Yes         bool
+}
@@ -0,0 +1,122 @@
+package cloud
+
+import "testing"
+
+func TestFilterByTag(t *testing.T) {
+	servers := []Server{
+		{ID: "1", Name: "web-1", Tags: []string{"web", "prod"}},
+		{ID: "2", Name: "web-2", Tags: []string{"web"}},
+		{ID: "3", Name: "db", Tags: []string{}},
+	}
+
+	tests := []struct {
+		name     string
+		tag      string
+		expected []string
+	}{
+		{
+			name:     "no tag",
+			tag:      "",
+			expected: []string{"1", "2", "3"},
+		},
+		{
+			name:     "shared tag",
+			tag:      "web",
+			expected: []string{"1", "2"},
+		},
+		{
+			name:     "single match",
+			tag:      "prod",
+			expected: []string{"1"},
+		},
+		{
+			name:     "no match",
+			tag:      "staging",
+			expected: []string{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			filtered := FilterByTag(servers, tt.tag)
+
+			if len(filtered) != len(tt.expected) {
+				t.Fatalf("expected %d servers, got %d", len(tt.expected), len(filtered))
+			}
+			for i, server := range filtered {
+				if server.ID != tt.expected[i] {
+					t.Errorf("expected server %s, got %s", tt.expected[i], server.ID)
+				}
+			}
+		})
+	}
+}
+
+func TestNewVolume_Validate(t *testing.T) {
+	tests := []struct {
+		name        string
+		volume      NewVolume
+		expectError bool
+	}{
+		{
+			name:   "ext4 with label",
+			volume: NewVolume{Name: "db-data", SizeGiB: 100, FilesystemType: "ext4", FilesystemLabel: "data"},
+		},
+		{
+			name:   "unformatted",
+			volume: NewVolume{Name: "db-data", SizeGiB: 1},
+		},
+		{
+			name:        "upper case name",
+			volume:      NewVolume{Name: "DB-data", SizeGiB: 100, FilesystemType: "ext4"},
+			expectError: true,
+		},
+		{
+			name:        "no size",
+			volume:      NewVolume{Name: "db-data", FilesystemType: "ext4"},
+			expectError: true,
+		},
+		{
+			name:        "unknown filesystem",
+			volume:      NewVolume{Name: "db-data", SizeGiB: 100, FilesystemType: "btrfs"},
+			expectError: true,
+		},
+		{
+			name:        "label too long for xfs",
+			volume:      NewVolume{Name: "db-data", SizeGiB: 100, FilesystemType: "xfs", FilesystemLabel: "postgres-data"},
+			expectError: true,
+		},
+		{
+			name:        "label without a filesystem",
+			volume:      NewVolume{Name: "db-data", SizeGiB: 100, FilesystemLabel: "data"},
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.volume.Validate()
+
+			if tt.expectError && err == nil {
+				t.Error("expected error, got nil")
+			}
+			if !tt.expectError && err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+		})
+	}
+}
+
+func TestValidatePowerAction(t *testing.T) {
+	for _, action := range PowerActions {
+		if err := ValidatePowerAction(action); err != nil {
+			t.Errorf("unexpected error for %s: %v", action, err)
+		}
+	}
+
+	for _, action := range []string{"", "restart", "ON"} {
+		if err := ValidatePowerAction(action); err == nil {
+			t.Errorf("expected error for %q, got nil", action)
+		}
+	}
+}
@@ -0,0 +1,79 @@
+package cloud
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/Joel-Valentine/cogo/utils"
+)
+
+// providers holds every registered provider in the order they were registered
+var providers []Provider
+
+// Register makes a provider available to the commands
+// It panics if a provider with the same key is already registered
+func Register(provider Provider) {
+	for _, registered := range providers {
+		if strings.EqualFold(registered.Key(), provider.Key()) {
+			panic(fmt.Sprintf("cloud: provider %q registered twice", provider.Key()))
+		}
+	}
+	providers = append(providers, provider)
+}
+
+// Providers returns every registered provider
+func Providers() []Provider {
+	return append([]Provider{}, providers...)
+}
+
+// Get returns the registered provider matching the given key or name, ignoring case
+func Get(key string) (Provider, error) {
+	for _, provider := range providers {
+		if strings.EqualFold(provider.Key(), key) || strings.EqualFold(provider.Name(), key) {
+			return provider, nil
+		}
+	}
+
+	return nil, fmt.Errorf("unknown provider %q, must be one of: %s", key, strings.Join(Keys(), ", "))
+}
+
+// Keys returns the key of every registered provider
+func Keys() []string {
+	keys := []string{}
+	for _, provider := range providers {
+		keys = append(keys, provider.Key())
+	}
+	return keys
+}
+
+// AskForProvider will return the provider matching key, or when key is empty ask the user
+// to select one of the providers that have credentials configured
+// If no provider has credentials yet every registered provider is offered
+func AskForProvider(ctx context.Context, key string) (Provider, error) {
+	if key != "" {
+		return Get(key)
+	}
+
+	supportedProviders := []utils.SelectItem{}
+
+	for _, provider := range providers {
+		if provider.HasCredentials(ctx) {
+			supportedProviders = append(supportedProviders, utils.SelectItem{Name: provider.Name(), Value: provider.Key()})
+		}
+	}
+
+	if len(supportedProviders) == 0 {
+		for _, provider := range providers {
+			supportedProviders = append(supportedProviders, utils.SelectItem{Name: provider.Name(), Value: provider.Key()})
+		}
+	}
+
+	selectedProvider, err := utils.AskForProvider(supportedProviders)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return Get(selectedProvider)
+}
@@ -0,0 +1,138 @@
+package cloud
+
+import (
+	"context"
+	"testing"
+
+	"github.com/Joel-Valentine/cogo/utils"
+)
+
+func TestGet(t *testing.T) {
+	defer withProviders(&fakeProvider{key: "do", name: "DigitalOcean"}, &fakeProvider{key: "other", name: "Other Cloud"})()
+
+	tests := []struct {
+		name        string
+		key         string
+		expectedKey string
+		expectError bool
+	}{
+		{
+			name:        "by key",
+			key:         "do",
+			expectedKey: "do",
+		},
+		{
+			name:        "by key ignoring case",
+			key:         "DO",
+			expectedKey: "do",
+		},
+		{
+			name:        "by name",
+			key:         "digitalocean",
+			expectedKey: "do",
+		},
+		{
+			name:        "unknown provider",
+			key:         "nope",
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			provider, err := Get(tt.key)
+
+			if tt.expectError {
+				if err == nil {
+					t.Error("expected error, got nil")
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if provider.Key() != tt.expectedKey {
+				t.Errorf("expected provider %q, got %q", tt.expectedKey, provider.Key())
+			}
+		})
+	}
+}
+
+func TestRegister_Duplicate(t *testing.T) {
+	defer withProviders(&fakeProvider{key: "do"})()
+
+	defer func() {
+		if recover() == nil {
+			t.Error("expected panic registering a duplicate provider")
+		}
+	}()
+
+	Register(&fakeProvider{key: "DO"})
+}
+
+func TestAskForProvider_OnlyProviderWithCredentials(t *testing.T) {
+	defer withProviders(
+		&fakeProvider{key: "without", name: "Without"},
+		&fakeProvider{key: "with", name: "With", hasCredentials: true},
+	)()
+
+	// Only one provider has credentials so it is selected without a prompt
+	provider, err := AskForProvider(context.Background(), "")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if provider.Key() != "with" {
+		t.Errorf("expected provider with credentials, got %q", provider.Key())
+	}
+}
+
+func TestAskForProvider_Key(t *testing.T) {
+	defer withProviders(&fakeProvider{key: "one"}, &fakeProvider{key: "two"})()
+
+	provider, err := AskForProvider(context.Background(), "two")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if provider.Key() != "two" {
+		t.Errorf("expected provider two, got %q", provider.Key())
+	}
+}
+
+// withProviders replaces the registered providers, returning a function that restores them
+func withProviders(registered ...Provider) func() {
+	original := providers
+	providers = registered
+	return func() { providers = original }
+}
+
+// fakeProvider is a test implementation of Provider
+type fakeProvider struct {
+	key            string
+	name           string
+	hasCredentials bool
+}
+
+func (f *fakeProvider) Key() string                             { return f.key }
+func (f *fakeProvider) Name() string                            { return f.name }
+func (f *fakeProvider) ServerNoun() string                      { return "server" }
+func (f *fakeProvider) HasCredentials(ctx context.Context) bool { return f.hasCredentials }
+
+func (f *fakeProvider) Create(ctx context.Context, opts CreateOptions) (*Server, error) {
+	return nil, nil
+}
+
+func (f *fakeProvider) List(ctx context.Context, opts ListOptions) ([]Server, error) {
+	return nil, nil
+}
+
+func (f *fakeProvider) Destroy(ctx context.Context, opts DestroyOptions) (*Server, error) {
+	return nil, nil
+}
+
+func (f *fakeProvider) Regions(ctx context.Context) ([]utils.SelectItem, error) { return nil, nil }
+func (f *fakeProvider) Sizes(ctx context.Context) ([]utils.SelectItem, error)   { return nil, nil }
+func (f *fakeProvider) Images(ctx context.Context) ([]utils.SelectItem, error)  { return nil, nil }
+func (f *fakeProvider) SSHKeys(ctx context.Context) ([]utils.SelectItem, error) { return nil, nil }
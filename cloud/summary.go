@@ -0,0 +1,176 @@
+package cloud
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/Joel-Valentine/cogo/utils"
+	"github.com/fatih/color"
+)
+
+// CreateSummary is what the create wizard is about to create and an estimate of what it will cost
+// The prices are left at zero for providers that don't know them
+type CreateSummary struct {
+	Provider      string     `json:"provider"`
+	Names         []string   `json:"names"`
+	Image         string     `json:"image"`
+	Size          string     `json:"size"`
+	Regions       []string   `json:"regions"`
+	VPC           string     `json:"vpc,omitempty"`
+	Features      []string   `json:"features,omitempty"`
+	SSHKeys       []string   `json:"ssh_keys"`
+	Tags          []string   `json:"tags,omitempty"`
+	SecurityGroup string     `json:"security_group,omitempty"`
+	Volumes       []string   `json:"volumes,omitempty"`
+	NewVolume     *NewVolume `json:"new_volume,omitempty"`
+
+	// PriceHourly and PriceMonthly are the price of one server
+	PriceHourly  float64 `json:"price_hourly,omitempty"`
+	PriceMonthly float64 `json:"price_monthly,omitempty"`
+
+	// Surcharges are the monthly cost of the extras across every server, such as backups
+	Surcharges []Surcharge `json:"surcharges,omitempty"`
+
+	// TotalMonthly is the projected monthly cost of every server along with the surcharges
+	TotalMonthly float64 `json:"total_monthly,omitempty"`
+}
+
+// Surcharge is an extra monthly cost on top of the price of the servers
+type Surcharge struct {
+	Name         string  `json:"name"`
+	PriceMonthly float64 `json:"price_monthly"`
+}
+
+// SetPrice sets the price of one server and the surcharges the provider priced for the summary,
+// then works out the total monthly cost
+func (s *CreateSummary) SetPrice(hourly float64, monthly float64, surcharges []Surcharge) {
+	count := float64(max(len(s.Names), 1))
+
+	s.PriceHourly = hourly
+	s.PriceMonthly = monthly
+	s.Surcharges = []Surcharge{}
+
+	total := monthly * count
+	for _, surcharge := range surcharges {
+		surcharge.PriceMonthly = roundCents(surcharge.PriceMonthly)
+		s.Surcharges = append(s.Surcharges, surcharge)
+		total += surcharge.PriceMonthly
+	}
+
+	s.TotalMonthly = roundCents(total)
+}
+
+// roundCents rounds a price to the nearest cent
+func roundCents(price float64) float64 {
+	return math.Round(price*100) / 100
+}
+
+// Print writes the summary as the review panel shown before asking if you are sure
+func (s CreateSummary) Print(w io.Writer, noun string) {
+	color.New(color.FgCyan).Fprintln(w, "Review")
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+
+	row := func(label string, value string) {
+		fmt.Fprintf(tw, "  %s:\t%s\n", label, value)
+	}
+
+	if len(s.Names) == 1 {
+		row("Name", s.Names[0])
+	} else {
+		row("Names", fmt.Sprintf("%s (%d %ss)", strings.Join(s.Names, ", "), len(s.Names), noun))
+	}
+
+	row("Image", s.Image)
+
+	if s.PriceMonthly > 0 {
+		row("Size", fmt.Sprintf("%s ($%s/hr, $%.2f/mo)", s.Size, strconv.FormatFloat(s.PriceHourly, 'f', -1, 64), s.PriceMonthly))
+	} else {
+		row("Size", s.Size)
+	}
+
+	if len(s.Regions) == 1 {
+		row("Region", s.Regions[0])
+	} else {
+		row("Regions", strings.Join(s.Regions, ", "))
+	}
+
+	if s.VPC != "" {
+		row("VPC", s.VPC)
+	}
+
+	if len(s.Features) > 0 {
+		row("Features", strings.Join(s.Features, ", "))
+	}
+
+	if len(s.SSHKeys) > 0 {
+		row("SSH keys", strings.Join(s.SSHKeys, ", "))
+	} else {
+		row("SSH keys", "none")
+	}
+
+	if len(s.Tags) > 0 {
+		row("Tags", strings.Join(s.Tags, ", "))
+	}
+
+	if s.SecurityGroup != "" {
+		row("Security group", s.SecurityGroup)
+	}
+
+	if len(s.Volumes) > 0 {
+		row("Volumes", strings.Join(s.Volumes, ", "))
+	}
+
+	if s.NewVolume != nil {
+		filesystem := s.NewVolume.FilesystemType
+		if filesystem == "" {
+			filesystem = "unformatted"
+		}
+
+		row("New volume", fmt.Sprintf("%s (%d GiB, %s)", s.NewVolume.Name, s.NewVolume.SizeGiB, filesystem))
+	}
+
+	if s.PriceMonthly > 0 {
+		for _, surcharge := range s.Surcharges {
+			row(strings.ToUpper(surcharge.Name[:1])+surcharge.Name[1:], fmt.Sprintf("+$%.2f/mo", surcharge.PriceMonthly))
+		}
+
+		if len(s.Names) > 1 {
+			row("Total", fmt.Sprintf("$%.2f/mo for %d %ss", s.TotalMonthly, len(s.Names), noun))
+		} else {
+			row("Total", fmt.Sprintf("$%.2f/mo", s.TotalMonthly))
+		}
+	}
+
+	tw.Flush()
+}
+
+// WriteJSON writes the summary to w as indented JSON
+func (s CreateSummary) WriteJSON(w io.Writer) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(s)
+}
+
+// ReviewCreate shows the summary then asks if you are sure, unless opts.Yes is set
+// With opts.DryRun the summary is written as JSON instead and nothing should be created
+// returns whether to go ahead and create
+func ReviewCreate(summary CreateSummary, noun string, opts CreateOptions) (bool, error) {
+	if opts.DryRun {
+		return false, summary.WriteJSON(os.Stdout)
+	}
+
+	summary.Print(os.Stdout, noun)
+
+	if opts.Yes {
+		return true, nil
+	}
+
+	return utils.AskYesNo("Are you sure? (y/n)")
+}
@@ -0,0 +1,121 @@
+package cloud
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestCreateSummary_SetPrice(t *testing.T) {
+	tests := []struct {
+		name       string
+		summary    CreateSummary
+		surcharges []Surcharge
+		expected   []Surcharge
+		total      float64
+	}{
+		{
+			name:     "one server",
+			summary:  CreateSummary{Names: []string{"web-1"}},
+			expected: []Surcharge{},
+			total:    6,
+		},
+		{
+			name:       "surcharges rounded to the cent",
+			summary:    CreateSummary{Names: []string{"web-1"}},
+			surcharges: []Surcharge{{Name: "backups", PriceMonthly: 1.2}, {Name: "volume", PriceMonthly: 0.333}},
+			expected:   []Surcharge{{Name: "backups", PriceMonthly: 1.2}, {Name: "volume", PriceMonthly: 0.33}},
+			total:      7.53,
+		},
+		{
+			name:       "several servers",
+			summary:    CreateSummary{Names: []string{"web-1", "web-2", "web-3"}},
+			surcharges: []Surcharge{{Name: "backups", PriceMonthly: 3.6}},
+			expected:   []Surcharge{{Name: "backups", PriceMonthly: 3.6}},
+			total:      21.6,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			summary := tt.summary
+			summary.SetPrice(0.00893, 6, tt.surcharges)
+
+			if len(summary.Surcharges) != len(tt.expected) {
+				t.Fatalf("expected surcharges %v, got %v", tt.expected, summary.Surcharges)
+			}
+			for index, surcharge := range tt.expected {
+				if summary.Surcharges[index] != surcharge {
+					t.Errorf("expected surcharge %v, got %v", surcharge, summary.Surcharges[index])
+				}
+			}
+			if summary.TotalMonthly != tt.total {
+				t.Errorf("expected total %v, got %v", tt.total, summary.TotalMonthly)
+			}
+		})
+	}
+}
+
+func TestCreateSummary_Print(t *testing.T) {
+	summary := CreateSummary{
+		Names:     []string{"web-1"},
+		Image:     "ubuntu-24-04-x64",
+		Size:      "s-1vcpu-1gb",
+		Regions:   []string{"lon1"},
+		Features:  []string{"backups"},
+		SSHKeys:   []string{"4001"},
+		NewVolume: &NewVolume{Name: "web-1-data", SizeGiB: 100, FilesystemType: "ext4"},
+	}
+	summary.SetPrice(0.00893, 6, []Surcharge{{Name: "backups", PriceMonthly: 1.2}, {Name: "volume", PriceMonthly: 10}})
+
+	var out bytes.Buffer
+	summary.Print(&out, "droplet")
+
+	for _, expected := range []string{
+		"Name:",
+		"s-1vcpu-1gb ($0.00893/hr, $6.00/mo)",
+		"Region:",
+		"web-1-data (100 GiB, ext4)",
+		"Backups:",
+		"+$1.20/mo",
+		"Volume:",
+		"+$10.00/mo",
+		"$17.20/mo",
+	} {
+		if !strings.Contains(out.String(), expected) {
+			t.Errorf("expected the summary to contain %q, got:\n%s", expected, out.String())
+		}
+	}
+
+	unpriced := CreateSummary{Names: []string{"web-1", "web-2"}, Size: "cx22", Regions: []string{"fsn1"}}
+
+	out.Reset()
+	unpriced.Print(&out, "server")
+
+	if strings.Contains(out.String(), "Total") || strings.Contains(out.String(), "$") {
+		t.Errorf("expected no prices when they aren't known, got:\n%s", out.String())
+	}
+	if !strings.Contains(out.String(), "web-1, web-2 (2 servers)") || !strings.Contains(out.String(), "none") {
+		t.Errorf("expected both names and no SSH keys, got:\n%s", out.String())
+	}
+}
+
+func TestCreateSummary_WriteJSON(t *testing.T) {
+	summary := CreateSummary{Provider: "do", Names: []string{"web-1"}, Size: "s-1vcpu-1gb", Regions: []string{"lon1"}, SSHKeys: []string{}}
+	summary.SetPrice(0.00893, 6, nil)
+
+	var out bytes.Buffer
+	if err := summary.WriteJSON(&out); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var decoded map[string]any
+	if err := json.Unmarshal(out.Bytes(), &decoded); err != nil {
+		t.Fatalf("summary is not valid json: %v", err)
+	}
+
+	if decoded["total_monthly"] != 6.0 || decoded["price_hourly"] != 0.00893 || decoded["provider"] != "do" {
+		t.Errorf("unexpected summary json: %s", out.String())
+	}
+}
@@ -0,0 +1,58 @@
+package cloud
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/Joel-Valentine/cogo/utils"
+)
+
+// PollInterval is how long to wait between checks on a server that is still being created
+var PollInterval = 5 * time.Second
+
+// WaitForServer polls get, showing a spinner, until the server has the active status
+// its IP may still be pending, as some providers assign it after the server is active
+// returns the latest server, or an error if that didn't happen within the timeout
+func WaitForServer(ctx context.Context, noun string, activeStatus string, timeout time.Duration, get func(context.Context) (*Server, error)) (*Server, error) {
+	spinner := utils.NewSpinner(fmt.Sprintf("Waiting for %s to become %s...", noun, activeStatus))
+	spinner.Start()
+	defer spinner.Stop()
+
+	return PollServer(ctx, noun, activeStatus, timeout, get)
+}
+
+// PollServer polls get until the server has the active status, like WaitForServer without the spinner
+// so that several servers can be waited for at once
+func PollServer(ctx context.Context, noun string, activeStatus string, timeout time.Duration, get func(context.Context) (*Server, error)) (*Server, error) {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	ticker := time.NewTicker(PollInterval)
+	defer ticker.Stop()
+
+	lastStatus := "unknown"
+
+	for {
+		server, err := get(ctx)
+
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil, fmt.Errorf("timed out after %s waiting for %s to become %s (last status: %s)", timeout, noun, activeStatus, lastStatus)
+			}
+			return nil, err
+		}
+
+		lastStatus = server.Status
+
+		if server.Status == activeStatus {
+			return server, nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, fmt.Errorf("timed out after %s waiting for %s to become %s (last status: %s)", timeout, noun, activeStatus, lastStatus)
+		case <-ticker.C:
+		}
+	}
+}
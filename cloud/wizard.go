@@ -0,0 +1,678 @@
+package cloud
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/Joel-Valentine/cogo/history"
+	"github.com/Joel-Valentine/cogo/userdata"
+	"github.com/Joel-Valentine/cogo/utils"
+	"github.com/fatih/color"
+	"github.com/manifoldco/promptui"
+)
+
+// CreateAnswers are the choices made in the create wizard
+// Each value is the Value of the SelectItem that was chosen from the provider's catalog
+type CreateAnswers struct {
+	Name   string
+	Image  string
+	Size   string
+	Region string
+
+	// VPC is only asked for when the provider implements VPCProvider, empty is the region's default
+	VPC string
+
+	// Features holds the optional extras that were turned on, only asked for when the provider
+	// implements FeatureProvider. nil if the question was skipped and the provider's defaults should be used
+	Features []string
+
+	// SSHKeys holds every SSH key that was selected
+	SSHKeys []string
+
+	// Tags are only asked for when the provider implements TagProvider
+	Tags []string
+
+	// SecurityGroup is only asked for when the provider implements SecurityGroupProvider
+	SecurityGroup string
+
+	// UserData is the rendered user data, only asked for when the provider implements UserDataProvider
+	UserData string
+}
+
+// AskCreateQuestions will ask the user the same series of questions as the DigitalOcean
+// create wizard, using the lists from the provider's catalog
+// 1. Asks what name you would like for the server
+// 2. Asks what image you would like to use on the server
+// 3. Asks what size you would like the server to be
+// 4. Asks what region you want the server to be hosted in
+// 5. Asks which VPC in that region the server should be in, if the provider has them
+// 6. Asks which optional features to turn on, if the provider has them
+// 7. Asks which SSH Keys you would like to use to access the server
+// 8. Asks which tags to add to the server, if the provider supports tags
+// 9. Asks what security group the server should be in, if the provider has them
+// 10. Asks which user data template to use, if the provider supports user data and there are templates
+// 11. Shows a summary of the server and its estimated cost, then asks if you are sure with a y/n answer
+// Any answer already given in opts is validated and its question is skipped
+// The recent images, sizes, regions and SSH keys are pinned to the top of their lists, and the
+// answers are remembered once confirmed, unless opts.NoHistory is set
+// returns nil without an error if the user decided not to create the server, or opts.DryRun is set
+func AskCreateQuestions(ctx context.Context, provider Provider, opts CreateOptions) (*CreateAnswers, error) {
+	noun := provider.ServerNoun()
+
+	name, err := askName(strings.ToUpper(noun[:1])+noun[1:]+" Name", opts.Name)
+
+	if err != nil {
+		return nil, err
+	}
+
+	recent := OpenHistory(opts)
+	key := provider.Key()
+
+	image, err := askOrValidate(ctx, "image", "Image Select", opts.Image, recent.Recent(key, history.Image), provider.Images)
+
+	if err != nil {
+		return nil, err
+	}
+
+	size, err := askOrValidate(ctx, "size", "Size Select", opts.Size, recent.Recent(key, history.Size), provider.Sizes)
+
+	if err != nil {
+		return nil, err
+	}
+
+	region, err := askOrValidate(ctx, "region", "Region Select", opts.Region, recent.Recent(key, history.Region), provider.Regions)
+
+	if err != nil {
+		return nil, err
+	}
+
+	vpc := ""
+	if vpcProvider, ok := provider.(VPCProvider); ok {
+		vpc, err = AskVPC(ctx, opts, region, vpcProvider.VPCs)
+
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	var features []string
+	if featureProvider, ok := provider.(FeatureProvider); ok {
+		features, err = AskFeatures(ctx, opts, size, featureProvider.Features)
+
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	sshKeys, err := askOrValidateSSHKeys(ctx, provider, opts.SSHKeys, recent.Recent(key, history.SSHKeys))
+
+	if err != nil {
+		return nil, err
+	}
+
+	tags := []string{}
+	if _, ok := provider.(TagProvider); ok {
+		tags, err = AskTags(opts)
+
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	securityGroup := ""
+	if securityGroupProvider, ok := provider.(SecurityGroupProvider); ok {
+		securityGroup, err = askOrValidate(ctx, "security group", "Security Group Select", opts.SecurityGroup, nil, securityGroupProvider.SecurityGroups)
+
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	userData := ""
+	if userDataProvider, ok := provider.(UserDataProvider); ok {
+		vars := userdata.Vars{Name: name, Region: region, Size: size, Image: image}
+
+		userData, err = AskUserData(opts, vars, userDataProvider.UserDataLimit())
+
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	summary := CreateSummary{
+		Provider:      key,
+		Names:         []string{name},
+		Image:         image,
+		Size:          size,
+		Regions:       []string{region},
+		VPC:           vpc,
+		Features:      features,
+		SSHKeys:       sshKeys,
+		Tags:          tags,
+		SecurityGroup: securityGroup,
+	}
+
+	if priceProvider, ok := provider.(PriceProvider); ok {
+		hourly, monthly, err := priceProvider.SizePrice(ctx, size)
+
+		if err != nil {
+			return nil, fmt.Errorf("failed to get the price of %s: %w", size, err)
+		}
+
+		summary.SetPrice(hourly, monthly, priceProvider.Surcharges(summary, monthly))
+	}
+
+	shouldCreate, err := ReviewCreate(summary, noun, opts)
+
+	if err != nil || !shouldCreate {
+		return nil, err
+	}
+
+	recent.Remember(key, history.Image, image)
+	recent.Remember(key, history.Size, size)
+	recent.Remember(key, history.Region, region)
+	recent.Remember(key, history.SSHKeys, sshKeys...)
+	SaveHistory(recent)
+
+	return &CreateAnswers{
+		Name:   name,
+		Image:  image,
+		Size:   size,
+		Region: region,
+		VPC:    vpc,
+
+		Features:      features,
+		SSHKeys:       sshKeys,
+		Tags:          tags,
+		SecurityGroup: securityGroup,
+		UserData:      userData,
+	}, nil
+}
+
+// AskVPC will check that the VPC given in opts is in the region, or ask the user to select one
+// of the region's VPCs. The question is skipped when opts.Yes is set or the region only has
+// its default VPC. returns an empty ID when the region's default VPC should be used
+func AskVPC(ctx context.Context, opts CreateOptions, region string, listFunc func(context.Context, string) ([]utils.SelectItem, error)) (string, error) {
+	list, err := listFunc(ctx, region)
+
+	if err != nil {
+		return "", fmt.Errorf("failed to get vpc list: %w", err)
+	}
+
+	if opts.VPC != "" {
+		if _, ok := utils.FindSelectItem(list, opts.VPC); !ok {
+			return "", fmt.Errorf("vpc %q is not available in %s", opts.VPC, region)
+		}
+
+		return opts.VPC, nil
+	}
+
+	if opts.Yes || len(list) <= 1 {
+		return "", nil
+	}
+
+	return utils.AskAndAnswerCustomSelect("VPC Select", list)
+}
+
+// AskFeatures will check the features given in opts are offered, or ask the user to toggle which
+// of them to turn on. The question is skipped when opts.Yes is set
+// returns nil when the question was skipped so the provider's defaults are used
+func AskFeatures(ctx context.Context, opts CreateOptions, size string, listFunc func(context.Context, string) ([]utils.SelectItem, error)) ([]string, error) {
+	list, err := listFunc(ctx, size)
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to get feature list: %w", err)
+	}
+
+	if opts.Features != nil {
+		for _, feature := range opts.Features {
+			if _, ok := utils.FindSelectItem(list, feature); !ok {
+				return nil, fmt.Errorf("feature %q is not available", feature)
+			}
+		}
+
+		return opts.Features, nil
+	}
+
+	if opts.Yes {
+		return nil, nil
+	}
+
+	return utils.AskAndAnswerCustomMultiSelect("Optional Features", list)
+}
+
+// VolumeAnswers are the volumes chosen in the attach volume step
+type VolumeAnswers struct {
+	// Existing are the IDs of unattached volumes to attach
+	Existing []string
+
+	// New is a volume to create and attach, nil if there isn't one
+	New *NewVolume
+}
+
+// AskVolumes will check the volumes given in opts can be attached, or ask the user whether to attach
+// one of the region's unattached volumes, create a new one or have none. A new volume is named after
+// the server by default. The question is skipped when opts.Yes is set
+func AskVolumes(ctx context.Context, opts CreateOptions, serverName string, region string, listFunc func(context.Context, string) ([]utils.SelectItem, error)) (*VolumeAnswers, error) {
+	list, err := listFunc(ctx, region)
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to get volume list: %w", err)
+	}
+
+	answers := &VolumeAnswers{Existing: []string{}}
+
+	if len(opts.Volumes) > 0 || opts.NewVolume != nil {
+		for _, volume := range opts.Volumes {
+			if _, ok := utils.FindSelectItem(list, volume); !ok {
+				return nil, fmt.Errorf("volume %q is not an unattached volume in %s", volume, region)
+			}
+		}
+
+		if opts.NewVolume != nil {
+			newVolume := *opts.NewVolume
+			if newVolume.Name == "" {
+				newVolume.Name = defaultVolumeName(serverName)
+			}
+
+			if err := newVolume.Validate(); err != nil {
+				return nil, err
+			}
+
+			answers.New = &newVolume
+		}
+
+		answers.Existing = opts.Volumes
+
+		return answers, nil
+	}
+
+	if opts.Yes {
+		return answers, nil
+	}
+
+	choices := append([]utils.SelectItem{{Name: "None", Value: "none"}}, list...)
+	choices = append(choices, utils.SelectItem{Name: "Create a new volume", Value: "new"})
+
+	selected, err := utils.AskAndAnswerCustomSelect("Attach Volume", choices)
+
+	if err != nil {
+		return nil, err
+	}
+
+	switch selected {
+	case "none":
+	case "new":
+		answers.New, err = askNewVolume(serverName)
+	default:
+		answers.Existing = []string{selected}
+	}
+
+	return answers, err
+}
+
+// defaultVolumeName returns the name given to a new volume when none is chosen
+func defaultVolumeName(serverName string) string {
+	return strings.ToLower(serverName) + "-data"
+}
+
+// askNewVolume asks for the name, size, filesystem and label of a volume to create
+func askNewVolume(serverName string) (*NewVolume, error) {
+	promptName := promptui.Prompt{
+		Label:    "Volume Name",
+		Default:  defaultVolumeName(serverName),
+		Validate: utils.ValidateVolumeName,
+	}
+
+	name, err := promptName.Run()
+
+	if err != nil {
+		return nil, err
+	}
+
+	promptSize := promptui.Prompt{
+		Label:    "Volume Size (GiB)",
+		Default:  "100",
+		Validate: utils.ValidateVolumeSize,
+	}
+
+	sizeInput, err := promptSize.Run()
+
+	if err != nil {
+		return nil, err
+	}
+
+	size, err := strconv.ParseInt(sizeInput, 10, 64)
+
+	if err != nil {
+		return nil, err
+	}
+
+	filesystems := []utils.SelectItem{{Name: "ext4", Value: "ext4"}, {Name: "xfs", Value: "xfs"}, {Name: "Unformatted", Value: "none"}}
+
+	filesystem, err := utils.AskAndAnswerCustomSelect("Volume Filesystem", filesystems)
+
+	if err != nil {
+		return nil, err
+	}
+
+	volume := &NewVolume{Name: name, SizeGiB: size}
+
+	if filesystem == "none" {
+		return volume, nil
+	}
+
+	volume.FilesystemType = filesystem
+
+	promptLabel := promptui.Prompt{
+		Label: "Filesystem Label (leave empty for none)",
+		Validate: func(input string) error {
+			return utils.ValidateFilesystemLabel(filesystem, input)
+		},
+	}
+
+	volume.FilesystemLabel, err = promptLabel.Run()
+
+	if err != nil {
+		return nil, err
+	}
+
+	return volume, nil
+}
+
+// AskTags will validate the tags given in opts, or ask the user for a comma separated list of tags
+// The question is skipped when opts.Yes is set. returns an empty list if no tags were given
+func AskTags(opts CreateOptions) ([]string, error) {
+	if len(opts.Tags) > 0 {
+		if err := utils.ValidateTags(strings.Join(opts.Tags, ",")); err != nil {
+			return nil, err
+		}
+
+		return utils.ParseTags(strings.Join(opts.Tags, ",")), nil
+	}
+
+	if opts.Yes {
+		return []string{}, nil
+	}
+
+	promptTags := promptui.Prompt{
+		Label:    "Tags (comma separated, leave empty for none)",
+		Validate: utils.ValidateTags,
+	}
+
+	input, err := promptTags.Run()
+
+	if err != nil {
+		return nil, err
+	}
+
+	return utils.ParseTags(input), nil
+}
+
+// AskUserData will render the user data file given in opts, or ask the user to pick one of
+// their templates when there are any. The template question is skipped when opts.Yes is set
+// returns empty user data if none was chosen
+func AskUserData(opts CreateOptions, vars userdata.Vars, limit int) (string, error) {
+	file, err := AskUserDataFile(opts)
+
+	if err != nil || file == "" {
+		return "", err
+	}
+
+	return userdata.Load(file, vars, limit)
+}
+
+// AskUserDataFile returns the user data file given in opts, or asks the user to pick one of
+// their templates when there are any, so it can be rendered for each of several servers
+// The template question is skipped when opts.Yes is set. returns an empty path if none was chosen
+func AskUserDataFile(opts CreateOptions) (string, error) {
+	if opts.UserDataFile != "" {
+		return opts.UserDataFile, nil
+	}
+
+	if opts.Yes {
+		return "", nil
+	}
+
+	templates, err := userdata.Templates()
+
+	if err != nil {
+		return "", fmt.Errorf("failed to get user data templates: %w", err)
+	}
+
+	if len(templates) == 0 {
+		return "", nil
+	}
+
+	list := append([]utils.SelectItem{{Name: "None", Value: "none"}}, templates...)
+
+	selected, err := utils.AskAndAnswerCustomSelect("User Data Select", list)
+
+	if err != nil || selected == "none" {
+		return "", err
+	}
+
+	return selected, nil
+}
+
+// askName will validate the given name, or ask the user for one when it is empty
+func askName(label string, name string) (string, error) {
+	if name != "" {
+		if err := utils.ValidateDropletName(name); err != nil {
+			return "", fmt.Errorf("invalid name %q: %w", name, err)
+		}
+
+		return name, nil
+	}
+
+	promptName := promptui.Prompt{
+		Label:    label,
+		Validate: utils.ValidateDropletName,
+	}
+
+	return promptName.Run()
+}
+
+// askOrValidate will check that the given value is in the list returned by listFunc
+// or ask the user to select one from the list when it is empty, with the recent values pinned to the top
+// kind is only used to describe what was invalid in the returned error
+func askOrValidate(ctx context.Context, kind string, title string, given string, recent []string, listFunc func(context.Context) ([]utils.SelectItem, error)) (string, error) {
+	list, err := listFunc(ctx)
+
+	if err != nil {
+		return "", fmt.Errorf("failed to get %s list: %w", kind, err)
+	}
+
+	if given != "" {
+		if _, ok := utils.FindSelectItem(list, given); !ok {
+			return "", fmt.Errorf("%s %q is not available", kind, given)
+		}
+
+		return given, nil
+	}
+
+	if len(list) == 0 {
+		// Nothing to choose from, e.g. an account without any SSH keys
+		color.Yellow("No %ss available, skipping\n", kind)
+		return "", nil
+	}
+
+	return utils.AskAndAnswerCustomSelect(title, utils.PinRecent(list, recent))
+}
+
+// askOrValidateSSHKeys will check that every given SSH key is in the provider's list
+// or ask the user to select any number of them when none are given, with the recent keys pinned and selected
+// Providers that implement SingleSSHKeyProvider are asked for one key instead
+func askOrValidateSSHKeys(ctx context.Context, provider Provider, given []string, recent []string) ([]string, error) {
+	_, single := provider.(SingleSSHKeyProvider)
+
+	if single && len(given) > 1 {
+		return nil, fmt.Errorf("%s can only add one ssh key", provider.Name())
+	}
+
+	list, err := provider.SSHKeys(ctx)
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to get ssh key list: %w", err)
+	}
+
+	if len(given) > 0 {
+		for _, key := range given {
+			if _, ok := utils.FindSelectItem(list, key); !ok {
+				return nil, fmt.Errorf("ssh key %q is not available", key)
+			}
+		}
+
+		return given, nil
+	}
+
+	if len(list) == 0 {
+		// Nothing to choose from, e.g. an account without any SSH keys
+		color.Yellow("No ssh keys available, skipping\n")
+		return []string{}, nil
+	}
+
+	if single {
+		key, err := utils.AskAndAnswerCustomSelect("SSH Key Select", utils.PinRecent(list, recent))
+
+		if err != nil {
+			return nil, err
+		}
+
+		return []string{key}, nil
+	}
+
+	return utils.AskAndAnswerRecentMultiSelect("SSH Key Select", list, recent)
+}
+
+// SelectServerIndex will find the server described by selector in the list of servers
+// if the selector is empty the user is asked to select one with the given label
+// returns the index of the server in servers
+func SelectServerIndex(servers []Server, selector ServerSelector, label string) (int, error) {
+	if selector.ID == "" && selector.Name == "" {
+		selectItems := []utils.SelectItem{}
+		for _, server := range servers {
+			selectItems = append(selectItems, utils.SelectItem{Name: server.Name, Value: server.ID})
+		}
+
+		selectPrompt := utils.CreateCustomSelectPrompt(label, selectItems)
+
+		selectedIndex, _, err := selectPrompt.Run()
+
+		if err != nil {
+			return -1, err
+		}
+
+		return selectedIndex, nil
+	}
+
+	selectedIndex := -1
+
+	for index, server := range servers {
+		if selector.ID != "" && server.ID != selector.ID {
+			continue
+		}
+		if selector.Name != "" && server.Name != selector.Name {
+			continue
+		}
+		if selectedIndex != -1 {
+			return -1, fmt.Errorf("more than one server is named %q, select it by ID instead", selector.Name)
+		}
+		selectedIndex = index
+	}
+
+	if selectedIndex == -1 {
+		if selector.ID != "" && selector.Name != "" {
+			return -1, fmt.Errorf("no server found with ID %s and name %q", selector.ID, selector.Name)
+		}
+		if selector.ID != "" {
+			return -1, fmt.Errorf("no server found with ID %s", selector.ID)
+		}
+		return -1, fmt.Errorf("no server found with name %q", selector.Name)
+	}
+
+	return selectedIndex, nil
+}
+
+// ConfirmDestroy will ask the same three questions as the DigitalOcean destroy
+// 1. An are you sure y/n question
+// 2. Re-entering the name of the server, which must match exactly
+// 3. Showing the details of the server and asking are you really really sure y/n
+// ConfirmName in opts replaces re-entering the name and Yes skips the y/n questions
+// returns false without an error if the user decided not to destroy the server
+func ConfirmDestroy(server Server, noun string, opts DestroyOptions) (bool, error) {
+	details := fmt.Sprintf("Name: %s\nSize: %s\nRegion: %s\nImage: %s\nIP: %s", server.Name, server.Size, server.Region, server.Image, server.PublicIPv4)
+
+	return confirmWithName(server, noun, "delete", fmt.Sprintf("%s WILL BE DELETED FOREVER", strings.ToUpper(noun)), details, opts.ConfirmName, opts.Yes)
+}
+
+// ConfirmRebuild will ask the same three questions as ConfirmDestroy before the server is rebuilt from image,
+// showing the image it will be rebuilt from along with its details
+// ConfirmName in opts replaces re-entering the name and Yes skips the y/n questions
+// returns false without an error if the user decided not to rebuild the server
+func ConfirmRebuild(server Server, noun string, image string, opts RebuildOptions) (bool, error) {
+	details := fmt.Sprintf("Name: %s\nSize: %s\nRegion: %s\nImage: %s\nNew image: %s\nIP: %s", server.Name, server.Size, server.Region, server.Image, image, server.PublicIPv4)
+
+	return confirmWithName(server, noun, "rebuild", fmt.Sprintf("ALL DATA ON THE %s WILL BE LOST", strings.ToUpper(noun)), details, opts.ConfirmName, opts.Yes)
+}
+
+// confirmWithName asks if you are sure, has the server's name re-entered, which must match exactly,
+// then shows the details and asks if you are really really sure. verb is what is being done to the server
+// and warning is shown when asking for the name. confirmName replaces re-entering the name and yes skips the y/n questions
+// returns false without an error if the user decided not to go ahead
+func confirmWithName(server Server, noun string, verb string, warning string, details string, confirmName string, yes bool) (bool, error) {
+	if !yes {
+		areYouSure, err := utils.AskYesNo("Are you sure? (y/n)")
+
+		if err != nil {
+			return false, err
+		}
+
+		if !areYouSure {
+			fmt.Printf("You decided not to %s this %s\n", verb, noun)
+			return false, nil
+		}
+	}
+
+	enteredName := confirmName
+
+	if enteredName == "" {
+		promptReEnterName := promptui.Prompt{
+			Label: fmt.Sprintf("Re enter %s name to confirm %s (WARNING %s)", noun, verb, warning),
+		}
+
+		var err error
+		enteredName, err = promptReEnterName.Run()
+
+		if err != nil {
+			return false, err
+		}
+	}
+
+	if len(enteredName) == 0 {
+		return false, fmt.Errorf("Must enter the name of the %s you want to %s", noun, verb)
+	}
+	if enteredName != server.Name {
+		color.Red("✗ Name doesn't match! Expected: %s, Got: %s", server.Name, enteredName)
+		return false, fmt.Errorf("Must enter the exact same name to %s", verb)
+	}
+
+	color.Cyan("%s", details)
+
+	if !yes {
+		areYouReallyReallySure, err := utils.AskYesNo(fmt.Sprintf("Are you really really sure you want to %s this %s? (y/n)", verb, noun))
+
+		if err != nil {
+			return false, err
+		}
+
+		if !areYouReallyReallySure {
+			fmt.Printf("You decided not to %s this %s\n", verb, noun)
+			return false, nil
+		}
+	}
+
+	return true, nil
+}
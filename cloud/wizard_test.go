@@ -0,0 +1,337 @@
+package cloud
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/Joel-Valentine/cogo/config"
+	"github.com/Joel-Valentine/cogo/userdata"
+	"github.com/Joel-Valentine/cogo/utils"
+)
+
+func TestSelectServerIndex(t *testing.T) {
+	servers := []Server{
+		{ID: "1", Name: "web-1"},
+		{ID: "2", Name: "web-2"},
+		{ID: "3", Name: "db"},
+		{ID: "4", Name: "db"},
+	}
+
+	tests := []struct {
+		name          string
+		selector      ServerSelector
+		expectedIndex int
+		expectError   bool
+	}{
+		{
+			name:          "by ID",
+			selector:      ServerSelector{ID: "2"},
+			expectedIndex: 1,
+		},
+		{
+			name:          "by name",
+			selector:      ServerSelector{Name: "web-1"},
+			expectedIndex: 0,
+		},
+		{
+			name:          "by ID and name",
+			selector:      ServerSelector{ID: "4", Name: "db"},
+			expectedIndex: 3,
+		},
+		{
+			name:        "ID and name of different droplets",
+			selector:    ServerSelector{ID: "1", Name: "web-2"},
+			expectError: true,
+		},
+		{
+			name:        "ambiguous name",
+			selector:    ServerSelector{Name: "db"},
+			expectError: true,
+		},
+		{
+			name:        "unknown ID",
+			selector:    ServerSelector{ID: "99"},
+			expectError: true,
+		},
+		{
+			name:        "unknown name",
+			selector:    ServerSelector{Name: "nope"},
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			index, err := SelectServerIndex(servers, tt.selector, "Select server")
+
+			if tt.expectError {
+				if err == nil {
+					t.Errorf("expected error, got index %d", index)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if index != tt.expectedIndex {
+				t.Errorf("expected index %d, got %d", tt.expectedIndex, index)
+			}
+		})
+	}
+}
+
+func TestAskUserData(t *testing.T) {
+	configDir := t.TempDir()
+	t.Setenv(config.DirEnvVar, configDir)
+
+	// a template that would be offered if the question was asked
+	templateDir := filepath.Join(configDir, userdata.DirName)
+	if err := os.MkdirAll(templateDir, 0700); err != nil {
+		t.Fatalf("failed to create template dir: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(templateDir, "db.yaml"), []byte("#cloud-config\n"), 0600); err != nil {
+		t.Fatalf("failed to write template: %v", err)
+	}
+
+	userDataFile := filepath.Join(t.TempDir(), "web.yaml")
+	if err := os.WriteFile(userDataFile, []byte("#cloud-config\nhostname: {{ .Name }}-{{ .Region }}\n"), 0600); err != nil {
+		t.Fatalf("failed to write user data: %v", err)
+	}
+
+	vars := userdata.Vars{Name: "web-1", Region: "lon1"}
+
+	tests := []struct {
+		name        string
+		opts        CreateOptions
+		limit       int
+		expected    string
+		expectError bool
+	}{
+		{
+			name:     "file is rendered",
+			opts:     CreateOptions{UserDataFile: userDataFile},
+			limit:    1024,
+			expected: "#cloud-config\nhostname: web-1-lon1\n",
+		},
+		{
+			name:  "templates are not offered with yes",
+			opts:  CreateOptions{Yes: true},
+			limit: 1024,
+		},
+		{
+			name:        "file over the limit",
+			opts:        CreateOptions{UserDataFile: userDataFile, Yes: true},
+			limit:       10,
+			expectError: true,
+		},
+		{
+			name:        "missing file",
+			opts:        CreateOptions{UserDataFile: filepath.Join(t.TempDir(), "nope.yaml")},
+			limit:       1024,
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			userData, err := AskUserData(tt.opts, vars, tt.limit)
+
+			if tt.expectError {
+				if err == nil {
+					t.Error("expected error, got nil")
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if userData != tt.expected {
+				t.Errorf("expected %q, got %q", tt.expected, userData)
+			}
+		})
+	}
+}
+
+func TestAskVPC(t *testing.T) {
+	vpcs := map[string][]utils.SelectItem{
+		"lon1": {
+			{Name: "default-lon1 (10.106.0.0/20) default", Value: "vpc-default"},
+			{Name: "backend (10.200.0.0/24)", Value: "vpc-backend"},
+		},
+		"ams3": {
+			{Name: "default-ams3 (10.110.0.0/20) default", Value: "vpc-ams3"},
+		},
+	}
+
+	listFunc := func(ctx context.Context, region string) ([]utils.SelectItem, error) {
+		return vpcs[region], nil
+	}
+
+	tests := []struct {
+		name        string
+		opts        CreateOptions
+		region      string
+		expected    string
+		expectError bool
+	}{
+		{
+			name:     "given vpc in region",
+			opts:     CreateOptions{VPC: "vpc-backend"},
+			region:   "lon1",
+			expected: "vpc-backend",
+		},
+		{
+			name:        "given vpc in another region",
+			opts:        CreateOptions{VPC: "vpc-backend"},
+			region:      "ams3",
+			expectError: true,
+		},
+		{
+			name:   "not asked with yes",
+			opts:   CreateOptions{Yes: true},
+			region: "lon1",
+		},
+		{
+			name:   "not asked when there is only the default",
+			opts:   CreateOptions{},
+			region: "ams3",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			vpc, err := AskVPC(context.Background(), tt.opts, tt.region, listFunc)
+
+			if tt.expectError {
+				if err == nil {
+					t.Error("expected error, got nil")
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if vpc != tt.expected {
+				t.Errorf("expected %q, got %q", tt.expected, vpc)
+			}
+		})
+	}
+}
+
+func TestAskVolumes(t *testing.T) {
+	listFunc := func(ctx context.Context, region string) ([]utils.SelectItem, error) {
+		if region != "lon1" {
+			return []utils.SelectItem{}, nil
+		}
+		return []utils.SelectItem{{Name: "db-data (100 GiB, ext4)", Value: "vol-1"}}, nil
+	}
+
+	tests := []struct {
+		name             string
+		opts             CreateOptions
+		region           string
+		expectedExisting []string
+		expectedNew      *NewVolume
+		expectError      bool
+	}{
+		{
+			name:             "existing volume",
+			opts:             CreateOptions{Volumes: []string{"vol-1"}},
+			region:           "lon1",
+			expectedExisting: []string{"vol-1"},
+		},
+		{
+			name:        "existing volume in another region",
+			opts:        CreateOptions{Volumes: []string{"vol-1"}},
+			region:      "ams3",
+			expectError: true,
+		},
+		{
+			name:             "new volume named after the server",
+			opts:             CreateOptions{NewVolume: &NewVolume{SizeGiB: 50, FilesystemType: "ext4"}},
+			region:           "ams3",
+			expectedExisting: []string{},
+			expectedNew:      &NewVolume{Name: "web-1-data", SizeGiB: 50, FilesystemType: "ext4"},
+		},
+		{
+			name:        "invalid new volume",
+			opts:        CreateOptions{NewVolume: &NewVolume{SizeGiB: 0}},
+			region:      "lon1",
+			expectError: true,
+		},
+		{
+			name:             "not asked with yes",
+			opts:             CreateOptions{Yes: true},
+			region:           "lon1",
+			expectedExisting: []string{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			answers, err := AskVolumes(context.Background(), tt.opts, "web-1", tt.region, listFunc)
+
+			if tt.expectError {
+				if err == nil {
+					t.Error("expected error, got nil")
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if strings.Join(answers.Existing, ",") != strings.Join(tt.expectedExisting, ",") {
+				t.Errorf("expected existing volumes %v, got %v", tt.expectedExisting, answers.Existing)
+			}
+
+			if (answers.New == nil) != (tt.expectedNew == nil) {
+				t.Fatalf("expected new volume %+v, got %+v", tt.expectedNew, answers.New)
+			}
+			if tt.expectedNew != nil && *answers.New != *tt.expectedNew {
+				t.Errorf("expected new volume %+v, got %+v", *tt.expectedNew, *answers.New)
+			}
+		})
+	}
+}
+
+func TestConfirmRebuild(t *testing.T) {
+	server := Server{ID: "1", Name: "test-1", Image: "ubuntu-22-04-x64"}
+
+	tests := []struct {
+		name        string
+		confirmName string
+		expected    bool
+		expectError bool
+	}{
+		{name: "name matches", confirmName: "test-1", expected: true},
+		{name: "name doesn't match", confirmName: "test-2", expectError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			confirmed, err := ConfirmRebuild(server, "droplet", "ubuntu-24-04-x64", RebuildOptions{ConfirmName: tt.confirmName, Yes: true})
+
+			if tt.expectError {
+				if err == nil || !strings.Contains(err.Error(), "rebuild") {
+					t.Errorf("expected a rebuild error, got %v", err)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if confirmed != tt.expected {
+				t.Errorf("expected %v, got %v", tt.expected, confirmed)
+			}
+		})
+	}
+}
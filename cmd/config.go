@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"os"
 
+	"github.com/Joel-Valentine/cogo/cloud"
 	"github.com/Joel-Valentine/cogo/credentials"
 	"github.com/fatih/color"
 	"github.com/manifoldco/promptui"
@@ -25,14 +26,17 @@ var configCmd = &cobra.Command{
 Credentials are stored securely in your OS keychain by default (macOS Keychain,
 Windows Credential Manager, or Linux Secret Service).
 
-You can also use environment variables or legacy file-based storage.`,
+You can also use environment variables or legacy file-based storage.
+
+The DigitalOcean token is managed unless another provider is selected with --provider.`,
 }
 
 // setTokenCmd sets the DigitalOcean API token
 var setTokenCmd = &cobra.Command{
 	Use:   "set-token [token]",
-	Short: "Set your DigitalOcean API token",
-	Long: `Store your DigitalOcean API token securely.
+	Short: "Set your DigitalOcean (or --provider) API token",
+	Long: `Store your DigitalOcean API token, or the token of the provider selected
+with --provider, securely.
 
 By default, tokens are stored in your OS keychain. You can also store
 in a configuration file using the --file flag (not recommended).
@@ -40,7 +44,8 @@ in a configuration file using the --file flag (not recommended).
 Example:
   cogo config set-token dop_v1_xxx
   cogo config set-token --file dop_v1_xxx
-  cogo config set-token  (will prompt for token)`,
+  cogo config set-token  (will prompt for token)
+  cogo config set-token --provider hetzner`,
 	Args: cobra.MaximumNArgs(1),
 	RunE: runSetToken,
 }
@@ -108,28 +113,40 @@ func runSetToken(cmd *cobra.Command, args []string) error {
 	ctx := context.Background()
 	var token string
 
+	service, err := credentialService()
+	if err != nil {
+		return err
+	}
+
+	if useFile && !service.LegacyFile {
+		return fmt.Errorf("%s tokens can't be stored in a config file", service.Name)
+	}
+
 	// Get token from args or prompt
 	if len(args) > 0 {
 		token = args[0]
 	} else {
 		prompt := promptui.Prompt{
-			Label: "Enter your DigitalOcean API Token",
+			Label: service.TokenLabel(),
 			Mask:  '*',
 			Validate: func(input string) error {
 				if len(input) == 0 {
 					return fmt.Errorf("token cannot be empty")
 				}
-				return nil
+				return service.ValidateToken(input)
 			},
 		}
 
-		var err error
 		token, err = prompt.Run()
 		if err != nil {
 			return fmt.Errorf("failed to read token: %w", err)
 		}
 	}
 
+	if err := service.ValidateToken(token); err != nil {
+		return err
+	}
+
 	// Determine which provider to use
 	var provider credentials.Provider
 	var providerName string
@@ -138,7 +155,7 @@ func runSetToken(cmd *cobra.Command, args []string) error {
 		provider = credentials.NewFileProvider()
 		providerName = "file"
 	} else {
-		provider = credentials.NewKeychainProvider()
+		provider = credentials.NewKeychainProviderForAccount(service.KeychainAccount)
 		providerName = "keychain"
 	}
 
@@ -161,7 +178,10 @@ func runSetToken(cmd *cobra.Command, args []string) error {
 func runGetToken(cmd *cobra.Command, args []string) error {
 	ctx := context.Background()
 
-	manager := createManager("", false)
+	manager, err := createManager("", false)
+	if err != nil {
+		return err
+	}
 	token, source, err := manager.GetToken(ctx)
 	if err != nil {
 		if err == credentials.ErrTokenNotFound {
@@ -199,7 +219,11 @@ func runDeleteToken(cmd *cobra.Command, args []string) error {
 		return nil
 	}
 
-	manager := createManager("", false)
+	manager, err := createManager("", false)
+	if err != nil {
+		return err
+	}
+
 	if err := manager.DeleteToken(ctx); err != nil {
 		return fmt.Errorf("failed to delete token: %w", err)
 	}
@@ -211,14 +235,22 @@ func runDeleteToken(cmd *cobra.Command, args []string) error {
 func runStatus(cmd *cobra.Command, args []string) error {
 	ctx := context.Background()
 
-	fmt.Println("Credential Configuration Status")
+	service, err := credentialService()
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("%s Credential Configuration Status\n", service.Name)
 	fmt.Println("================================")
 
 	// Check each provider
 	providers := []credentials.Provider{
-		credentials.NewEnvProvider(),
-		credentials.NewKeychainProvider(),
-		credentials.NewFileProvider(),
+		credentials.NewEnvProvider(service.EnvVars...),
+		credentials.NewKeychainProviderForAccount(service.KeychainAccount),
+	}
+
+	if service.LegacyFile {
+		providers = append(providers, credentials.NewFileProvider())
 	}
 
 	for _, provider := range providers {
@@ -241,7 +273,7 @@ func runStatus(cmd *cobra.Command, args []string) error {
 	fmt.Println("\nEffective Token")
 	fmt.Println("---------------")
 
-	manager := createManager("", false)
+	manager := service.NewManager("", false)
 	token, source, err := manager.GetToken(ctx)
 	if err != nil {
 		if err == credentials.ErrTokenNotFound {
@@ -266,15 +298,12 @@ func runStatus(cmd *cobra.Command, args []string) error {
 	// Show environment variable info
 	fmt.Println("\nEnvironment Variables")
 	fmt.Println("--------------------")
-	if os.Getenv("DIGITALOCEAN_TOKEN") != "" {
-		color.Green("DIGITALOCEAN_TOKEN: Set")
-	} else {
-		fmt.Println("DIGITALOCEAN_TOKEN: Not set")
-	}
-	if os.Getenv("COGO_DIGITALOCEAN_TOKEN") != "" {
-		color.Green("COGO_DIGITALOCEAN_TOKEN: Set")
-	} else {
-		fmt.Println("COGO_DIGITALOCEAN_TOKEN: Not set")
+	for _, envVar := range service.EnvVars {
+		if os.Getenv(envVar) != "" {
+			color.Green("%s: Set", envVar)
+		} else {
+			fmt.Printf("%s: Not set\n", envVar)
+		}
 	}
 
 	return nil
@@ -283,8 +312,18 @@ func runStatus(cmd *cobra.Command, args []string) error {
 func runMigrate(cmd *cobra.Command, args []string) error {
 	ctx := context.Background()
 
+	service, err := credentialService()
+	if err != nil {
+		return err
+	}
+
+	if !service.LegacyFile {
+		color.Yellow("%s tokens are never stored in a config file, nothing to migrate", service.Name)
+		return nil
+	}
+
 	fileProvider := credentials.NewFileProvider()
-	keychainProvider := credentials.NewKeychainProvider()
+	keychainProvider := credentials.NewKeychainProviderForAccount(service.KeychainAccount)
 
 	// Check if keychain is available
 	if !keychainProvider.Available() {
@@ -338,19 +377,34 @@ func runMigrate(cmd *cobra.Command, args []string) error {
 }
 
 // createManager creates a credential manager with the standard provider chain
+// for the provider selected with --provider
 // flagToken is an optional token from CLI flag
 // includePrompt determines whether to include the interactive prompt provider
-func createManager(flagToken string, includePrompt bool) *credentials.Manager {
-	providers := []credentials.Provider{
-		credentials.NewFlagProvider(flagToken),
-		credentials.NewEnvProvider(),
-		credentials.NewKeychainProvider(),
-		credentials.NewFileProvider(),
+func createManager(flagToken string, includePrompt bool) (*credentials.Manager, error) {
+	service, err := credentialService()
+	if err != nil {
+		return nil, err
+	}
+
+	return service.NewManager(flagToken, includePrompt), nil
+}
+
+// credentialService returns where the token of the provider selected with --provider is kept
+// DigitalOcean is used when no provider was selected so existing usage keeps working
+func credentialService() (credentials.Service, error) {
+	if providerKey == "" {
+		return credentials.DigitalOcean, nil
+	}
+
+	provider, err := cloud.Get(providerKey)
+	if err != nil {
+		return credentials.Service{}, err
 	}
 
-	if includePrompt {
-		providers = append(providers, credentials.NewPromptProvider())
+	withCredentials, ok := provider.(cloud.CredentialProvider)
+	if !ok {
+		return credentials.Service{}, fmt.Errorf("%s does not use an API token", provider.Name())
 	}
 
-	return credentials.NewManager(providers...)
+	return withCredentials.CredentialService(), nil
 }
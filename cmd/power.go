@@ -0,0 +1,81 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/Joel-Valentine/cogo/cloud"
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+)
+
+var powerOptions cloud.PowerOptions
+
+// powerCmd runs a power action on a server
+var powerCmd = &cobra.Command{
+	Use:   "power <" + strings.Join(cloud.PowerActions, "|") + ">",
+	Short: "Power a server on or off, power cycle, shut down or reboot it",
+	Long: `Will show a list of servers that you currently have in a selected provider,
+then run the power action on the one you select and wait for it to finish.
+
+  on        power the server on
+  off       cut the power, like pulling the plug
+  cycle     power the server off and back on
+  shutdown  shut the server down gracefully, powering it off if that takes
+            longer than --shutdown-timeout
+  reboot    reboot the server gracefully
+
+Select the server with --id or --name to run the action without prompts.
+
+Example:
+  cogo power reboot
+  cogo power shutdown --provider do --name web-1
+  cogo power on --provider do --id 12345678`,
+	Args:          cobra.MatchAll(cobra.ExactArgs(1), cobra.OnlyValidArgs),
+	ValidArgs:     cloud.PowerActions,
+	SilenceUsage:  true,
+	SilenceErrors: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := context.Background()
+
+		powerOptions.Action = args[0]
+
+		selectedProvider, err := cloud.AskForProvider(ctx, providerKey)
+
+		if err != nil {
+			color.Yellow("Something went wrong asking for selected provider\n")
+			return err
+		}
+
+		noun := selectedProvider.ServerNoun()
+
+		powerProvider, ok := selectedProvider.(cloud.PowerProvider)
+
+		if !ok {
+			return fmt.Errorf("%s does not support power actions", selectedProvider.Name())
+		}
+
+		server, err := powerProvider.Power(ctx, powerOptions)
+
+		if err != nil {
+			color.Cyan("Aborted, %s power %s did not finish\n", noun, powerOptions.Action)
+			return err
+		}
+
+		color.Green("✓ %s [%s] power %s finished, status: %s", capitalize(noun), server.Name, powerOptions.Action, server.Status)
+
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(powerCmd)
+
+	// Flags
+	powerCmd.Flags().StringVar(&powerOptions.ID, "id", "", "ID of the server")
+	powerCmd.Flags().StringVar(&powerOptions.Name, "name", "", "Name of the server")
+	powerCmd.Flags().DurationVar(&powerOptions.Timeout, "timeout", 5*time.Minute, "How long to wait for the action to finish")
+	powerCmd.Flags().DurationVar(&powerOptions.ShutdownTimeout, "shutdown-timeout", time.Minute, "How long a shutdown is given before the server is powered off instead")
+}
@@ -0,0 +1,191 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/Joel-Valentine/cogo/cloud"
+	"github.com/Joel-Valentine/cogo/preset"
+	"github.com/Joel-Valentine/cogo/utils"
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+)
+
+var (
+	presetOptions   cloud.CreateOptions
+	presetDeleteYes bool
+)
+
+// presetCmd represents the preset command
+var presetCmd = &cobra.Command{
+	Use:   "preset",
+	Short: "Manage saved sets of create answers",
+	Long: `Save the answers you give over and over (image, size, region, SSH keys, tags,
+user data, VPC and features) as a named preset, then create from it with
+cogo create --preset <name>. Only the answers missing from the preset are asked for,
+and any flag given to create overrides the preset.
+
+Presets are plain YAML files kept in the presets directory of the cogo config
+directory, so they can be shared by checking them into a repo. A path to a
+YAML file can be given to --preset instead of a name.`,
+}
+
+// presetSaveCmd saves a preset from flags
+var presetSaveCmd = &cobra.Command{
+	Use:   "save <name>",
+	Short: "Save the answers given as flags as a preset",
+	Long: `Save the answers given as flags under the name, replacing any preset that
+already has it. The provider selected with --provider is saved too.
+
+Example:
+  cogo preset save web --provider do --image ubuntu-24-04-x64 --size s-1vcpu-1gb --region lon1 --tag web --monitoring
+  cogo preset save db --size s-2vcpu-4gb --ssh-key 12345 --user-data ./db.yaml`,
+	Args:          cobra.ExactArgs(1),
+	SilenceUsage:  true,
+	SilenceErrors: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if providerKey != "" {
+			if _, err := cloud.Get(providerKey); err != nil {
+				return err
+			}
+		}
+
+		if len(presetOptions.Tags) > 0 {
+			if err := utils.ValidateTags(strings.Join(presetOptions.Tags, ",")); err != nil {
+				return err
+			}
+		}
+
+		presetOptions.Features = featuresFromFlags(cmd)
+
+		saved := preset.FromOptions(providerKey, presetOptions)
+
+		if saved.IsEmpty() {
+			return errors.New("nothing to save, give at least one answer as a flag")
+		}
+
+		if err := preset.Save(args[0], saved); err != nil {
+			return err
+		}
+
+		color.Green("✓ Preset [%s] saved", args[0])
+
+		return nil
+	},
+}
+
+// presetListCmd lists the saved presets
+var presetListCmd = &cobra.Command{
+	Use:           "list",
+	Short:         "List the saved presets",
+	Args:          cobra.NoArgs,
+	SilenceUsage:  true,
+	SilenceErrors: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		names, err := preset.List()
+
+		if err != nil {
+			return err
+		}
+
+		if len(names) == 0 {
+			color.Yellow("No presets saved")
+			fmt.Println("\nTo save one, run:")
+			fmt.Println("  $ cogo preset save <name> --size s-1vcpu-1gb --region lon1")
+			return nil
+		}
+
+		for _, name := range names {
+			fmt.Println(name)
+		}
+
+		return nil
+	},
+}
+
+// presetShowCmd prints a preset
+var presetShowCmd = &cobra.Command{
+	Use:           "show <name>",
+	Short:         "Print the YAML of a preset",
+	Args:          cobra.ExactArgs(1),
+	SilenceUsage:  true,
+	SilenceErrors: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		loaded, err := preset.Load(args[0])
+
+		if err != nil {
+			return err
+		}
+
+		content, err := preset.Marshal(loaded)
+
+		if err != nil {
+			return err
+		}
+
+		_, err = os.Stdout.Write(content)
+
+		return err
+	},
+}
+
+// presetDeleteCmd deletes a preset
+var presetDeleteCmd = &cobra.Command{
+	Use:           "delete <name>",
+	Short:         "Delete a saved preset",
+	Args:          cobra.ExactArgs(1),
+	SilenceUsage:  true,
+	SilenceErrors: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		name := args[0]
+
+		if err := preset.ValidateName(name); err != nil {
+			return err
+		}
+
+		if !presetDeleteYes {
+			confirmed, err := utils.AskYesNo(fmt.Sprintf("Delete preset %s? (y/n)", name))
+
+			if err != nil {
+				return err
+			}
+
+			if !confirmed {
+				color.Cyan("Aborted, preset was not deleted\n")
+				return nil
+			}
+		}
+
+		if err := preset.Delete(name); err != nil {
+			return err
+		}
+
+		color.Green("✓ Preset [%s] deleted", name)
+
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(presetCmd)
+	presetCmd.AddCommand(presetSaveCmd)
+	presetCmd.AddCommand(presetListCmd)
+	presetCmd.AddCommand(presetShowCmd)
+	presetCmd.AddCommand(presetDeleteCmd)
+
+	// Flags
+	presetSaveCmd.Flags().StringVar(&presetOptions.Image, "image", "", "Image slug to create the server from (ubuntu-24-04-x64)")
+	presetSaveCmd.Flags().StringVar(&presetOptions.Size, "size", "", "Size slug of the server (s-1vcpu-1gb)")
+	presetSaveCmd.Flags().StringVar(&presetOptions.Region, "region", "", "Region slug to create the server in (lon1)")
+	presetSaveCmd.Flags().StringVar(&presetOptions.VPC, "vpc", "", "ID of the VPC to put the server in")
+	for _, feature := range utils.DropletFeatures {
+		presetSaveCmd.Flags().Bool(feature, false, featureFlagUsage[feature])
+	}
+	presetSaveCmd.Flags().StringSliceVar(&presetOptions.SSHKeys, "ssh-key", nil, "ID or fingerprint of an SSH key to add to the server, repeat or comma separate for several")
+	presetSaveCmd.Flags().StringSliceVar(&presetOptions.Tags, "tag", nil, "Tag to add to the server, repeat or comma separate for several")
+	presetSaveCmd.Flags().StringVar(&presetOptions.UserDataFile, "user-data", "", "Cloud-init user data template file to give to the server")
+
+	presetDeleteCmd.Flags().BoolVarP(&presetDeleteYes, "yes", "y", false, "Delete without asking for confirmation")
+}
@@ -0,0 +1,84 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/Joel-Valentine/cogo/cloud"
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+)
+
+var rebuildOptions cloud.RebuildOptions
+
+// rebuildCmd reinstalls a server from an image
+var rebuildCmd = &cobra.Command{
+	Use:   "rebuild",
+	Short: "Rebuild a server in selected provider from an image",
+	Long: `Will show a list of servers that you currently have in a selected provider,
+then ask which image to rebuild the one you select from, with the same image
+steps as create. The server keeps its name, size and IP addresses, but
+everything on its disk is replaced by the image.
+
+Be very careful here. Like destroy, there will be two warnings and you will
+have to re-enter the server's name.
+
+To rebuild without prompts, select the server with --id or --name, give the
+image with --image, confirm its name with --confirm-name and skip the warnings
+with --yes. The rebuild is refused if --confirm-name doesn't exactly match the
+server's name.
+
+Example:
+  cogo rebuild
+  cogo rebuild --provider do --id 12345678 --image ubuntu-24-04-x64 --confirm-name test-1 --yes`,
+	Args:          cobra.NoArgs,
+	SilenceUsage:  true,
+	SilenceErrors: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := context.Background()
+
+		selectedProvider, err := cloud.AskForProvider(ctx, providerKey)
+
+		if err != nil {
+			color.Yellow("Something went wrong asking for selected provider\n")
+			return err
+		}
+
+		noun := selectedProvider.ServerNoun()
+
+		rebuildProvider, ok := selectedProvider.(cloud.RebuildProvider)
+
+		if !ok {
+			return fmt.Errorf("%s does not support rebuilding", selectedProvider.Name())
+		}
+
+		server, err := rebuildProvider.Rebuild(ctx, rebuildOptions)
+
+		if err != nil {
+			color.Cyan("Aborted, %s was not rebuilt\n", noun)
+			return err
+		}
+
+		if server == nil {
+			color.Cyan("Aborted, %s was not rebuilt\n", noun)
+			return nil
+		}
+
+		color.Green("✓ %s [%s] was rebuilt from %s, status: %s", capitalize(noun), server.Name, server.Image, server.Status)
+
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(rebuildCmd)
+
+	// Flags
+	rebuildCmd.Flags().StringVar(&rebuildOptions.ID, "id", "", "ID of the server to rebuild")
+	rebuildCmd.Flags().StringVar(&rebuildOptions.Name, "name", "", "Name of the server to rebuild")
+	rebuildCmd.Flags().StringVar(&rebuildOptions.Image, "image", "", "Image slug, or ID of a custom image or snapshot, to rebuild the server from")
+	rebuildCmd.Flags().StringVar(&rebuildOptions.ConfirmName, "confirm-name", "", "Name of the server, must match exactly to confirm the rebuild")
+	rebuildCmd.Flags().BoolVarP(&rebuildOptions.Yes, "yes", "y", false, "Rebuild without asking the y/n questions")
+	rebuildCmd.Flags().DurationVar(&rebuildOptions.Timeout, "timeout", 10*time.Minute, "How long to wait for the rebuild to finish")
+}
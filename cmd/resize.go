@@ -0,0 +1,83 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/Joel-Valentine/cogo/cloud"
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+)
+
+var resizeOptions cloud.ResizeOptions
+
+// resizeCmd changes the size of a server
+var resizeCmd = &cobra.Command{
+	Use:   "resize",
+	Short: "Resize a server in selected provider",
+	Long: `Will show a list of servers that you currently have in a selected provider,
+then show the current size of the one you select and ask which size to resize it to.
+
+Only the sizes offered in the server's region are listed, each with how much more
+or less it costs a month. Sizes with a bigger disk are marked as irreversible:
+growing the disk means the server can never be resized back down, so you are asked
+whether to grow it or only change the CPU and memory.
+
+A server that is on is shut down for the resize and powered back on afterwards.
+
+Example:
+  cogo resize
+  cogo resize --provider do --name web-1 --size s-2vcpu-4gb
+  cogo resize --provider do --id 12345678 --size s-4vcpu-8gb --disk --yes`,
+	Args:          cobra.NoArgs,
+	SilenceUsage:  true,
+	SilenceErrors: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := context.Background()
+
+		selectedProvider, err := cloud.AskForProvider(ctx, providerKey)
+
+		if err != nil {
+			color.Yellow("Something went wrong asking for selected provider\n")
+			return err
+		}
+
+		noun := selectedProvider.ServerNoun()
+
+		resizeProvider, ok := selectedProvider.(cloud.ResizeProvider)
+
+		if !ok {
+			return fmt.Errorf("%s does not support resizing", selectedProvider.Name())
+		}
+
+		server, err := resizeProvider.Resize(ctx, resizeOptions)
+
+		if err != nil {
+			color.Cyan("Aborted, %s was not resized\n", noun)
+			return err
+		}
+
+		if server == nil {
+			color.Cyan("Aborted, %s was not resized\n", noun)
+			return nil
+		}
+
+		color.Green("✓ %s [%s] resized to %s, status: %s", capitalize(noun), server.Name, server.Size, server.Status)
+
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(resizeCmd)
+
+	// Flags
+	resizeCmd.Flags().StringVar(&resizeOptions.ID, "id", "", "ID of the server to resize")
+	resizeCmd.Flags().StringVar(&resizeOptions.Name, "name", "", "Name of the server to resize")
+	resizeCmd.Flags().StringVar(&resizeOptions.Size, "size", "", "Size slug to resize to (s-2vcpu-4gb)")
+	resizeCmd.Flags().BoolVar(&resizeOptions.Disk, "disk", false, "Grow the disk to the new size's disk as well, this can't be undone")
+	resizeCmd.Flags().BoolVarP(&resizeOptions.Yes, "yes", "y", false, "Resize without asking the y/n questions, only the CPU and memory change unless --disk is given")
+	resizeCmd.Flags().DurationVar(&resizeOptions.Timeout, "timeout", 10*time.Minute, "How long to wait for each step of the resize to finish")
+	resizeCmd.Flags().DurationVar(&resizeOptions.ShutdownTimeout, "shutdown-timeout", time.Minute, "How long a shutdown is given before the server is powered off instead")
+}
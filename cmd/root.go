@@ -1,15 +1,43 @@
 package cmd
 
 import (
+	"context"
 	"fmt"
 	"os"
+	"strings"
+	"time"
 
+	"github.com/Joel-Valentine/cogo/aws"
+	"github.com/Joel-Valentine/cogo/cloud"
 	do "github.com/Joel-Valentine/cogo/digitalocean"
+	"github.com/Joel-Valentine/cogo/hetzner"
+	"github.com/Joel-Valentine/cogo/linode"
+	"github.com/Joel-Valentine/cogo/mock"
+	"github.com/Joel-Valentine/cogo/output"
+	"github.com/Joel-Valentine/cogo/preset"
 	"github.com/Joel-Valentine/cogo/utils"
 	"github.com/fatih/color"
 	"github.com/spf13/cobra"
 )
 
+var (
+	providerKey    string
+	createOptions  cloud.CreateOptions
+	newVolume      cloud.NewVolume
+	destroyOptions cloud.DestroyOptions
+	listOptions    cloud.ListOptions
+	listOutput     string
+	createPreset   string
+)
+
+// featureFlagUsage describes the create flag of each optional feature
+var featureFlagUsage = map[string]string{
+	utils.FeatureBackups:    "Turn on weekly backups, which add 20% to the monthly price",
+	utils.FeatureMonitoring: "Turn on monitoring",
+	utils.FeatureIPv6:       "Turn on IPv6",
+	utils.FeatureAgent:      "Install the droplet agent for web console access",
+}
+
 // rootCmd represents the base command when called without any subcommands
 var rootCmd = &cobra.Command{
 	Use:   "Cogo create, list, destroy wizard",
@@ -27,60 +55,248 @@ func Execute() {
 }
 
 func init() {
+	cloud.Register(do.NewProvider())
+	cloud.Register(hetzner.NewProvider())
+	cloud.Register(linode.NewProvider())
+	cloud.Register(aws.NewProvider())
+	cloud.Register(mock.NewProvider())
+
 	rootCmd.AddCommand(create)
 	rootCmd.AddCommand(list)
 	rootCmd.AddCommand(destroy)
 	cobra.OnInitialize()
+
+	// Flags
+	rootCmd.PersistentFlags().StringVarP(&providerKey, "provider", "p", "", "Provider to use instead of asking ("+strings.Join(cloud.Keys(), ", ")+")")
+
+	create.Flags().StringVar(&createOptions.Name, "name", "", "Name of the server")
+	create.Flags().StringVar(&createOptions.Image, "image", "", "Image slug to create the server from (ubuntu-24-04-x64)")
+	create.Flags().StringVar(&createOptions.Size, "size", "", "Size slug of the server (s-1vcpu-1gb)")
+	create.Flags().StringVar(&createOptions.Region, "region", "", "Region slug to create the server in (lon1)")
+	create.Flags().IntVar(&createOptions.Count, "count", 1, "Number of identical servers to create")
+	create.Flags().StringVar(&createOptions.NameTemplate, "name-template", "", "Template for the name of each server when using --count, given {{.Index}} and {{.Region}} (default <name>-{{.Index}})")
+	create.Flags().StringSliceVar(&createOptions.Regions, "regions", nil, "Region slugs to spread the servers round-robin across when using --count")
+	create.Flags().StringVar(&createOptions.VPC, "vpc", "", "ID of the VPC to put the server in, defaults to the region's default VPC")
+	for _, feature := range utils.DropletFeatures {
+		create.Flags().Bool(feature, false, featureFlagUsage[feature])
+	}
+	create.Flags().StringSliceVar(&createOptions.Volumes, "volume", nil, "ID of an unattached volume in the region to attach, repeat or comma separate for several")
+	create.Flags().Int64Var(&newVolume.SizeGiB, "new-volume-size", 0, "Create and attach a new volume of this many GiB")
+	create.Flags().StringVar(&newVolume.Name, "new-volume-name", "", "Name of the new volume (default <name>-data)")
+	create.Flags().StringVar(&newVolume.FilesystemType, "new-volume-fs", "ext4", "Filesystem to format the new volume with: ext4, xfs, or empty for none")
+	create.Flags().StringVar(&newVolume.FilesystemLabel, "new-volume-label", "", "Filesystem label of the new volume")
+	create.Flags().StringSliceVar(&createOptions.SSHKeys, "ssh-key", nil, "ID or fingerprint of an SSH key to add to the server, repeat or comma separate for several")
+	create.Flags().BoolVar(&createOptions.ShowRootPassword, "show-root-password", false, "Allow creating a server without an SSH key by printing its root password to stderr (linode and hetzner only)")
+	create.Flags().StringSliceVar(&createOptions.Tags, "tag", nil, "Tag to add to the server, repeat or comma separate for several")
+	create.Flags().StringVar(&createOptions.SecurityGroup, "security-group", "", "ID of the security group to put the server in (aws only)")
+	create.Flags().StringVar(&createOptions.UserDataFile, "user-data", "", "Cloud-init user data template file to give to the server")
+	create.Flags().BoolVarP(&createOptions.Yes, "yes", "y", false, "Create without asking for confirmation")
+	create.Flags().BoolVar(&createOptions.Wait, "wait", false, "Wait for the server to become active and print its IP addresses")
+	create.Flags().DurationVar(&createOptions.WaitTimeout, "wait-timeout", 5*time.Minute, "How long to wait for the server to become active")
+	create.Flags().BoolVar(&createOptions.DryRun, "dry-run", false, "Print a JSON summary of what would be created and its estimated cost, without creating anything")
+	create.Flags().BoolVar(&createOptions.NoHistory, "no-history", false, "Don't pin recent answers to the top of each list, or remember these ones")
+	create.Flags().StringVar(&createPreset, "preset", "", "Name of a saved preset, or path to a preset YAML file, to fill in the answers from")
+
+	list.Flags().StringVar(&listOptions.Tag, "tag", "", "Only list servers with this tag")
+	list.Flags().StringVarP(&listOutput, "output", "o", "", "Output format: "+strings.Join(output.Formats, ", "))
+
+	destroy.Flags().StringVar(&destroyOptions.ID, "id", "", "ID of the server to destroy")
+	destroy.Flags().StringVar(&destroyOptions.Name, "name", "", "Name of the server to destroy")
+	destroy.Flags().StringVar(&destroyOptions.ConfirmName, "confirm-name", "", "Name of the server, must match exactly to confirm the destroy")
+	destroy.Flags().BoolVarP(&destroyOptions.Yes, "yes", "y", false, "Destroy without asking the y/n questions")
 }
 
 var create = &cobra.Command{
 	Use:   "create",
 	Short: "Creates a server in selected provider",
-	Long:  `Will walk you through a wizard to create a server in a selected provider`,
-	Run: func(cmd *cobra.Command, args []string) {
+	Long: `Will walk you through a wizard to create a server in a selected provider
+
+Any answer given as a flag is validated and its question is skipped, so
+supplying every flag along with --yes creates the server without any prompts.
+A summary of the server and its estimated monthly cost is shown before asking
+if you are sure, --dry-run prints it as JSON instead and creates nothing.
+
+Example:
+  cogo create
+  cogo create --wait
+  cogo create --tag web --tag env:prod
+  cogo create --backups --monitoring
+  cogo create --new-volume-size 100 --new-volume-label data
+  cogo create --count 5 --name-template "worker-{{.Index}}" --regions lon1,ams3
+  cogo create --preset web --name web-3
+  cogo create --preset web --name web-3 --dry-run
+  cogo create --provider do --name web-1 --image ubuntu-24-04-x64 --size s-1vcpu-1gb --region lon1 --ssh-key 12345 --yes`,
+	SilenceUsage:  true,
+	SilenceErrors: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := context.Background()
+
+		var fromPreset preset.Preset
+
+		if createPreset != "" {
+			loaded, err := preset.Load(createPreset)
+
+			if err != nil {
+				return err
+			}
+
+			fromPreset = loaded
+		}
+
+		provider := providerKey
+		if provider == "" {
+			provider = fromPreset.Provider
+		}
 
-		selectedProvider, err := utils.AskForProvider()
+		selectedProvider, err := cloud.AskForProvider(ctx, provider)
 
 		if err != nil {
 			color.Yellow("Something went wrong asking for selected provider\n")
-			color.Cyan("List your droplets in a couple of minutes to see the IP\n")
-			return
+			return err
 		}
 
-		if selectedProvider == "DO" {
-			createdDroplet, createDropletError := do.CreateDroplet()
+		noun := selectedProvider.ServerNoun()
+
+		createOptions.Features = featuresFromFlags(cmd)
+
+		// Flags take precedence over the preset
+		fromPreset.Apply(&createOptions)
+
+		bulk := createOptions.Count != 1 || createOptions.NameTemplate != "" || len(createOptions.Regions) > 0
 
-			if createDropletError != nil {
-				color.Cyan("Aborted, droplet was not created\n")
-				return
+		if bulk {
+			if createOptions.Count < 1 {
+				return fmt.Errorf("--count must be at least 1")
 			}
 
-			if createdDroplet == nil {
-				color.Cyan("Aborted, droplet was not created\n")
-				return
+			if createOptions.Region != "" && len(createOptions.Regions) > 0 {
+				return fmt.Errorf("use either --region or --regions")
 			}
 
-			color.Green("Droplet [%s] was created!", createdDroplet.Name)
-			color.Cyan("List your droplets in a couple of minutes to see the IP\n")
+			if createOptions.VPC != "" && len(createOptions.Regions) > 1 {
+				return fmt.Errorf("--vpc can't be used with more than one region")
+			}
+
+			if len(createOptions.Volumes) > 0 || cmd.Flags().Changed("new-volume-size") {
+				return fmt.Errorf("volumes can't be attached when creating several %ss", noun)
+			}
+		}
+
+		if _, ok := selectedProvider.(cloud.FeatureProvider); createOptions.Features != nil && !ok {
+			return fmt.Errorf("%s does not support --%s", selectedProvider.Name(), strings.Join(utils.DropletFeatures, ", --"))
+		}
+
+		if cmd.Flags().Changed("new-volume-size") {
+			createOptions.NewVolume = &newVolume
+		} else if cmd.Flags().Changed("new-volume-name") || cmd.Flags().Changed("new-volume-fs") || cmd.Flags().Changed("new-volume-label") {
+			return fmt.Errorf("--new-volume-size is needed to create a new volume")
 		}
+
+		if _, ok := selectedProvider.(cloud.VolumeProvider); (len(createOptions.Volumes) > 0 || createOptions.NewVolume != nil) && !ok {
+			return fmt.Errorf("%s does not support attaching volumes", selectedProvider.Name())
+		}
+
+		if _, ok := selectedProvider.(cloud.VPCProvider); createOptions.VPC != "" && !ok {
+			return fmt.Errorf("%s does not support --vpc", selectedProvider.Name())
+		}
+
+		if _, ok := selectedProvider.(cloud.TagProvider); len(createOptions.Tags) > 0 && !ok {
+			return fmt.Errorf("%s does not support --tag", selectedProvider.Name())
+		}
+
+		if _, ok := selectedProvider.(cloud.SecurityGroupProvider); createOptions.SecurityGroup != "" && !ok {
+			return fmt.Errorf("%s does not support --security-group", selectedProvider.Name())
+		}
+
+		if _, ok := selectedProvider.(cloud.UserDataProvider); createOptions.UserDataFile != "" && !ok {
+			return fmt.Errorf("%s does not support --user-data", selectedProvider.Name())
+		}
+
+		if bulk {
+			return createMany(ctx, selectedProvider)
+		}
+
+		createdServer, createServerError := selectedProvider.Create(ctx, createOptions)
+
+		if createServerError != nil && createdServer != nil {
+			color.Yellow("%s [%s] was created (ID %s), but waiting for it to become active failed", capitalize(noun), createdServer.Name, createdServer.ID)
+			return createServerError
+		}
+
+		if createServerError != nil {
+			color.Cyan("Aborted, %s was not created\n", noun)
+			return createServerError
+		}
+
+		if createdServer == nil {
+			if !createOptions.DryRun {
+				color.Cyan("Aborted, %s was not created\n", noun)
+			}
+			return nil
+		}
+
+		if createOptions.Wait {
+			printActiveServer(noun, createdServer)
+			return nil
+		}
+
+		_, hasVPC := selectedProvider.(cloud.VPCProvider)
+		printCreatedServer(noun, createdServer, hasVPC)
+
+		return nil
 	},
 }
 
 var list = &cobra.Command{
 	Use:   "list",
 	Short: "Lists servers created in selected provider",
-	Long:  `Will show a list of servers that you currently have in a selected provider`,
-	Run: func(cmd *cobra.Command, args []string) {
-		selectedProvider, err := utils.AskForProvider()
+	Long: `Will show a list of servers that you currently have in a selected provider
+
+Use --output to print the list in a format that other tools can read:
+  json, yaml  every field of every server
+  csv         a header row followed by a row per server
+  wide        a table with every field
+  name, id    just the name or ID of each server, one per line
+
+Example:
+  cogo list --output json | jq '.[].public_ipv4'
+  cogo list --tag web
+  for id in $(cogo list -o id); do echo $id; done`,
+	SilenceUsage:  true,
+	SilenceErrors: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := context.Background()
+
+		if listOutput != "" {
+			if err := output.ValidateFormat(listOutput); err != nil {
+				return err
+			}
+		}
+
+		selectedProvider, err := cloud.AskForProvider(ctx, providerKey)
 
 		if err != nil {
 			color.Yellow("Something went wrong asking for selected provider\n")
-			return
+			return err
+		}
+
+		if _, ok := selectedProvider.(cloud.TagProvider); listOptions.Tag != "" && !ok {
+			return fmt.Errorf("%s does not support --tag", selectedProvider.Name())
 		}
 
-		if selectedProvider == "DO" {
-			do.DisplayDropletList()
+		servers, err := selectedProvider.List(ctx, listOptions)
+
+		if err != nil {
+			return err
+		}
+
+		if listOutput != "" {
+			return output.Render(os.Stdout, listOutput, servers)
 		}
+
+		output.Display(selectedProvider.ServerNoun(), servers)
+
+		return nil
 	},
 }
 
@@ -91,30 +307,165 @@ var destroy = &cobra.Command{
 	with the ability to select one and delete/destroy it.
 	
 	Be very careful here. There will be two warnings to make sure that you don't accidentally delete
-	a crucial droplet`,
-	Run: func(cmd *cobra.Command, args []string) {
-		selectedProvider, err := utils.AskForProvider()
+	a crucial server
+
+	To destroy without prompts, select the server with --id or --name, confirm its name
+	with --confirm-name and skip the warnings with --yes. The destroy is refused if
+	--confirm-name doesn't exactly match the server's name.
+
+	Example:
+	  cogo destroy --provider do --id 12345678 --confirm-name web-1 --yes`,
+	SilenceUsage:  true,
+	SilenceErrors: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := context.Background()
+
+		selectedProvider, err := cloud.AskForProvider(ctx, providerKey)
 
 		if err != nil {
 			color.Yellow("Something went wrong asking for selected provider\n")
-			color.Cyan("Aborted, Droplet was not destroyed:\n")
-			return
+			color.Cyan("Aborted, server was not destroyed:\n")
+			return err
 		}
 
-		if selectedProvider == "DO" {
-			destroyedDroplet, err := do.DestroyDroplet()
+		noun := selectedProvider.ServerNoun()
 
-			if err != nil {
-				color.Cyan("Aborted, Droplet was not destroyed\n")
-				return
-			}
+		destroyedServer, err := selectedProvider.Destroy(ctx, destroyOptions)
 
-			if destroyedDroplet == nil {
-				color.Cyan("Aborted, droplet was not destroyed\n")
-				return
-			}
+		if err != nil {
+			color.Cyan("Aborted, %s was not destroyed\n", noun)
+			return err
+		}
 
-			color.Green("Droplet [%s] has been destroyed\n", destroyedDroplet.Name)
+		if destroyedServer == nil {
+			color.Cyan("Aborted, %s was not destroyed\n", noun)
+			return nil
 		}
+
+		color.Green("%s [%s] has been destroyed\n", capitalize(noun), destroyedServer.Name)
+
+		return nil
 	},
 }
+
+// createMany creates createOptions.Count servers with a provider that supports it
+// then reports how creating each of them went
+func createMany(ctx context.Context, provider cloud.Provider) error {
+	noun := provider.ServerNoun()
+
+	bulkProvider, ok := provider.(cloud.BulkCreateProvider)
+
+	if !ok {
+		return fmt.Errorf("%s does not support --count, --name-template or --regions", provider.Name())
+	}
+
+	results, err := bulkProvider.CreateMany(ctx, createOptions)
+
+	if err != nil {
+		color.Cyan("Aborted, no %ss were created\n", noun)
+		return err
+	}
+
+	if results == nil {
+		if !createOptions.DryRun {
+			color.Cyan("Aborted, no %ss were created\n", noun)
+		}
+		return nil
+	}
+
+	failed := 0
+
+	for _, result := range results {
+		if result.Err != nil && result.Server != nil {
+			failed++
+			color.Red("✗ %s [%s] in %s was created (ID %s), but waiting for it to become active failed: %s", capitalize(noun), result.Name, result.Region, result.Server.ID, result.Err)
+			continue
+		}
+
+		if result.Err != nil {
+			failed++
+			color.Red("✗ %s [%s] in %s failed: %s", capitalize(noun), result.Name, result.Region, result.Err)
+			continue
+		}
+
+		if createOptions.Wait {
+			color.Green("✓ %s [%s] in %s is active, ID: %s IPv4: %s", capitalize(noun), result.Name, result.Region, result.Server.ID, orPending(result.Server.PublicIPv4))
+		} else {
+			color.Green("✓ %s [%s] in %s was created, ID: %s", capitalize(noun), result.Name, result.Region, result.Server.ID)
+		}
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("%d of %d %ss failed", failed, len(results), noun)
+	}
+
+	if !createOptions.Wait {
+		color.Cyan("List your %ss in a couple of minutes to see their IPs\n", noun)
+	}
+
+	return nil
+}
+
+// featuresFromFlags returns the features turned on with flags
+// or nil if none of the feature flags were used, so the user is asked instead
+func featuresFromFlags(cmd *cobra.Command) []string {
+	var features []string
+
+	for _, feature := range utils.DropletFeatures {
+		if !cmd.Flags().Changed(feature) {
+			continue
+		}
+
+		if features == nil {
+			features = []string{}
+		}
+
+		if on, _ := cmd.Flags().GetBool(feature); on {
+			features = append(features, feature)
+		}
+	}
+
+	return features
+}
+
+// printActiveServer prints the details of a server that has finished being created
+func printActiveServer(noun string, server *cloud.Server) {
+	color.Green("%s [%s] is active!\n", capitalize(noun), server.Name)
+	fmt.Printf("ID:           %s\n", server.ID)
+	fmt.Printf("IPv4:         %s\n", orPending(server.PublicIPv4))
+	if server.PrivateIPv4 != "" {
+		fmt.Printf("Private IPv4: %s\n", server.PrivateIPv4)
+	}
+	if server.PublicIPv6 != "" {
+		fmt.Printf("IPv6:         %s\n", server.PublicIPv6)
+	}
+}
+
+// printCreatedServer prints the details of a server that has been created but not waited for
+// Its private IP is shown when the provider already knows it, or as pending for providers with VPCs
+func printCreatedServer(noun string, server *cloud.Server, hasVPC bool) {
+	color.Green("%s [%s] was created!", capitalize(noun), server.Name)
+	fmt.Printf("ID:           %s\n", server.ID)
+	if server.PrivateIPv4 != "" {
+		fmt.Printf("Private IPv4: %s\n", server.PrivateIPv4)
+	} else if hasVPC {
+		fmt.Println("Private IPv4: pending")
+	}
+	color.Cyan("List your %ss in a couple of minutes to see the IP\n", noun)
+}
+
+// orPending returns the IP, or pending when the provider hasn't assigned it yet
+func orPending(ip string) string {
+	if ip == "" {
+		return "pending"
+	}
+	return ip
+}
+
+// capitalize returns the word with its first letter in upper case
+func capitalize(word string) string {
+	if word == "" {
+		return word
+	}
+	return strings.ToUpper(word[:1]) + word[1:]
+}
@@ -2,6 +2,8 @@ package config
 
 import (
 	"errors"
+	"os"
+	"path/filepath"
 	"time"
 
 	"github.com/spf13/viper"
@@ -37,6 +39,29 @@ type Configurations struct {
 // Not entirely sure this is what I want.. I think I want to use an enum
 var PossibleSaveLocations = []string{"$HOME/.cogo", "$HOME/.config/.cogo", "./.cogo"}
 
+// DirEnvVar can be set to keep cogo's own files somewhere other than the user config directory
+const DirEnvVar = "COGO_CONFIG_DIR"
+
+// Dir returns the directory cogo keeps its own files in, creating it if it doesn't exist
+// This is $COGO_CONFIG_DIR when set, otherwise cogo in the user config directory e.g. ~/.config/cogo
+func Dir() (string, error) {
+	dir := os.Getenv(DirEnvVar)
+
+	if dir == "" {
+		userConfigDir, err := os.UserConfigDir()
+		if err != nil {
+			return "", err
+		}
+		dir = filepath.Join(userConfigDir, "cogo")
+	}
+
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return "", err
+	}
+
+	return dir, nil
+}
+
 // AppError is The default config error
 type AppError struct {
 	Error   error
@@ -0,0 +1,98 @@
+package credentials
+
+import (
+	"context"
+	"errors"
+	"os"
+	"strings"
+)
+
+// AccessKey is an access key pair, as used by AWS, instead of a single API token
+// It is kept by the providers as a token in the form ID:SECRET or ID:SECRET:SESSION_TOKEN
+type AccessKey struct {
+	ID           string
+	Secret       string
+	SessionToken string
+}
+
+// ErrInvalidAccessKey is returned when a token isn't in the access key form
+var ErrInvalidAccessKey = errors.New("access key must be in the form ACCESS_KEY_ID:SECRET_ACCESS_KEY")
+
+// ParseAccessKey reads an access key pair from a token in the form ID:SECRET or ID:SECRET:SESSION_TOKEN
+func ParseAccessKey(token string) (AccessKey, error) {
+	parts := strings.SplitN(strings.TrimSpace(token), ":", 3)
+	if len(parts) < 2 || parts[0] == "" || parts[1] == "" {
+		return AccessKey{}, ErrInvalidAccessKey
+	}
+
+	key := AccessKey{ID: parts[0], Secret: parts[1]}
+	if len(parts) == 3 {
+		key.SessionToken = parts[2]
+	}
+
+	return key, nil
+}
+
+// String returns the access key as a token that can be stored by a provider
+func (k AccessKey) String() string {
+	token := k.ID + ":" + k.Secret
+	if k.SessionToken != "" {
+		token += ":" + k.SessionToken
+	}
+	return token
+}
+
+// AccessKeyEnvProvider retrieves an access key pair from separate environment variables
+// e.g. AWS_ACCESS_KEY_ID, AWS_SECRET_ACCESS_KEY and AWS_SESSION_TOKEN
+type AccessKeyEnvProvider struct {
+	idVar      string
+	secretVar  string
+	sessionVar string
+}
+
+// NewAccessKeyEnvProvider creates a new environment variable credential provider for an access key pair
+// sessionVar may be empty if session tokens aren't supported
+func NewAccessKeyEnvProvider(idVar string, secretVar string, sessionVar string) *AccessKeyEnvProvider {
+	return &AccessKeyEnvProvider{
+		idVar:      idVar,
+		secretVar:  secretVar,
+		sessionVar: sessionVar,
+	}
+}
+
+// GetToken retrieves the access key pair from the environment variables as a single token
+func (p *AccessKeyEnvProvider) GetToken(ctx context.Context) (string, error) {
+	if !p.Available() {
+		return "", ErrTokenNotFound
+	}
+
+	key := AccessKey{
+		ID:     os.Getenv(p.idVar),
+		Secret: os.Getenv(p.secretVar),
+	}
+	if p.sessionVar != "" {
+		key.SessionToken = os.Getenv(p.sessionVar)
+	}
+
+	return key.String(), nil
+}
+
+// SetToken is not supported for environment provider (read-only)
+func (p *AccessKeyEnvProvider) SetToken(ctx context.Context, token string) error {
+	return ErrNotSupported
+}
+
+// DeleteToken is not supported for environment provider (read-only)
+func (p *AccessKeyEnvProvider) DeleteToken(ctx context.Context) error {
+	return ErrNotSupported
+}
+
+// Name returns the provider name
+func (p *AccessKeyEnvProvider) Name() string {
+	return "environment"
+}
+
+// Available returns true if both the access key ID and secret are set
+func (p *AccessKeyEnvProvider) Available() bool {
+	return os.Getenv(p.idVar) != "" && os.Getenv(p.secretVar) != ""
+}
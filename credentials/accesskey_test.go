@@ -0,0 +1,123 @@
+package credentials
+
+import (
+	"context"
+	"os"
+	"testing"
+)
+
+func TestParseAccessKey(t *testing.T) {
+	tests := []struct {
+		name        string
+		token       string
+		expected    AccessKey
+		expectError bool
+	}{
+		{
+			name:     "id and secret",
+			token:    "AKIDEXAMPLE:secret",
+			expected: AccessKey{ID: "AKIDEXAMPLE", Secret: "secret"},
+		},
+		{
+			name:     "with session token",
+			token:    "ASIAEXAMPLE:secret:session:with:colons",
+			expected: AccessKey{ID: "ASIAEXAMPLE", Secret: "secret", SessionToken: "session:with:colons"},
+		},
+		{
+			name:        "plain token",
+			token:       "dop_v1_xxx",
+			expectError: true,
+		},
+		{
+			name:        "missing secret",
+			token:       "AKIDEXAMPLE:",
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			key, err := ParseAccessKey(tt.token)
+
+			if tt.expectError {
+				if err == nil {
+					t.Error("expected error, got nil")
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if key != tt.expected {
+				t.Errorf("expected %+v, got %+v", tt.expected, key)
+			}
+			if key.String() != tt.token {
+				t.Errorf("expected String() = %q, got %q", tt.token, key.String())
+			}
+		})
+	}
+}
+
+func TestAccessKeyEnvProvider_GetToken(t *testing.T) {
+	ctx := context.Background()
+
+	// Save original env vars
+	for _, envVar := range []string{"TEST_ACCESS_KEY_ID", "TEST_SECRET_ACCESS_KEY", "TEST_SESSION_TOKEN"} {
+		original := os.Getenv(envVar)
+		defer os.Setenv(envVar, original)
+	}
+
+	tests := []struct {
+		name          string
+		id            string
+		secret        string
+		session       string
+		expectedToken string
+		expectError   bool
+	}{
+		{
+			name:          "id and secret set",
+			id:            "AKIDEXAMPLE",
+			secret:        "secret",
+			expectedToken: "AKIDEXAMPLE:secret",
+		},
+		{
+			name:          "session token set",
+			id:            "ASIAEXAMPLE",
+			secret:        "secret",
+			session:       "session",
+			expectedToken: "ASIAEXAMPLE:secret:session",
+		},
+		{
+			name:        "secret missing",
+			id:          "AKIDEXAMPLE",
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			os.Setenv("TEST_ACCESS_KEY_ID", tt.id)
+			os.Setenv("TEST_SECRET_ACCESS_KEY", tt.secret)
+			os.Setenv("TEST_SESSION_TOKEN", tt.session)
+
+			provider := NewAccessKeyEnvProvider("TEST_ACCESS_KEY_ID", "TEST_SECRET_ACCESS_KEY", "TEST_SESSION_TOKEN")
+			token, err := provider.GetToken(ctx)
+
+			if tt.expectError {
+				if err != ErrTokenNotFound {
+					t.Errorf("expected ErrTokenNotFound, got %v", err)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+			if token != tt.expectedToken {
+				t.Errorf("expected token %q, got %q", tt.expectedToken, token)
+			}
+		})
+	}
+}
@@ -13,16 +13,26 @@ const (
 )
 
 // KeychainProvider retrieves tokens from the OS keychain
-type KeychainProvider struct{}
+type KeychainProvider struct {
+	account string
+}
 
-// NewKeychainProvider creates a new keychain-based credential provider
+// NewKeychainProvider creates a new keychain-based credential provider for the DigitalOcean token
 func NewKeychainProvider() *KeychainProvider {
-	return &KeychainProvider{}
+	return NewKeychainProviderForAccount(keychainAccount)
+}
+
+// NewKeychainProviderForAccount creates a new keychain-based credential provider
+// that stores its token under the given account, e.g. one per cloud provider
+func NewKeychainProviderForAccount(account string) *KeychainProvider {
+	return &KeychainProvider{
+		account: account,
+	}
 }
 
 // GetToken retrieves the token from the OS keychain
 func (p *KeychainProvider) GetToken(ctx context.Context) (string, error) {
-	token, err := keyring.Get(keychainService, keychainAccount)
+	token, err := keyring.Get(keychainService, p.account)
 	if err != nil {
 		if errors.Is(err, keyring.ErrNotFound) {
 			return "", ErrTokenNotFound
@@ -34,12 +44,12 @@ func (p *KeychainProvider) GetToken(ctx context.Context) (string, error) {
 
 // SetToken stores the token in the OS keychain
 func (p *KeychainProvider) SetToken(ctx context.Context, token string) error {
-	return keyring.Set(keychainService, keychainAccount, token)
+	return keyring.Set(keychainService, p.account, token)
 }
 
 // DeleteToken removes the token from the OS keychain
 func (p *KeychainProvider) DeleteToken(ctx context.Context) error {
-	err := keyring.Delete(keychainService, keychainAccount)
+	err := keyring.Delete(keychainService, p.account)
 	if err != nil && errors.Is(err, keyring.ErrNotFound) {
 		return ErrTokenNotFound
 	}
@@ -9,13 +9,23 @@ import (
 
 // PromptProvider retrieves tokens interactively from user input
 type PromptProvider struct {
+	label    string
+	validate func(string) error
 	token    string
 	prompted bool
 }
 
-// NewPromptProvider creates a new interactive prompt credential provider
+// NewPromptProvider creates a new interactive prompt credential provider for the DigitalOcean token
 func NewPromptProvider() *PromptProvider {
-	return &PromptProvider{}
+	return NewPromptProviderWithLabel("Enter your DigitalOcean API Token")
+}
+
+// NewPromptProviderWithLabel creates a new interactive prompt credential provider
+// that asks for the token with the given label
+func NewPromptProviderWithLabel(label string) *PromptProvider {
+	return &PromptProvider{
+		label: label,
+	}
 }
 
 // GetToken prompts the user to enter their token
@@ -25,12 +35,15 @@ func (p *PromptProvider) GetToken(ctx context.Context) (string, error) {
 	}
 
 	prompt := promptui.Prompt{
-		Label: "Enter your DigitalOcean API Token",
+		Label: p.label,
 		Mask:  '*',
 		Validate: func(input string) error {
 			if len(input) == 0 {
 				return fmt.Errorf("token cannot be empty")
 			}
+			if p.validate != nil {
+				return p.validate(input)
+			}
 			return nil
 		},
 	}
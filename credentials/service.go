@@ -0,0 +1,179 @@
+package credentials
+
+import (
+	"context"
+
+	"github.com/fatih/color"
+	"github.com/manifoldco/promptui"
+)
+
+// Service describes where the token for one cloud provider is looked up and stored
+type Service struct {
+	// Name is the human readable name of the cloud provider, used in prompts
+	Name string
+
+	// EnvVars are the environment variables checked for a token, in priority order
+	EnvVars []string
+
+	// KeychainAccount is the OS keychain account the token is stored under
+	KeychainAccount string
+
+	// LegacyFile is true if the token may also be in the legacy .cogo config file
+	LegacyFile bool
+
+	// AccessKeyEnvVars are the access key ID, secret and session token environment variables
+	// Only set for services whose token is an AccessKey
+	AccessKeyEnvVars []string
+}
+
+// DigitalOcean is where the DigitalOcean API token is kept
+var DigitalOcean = Service{
+	Name:            "DigitalOcean",
+	EnvVars:         []string{"DIGITALOCEAN_TOKEN", "COGO_DIGITALOCEAN_TOKEN"},
+	KeychainAccount: keychainAccount,
+	LegacyFile:      true,
+}
+
+// Hetzner is where the Hetzner Cloud API token is kept
+var Hetzner = Service{
+	Name:            "Hetzner Cloud",
+	EnvVars:         []string{"HCLOUD_TOKEN", "COGO_HETZNER_TOKEN"},
+	KeychainAccount: "hetzner-token",
+}
+
+// Linode is where the Linode personal access token is kept
+var Linode = Service{
+	Name:            "Linode",
+	EnvVars:         []string{"LINODE_TOKEN", "COGO_LINODE_TOKEN"},
+	KeychainAccount: "linode-token",
+}
+
+// AWS is where the AWS access key pair is kept
+var AWS = Service{
+	Name:             "AWS",
+	EnvVars:          []string{"COGO_AWS_ACCESS_KEY"},
+	KeychainAccount:  "aws-access-key",
+	AccessKeyEnvVars: []string{"AWS_ACCESS_KEY_ID", "AWS_SECRET_ACCESS_KEY", "AWS_SESSION_TOKEN"},
+}
+
+// IsAccessKey returns true if the service's token is an AccessKey rather than an API token
+func (s Service) IsAccessKey() bool {
+	return len(s.AccessKeyEnvVars) > 0
+}
+
+// TokenLabel is the label used when asking the user for the service's token
+func (s Service) TokenLabel() string {
+	if s.IsAccessKey() {
+		return "Enter your " + s.Name + " access key (ACCESS_KEY_ID:SECRET_ACCESS_KEY)"
+	}
+	return "Enter your " + s.Name + " API Token"
+}
+
+// ValidateToken checks that a token entered by the user is in the form the service expects
+func (s Service) ValidateToken(token string) error {
+	if s.IsAccessKey() {
+		_, err := ParseAccessKey(token)
+		return err
+	}
+	return nil
+}
+
+// NewManager creates a credential manager with the standard provider chain for the service
+// Priority order: CLI flag → Env var → Keychain → Config file → Interactive prompt
+// flagToken is an optional token from CLI flag
+// includePrompt determines whether to include the interactive prompt provider
+func (s Service) NewManager(flagToken string, includePrompt bool) *Manager {
+	providers := []Provider{
+		NewFlagProvider(flagToken),
+		NewEnvProvider(s.EnvVars...),
+	}
+
+	if s.IsAccessKey() {
+		providers = append(providers, NewAccessKeyEnvProvider(s.AccessKeyEnvVars[0], s.AccessKeyEnvVars[1], s.AccessKeyEnvVars[2]))
+	}
+
+	providers = append(providers, NewKeychainProviderForAccount(s.KeychainAccount))
+
+	if s.LegacyFile {
+		providers = append(providers, NewFileProvider())
+	}
+
+	if includePrompt {
+		prompt := NewPromptProviderWithLabel(s.TokenLabel())
+		prompt.validate = s.ValidateToken
+		providers = append(providers, prompt)
+	}
+
+	return NewManager(providers...)
+}
+
+// GetToken retrieves the service's token, prompting for it if it isn't stored anywhere
+// If the token came from the prompt the user is offered to save it
+func (s Service) GetToken(ctx context.Context) (string, error) {
+	token, source, err := s.NewManager("", true).GetToken(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	// If token came from prompt, offer to save it
+	if source.Provider == "prompt" {
+		s.offerToSaveToken(ctx, token)
+	}
+
+	return token, nil
+}
+
+// HasToken returns true if a token can be found without prompting for one
+func (s Service) HasToken(ctx context.Context) bool {
+	_, _, err := s.NewManager("", false).GetToken(ctx)
+	return err == nil
+}
+
+// GetAccessKey retrieves the service's access key pair, prompting for it if it isn't stored anywhere
+func (s Service) GetAccessKey(ctx context.Context) (AccessKey, error) {
+	token, err := s.GetToken(ctx)
+	if err != nil {
+		return AccessKey{}, err
+	}
+
+	return ParseAccessKey(token)
+}
+
+// offerToSaveToken asks the user if they want to save the token they just entered
+func (s Service) offerToSaveToken(ctx context.Context, token string) {
+	prompt := promptui.Prompt{
+		Label:     "Save token securely in keychain for future use?",
+		IsConfirm: true,
+	}
+
+	if _, err := prompt.Run(); err != nil {
+		// User declined
+		return
+	}
+
+	// Try keychain first
+	keychainProvider := NewKeychainProviderForAccount(s.KeychainAccount)
+	if keychainProvider.Available() {
+		if err := keychainProvider.SetToken(ctx, token); err == nil {
+			color.Green("✓ Token saved securely in keychain")
+			return
+		}
+	}
+
+	if s.IsAccessKey() {
+		color.Yellow("⚠  Keychain not available, set %s and %s instead", s.AccessKeyEnvVars[0], s.AccessKeyEnvVars[1])
+		return
+	}
+
+	if !s.LegacyFile {
+		color.Yellow("⚠  Keychain not available, set %s instead", s.EnvVars[0])
+		return
+	}
+
+	// Fallback to file if keychain not available
+	color.Yellow("⚠  Keychain not available, using file storage")
+	fileProvider := NewFileProvider()
+	if err := fileProvider.SetToken(ctx, token); err != nil {
+		color.Red("✗ Failed to save token: %v", err)
+	}
+}
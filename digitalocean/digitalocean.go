@@ -6,36 +6,503 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"github.com/Joel-Valentine/cogo/cloud"
 	"github.com/Joel-Valentine/cogo/credentials"
+	"github.com/Joel-Valentine/cogo/history"
+	"github.com/Joel-Valentine/cogo/userdata"
 	"github.com/Joel-Valentine/cogo/utils"
 	"github.com/digitalocean/godo"
 	"github.com/fatih/color"
 	"github.com/manifoldco/promptui"
+	"slices"
 	"strconv"
+	"strings"
+	"sync"
 )
 
 var imageFork = []utils.SelectItem{{Name: "Distributions", Value: "D"}, {Name: "Applications", Value: "A"}, {Name: "Custom", Value: "C"}}
 
+// userDataLimit is the largest user data DigitalOcean accepts, 64 KiB
+const userDataLimit = 64 * 1024
+
 // CreateDroplet will ask the user a series of questions to determine what kind of
-// droplet they would like to be create
+// droplet they would like to be create. Any answer already given in opts is
+// validated and its question is skipped
 // 1. Asks for a digital ocean api token
 // 2. Asks what name you would like for the droplet
-// 3. Asks what Image you would like to use on the droplet (ubuntu, centos...)
+// 3. Asks what Image you would like to use on the droplet (ubuntu, centos...), distributions by name and then version
 // 4. Asks what size you would like the droplet to be (1gb RAM 1 CPU..)
 // 5. Asks what region you want the droplet to be hosted in (London, Amsterdam...)
-// 6. Asks what SSH Key you would like to use to access the droplet
-// 7. Asks if you are sure with a y/n answer. It will not create a droplet if you chose n
-// Finally the droplet is created and returned
-func CreateDroplet() (*godo.Droplet, error) {
-	digitalOceanToken, tokenError := getToken()
+// 6. Asks which VPC in that region you want the droplet in, if there is more than the default
+// 7. Asks which of backups, monitoring, IPv6 and the droplet agent to turn on
+// 8. Asks whether to attach an existing volume in the region, create a new one or have none
+// 9. Asks which SSH Keys you would like to use to access the droplet
+// 10. Asks which tags to add to the droplet
+// 11. Asks which cloud-init user data template to use, if there are any
+// 12. Shows a summary of the droplet and its estimated monthly cost, then asks if you are sure with a y/n answer
+// It will not create a droplet if you chose n, or opts.DryRun is set, which writes the summary as JSON instead
+// Then any new volume is created, and finally the droplet is created and returned, after waiting for it to become active if opts.Wait is set
+// When waiting fails the created droplet is returned along with the error
+func CreateDroplet(ctx context.Context, client *godo.Client, opts cloud.CreateOptions) (*godo.Droplet, error) {
+	createRequest, volumes, err := askCreateRequest(ctx, client, opts, false)
+
+	if err != nil {
+		return nil, err
+	}
+
+	summary, err := createSummary(ctx, client, createRequest, volumes, []string{createRequest.Name}, []string{createRequest.Region})
+
+	if err != nil {
+		return nil, err
+	}
+
+	shouldCreate, err := cloud.ReviewCreate(summary, "droplet", opts)
+
+	if err != nil || !shouldCreate {
+		return nil, err
+	}
+
+	if volumes.New != nil {
+		newVolume, _, err := client.Storage.CreateVolume(ctx, &godo.VolumeCreateRequest{
+			Region:          createRequest.Region,
+			Name:            volumes.New.Name,
+			SizeGigaBytes:   volumes.New.SizeGiB,
+			FilesystemType:  volumes.New.FilesystemType,
+			FilesystemLabel: volumes.New.FilesystemLabel,
+		})
+
+		if err != nil {
+			return nil, fmt.Errorf("failed to create volume %s: %w", volumes.New.Name, err)
+		}
+
+		color.Green("Volume [%s] was created!", newVolume.Name)
+
+		createRequest.Volumes = append(createRequest.Volumes, godo.DropletCreateVolume{ID: newVolume.ID})
+	}
+
+	newDroplet, _, createDropletError := client.Droplets.Create(ctx, createRequest)
+
+	if createDropletError != nil && volumes.New != nil {
+		color.Yellow("Volume [%s] was created but not attached, delete it if you don't need it", volumes.New.Name)
+	}
+
+	if createDropletError == nil {
+		rememberCreateRequest(opts, createRequest)
+	}
+
+	if createDropletError != nil || !opts.Wait {
+		return newDroplet, createDropletError
+	}
+
+	color.Green("Droplet [%s] was created!", newDroplet.Name)
+
+	activeDroplet, err := waitForDroplet(ctx, client, newDroplet.ID, opts.WaitTimeout)
+
+	// the droplet exists even if waiting for it failed, so it is returned along with the error
+	if err != nil {
+		return newDroplet, err
+	}
+
+	return activeDroplet, nil
+}
+
+// askCreateRequest asks the CreateDroplet questions up to the y/n, skipping any answered in opts
+// When creating several droplets (bulk) the name is only asked for without a name template,
+// the VPC is only asked for when they all go in one region, no volumes are asked for and the
+// user data is left for the caller to render for each droplet
+// returns the create request along with the volumes to attach to the droplet
+func askCreateRequest(ctx context.Context, client *godo.Client, opts cloud.CreateOptions, bulk bool) (*godo.DropletCreateRequest, *cloud.VolumeAnswers, error) {
+	dropletName := ""
+
+	// Droplets named from a template don't need a name
+	if !bulk || opts.NameTemplate == "" {
+		name, err := getDropletName(opts.Name)
+
+		if err != nil {
+			return nil, nil, err
+		}
+
+		dropletName = name
+	}
+
+	// The image and size are asked before the region, so they can only be narrowed down by regions given up front
+	givenRegions := opts.Regions
+
+	if len(givenRegions) == 0 && opts.Region != "" {
+		givenRegions = []string{opts.Region}
+	}
+
+	recent := cloud.OpenHistory(opts)
+
+	var selectedImage godo.Image
+
+	if opts.Image != "" {
+		image, err := findImage(ctx, client, opts.Image, givenRegions)
+
+		if err != nil {
+			return nil, nil, err
+		}
+
+		selectedImage = image
+	} else {
+		image, err := getSelectedImage(ctx, client, givenRegions, recent.Recent(providerKey, history.Image))
+
+		if err != nil {
+			return nil, nil, err
+		}
+
+		selectedImage = image
+	}
+
+	selectedSize := opts.Size
+
+	if selectedSize != "" {
+		if err := validateSize(ctx, client, selectedSize, givenRegions); err != nil {
+			return nil, nil, err
+		}
+	} else {
+		selected, err := getSelectedSizeSlug(ctx, client, givenRegions, selectedImage, opts, recent.Recent(providerKey, history.Size))
+
+		if err != nil {
+			fmt.Printf("Failed to get size slug: %s", err)
+			return nil, nil, err
+		}
+
+		selectedSize = selected
+	}
+
+	regions, checkRegion, err := regionCheck(ctx, client, selectedSize, selectedImage, opts)
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	selectedRegion := opts.Region
+
+	if len(opts.Regions) > 0 {
+		for _, region := range opts.Regions {
+			if err := validateRegion(region, regions, checkRegion); err != nil {
+				return nil, nil, err
+			}
+		}
+
+		selectedRegion = opts.Regions[0]
+	} else if selectedRegion != "" {
+		if err := validateRegion(selectedRegion, regions, checkRegion); err != nil {
+			return nil, nil, err
+		}
+	} else {
+		selected, err := getSelectedRegionSlug(regions, checkRegion, recent.Recent(providerKey, history.Region))
+
+		if err != nil {
+			fmt.Printf("Failed to get region slug: %s", err)
+			return nil, nil, err
+		}
 
-	if tokenError != nil {
-		return nil, tokenError
+		selectedRegion = selected
 	}
 
-	client := godo.NewFromToken(digitalOceanToken)
+	selectedVPC := ""
 
-	ctx := context.TODO()
+	// A VPC only belongs to one region so droplets spread across regions go in each region's default
+	if len(opts.Regions) <= 1 {
+		vpc, err := cloud.AskVPC(ctx, opts, selectedRegion, func(ctx context.Context, region string) ([]utils.SelectItem, error) {
+			return vpcList(ctx, client, region)
+		})
+
+		if err != nil {
+			return nil, nil, err
+		}
+
+		selectedVPC = vpc
+	}
+
+	features, err := cloud.AskFeatures(ctx, opts, selectedSize, func(ctx context.Context, size string) ([]utils.SelectItem, error) {
+		return featureList(ctx, client, size, selectedRegion)
+	})
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	volumes := &cloud.VolumeAnswers{Existing: []string{}}
+
+	// A volume can only be attached to one droplet
+	if !bulk {
+		volumes, err = cloud.AskVolumes(ctx, opts, dropletName, selectedRegion, func(ctx context.Context, region string) ([]utils.SelectItem, error) {
+			return volumeList(ctx, client, region)
+		})
+
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+
+	var sshKeys []godo.DropletCreateSSHKey
+
+	if len(opts.SSHKeys) > 0 {
+		sshKeys, err = resolveSSHKeys(ctx, client, opts.SSHKeys)
+
+		if err != nil {
+			return nil, nil, err
+		}
+	} else {
+		sshKeys, err = getSelectedSSHKeys(ctx, client, recent.Recent(providerKey, history.SSHKeys))
+
+		if err != nil {
+			fmt.Printf("Failed to get SSH keys: %s", err)
+			return nil, nil, err
+		}
+	}
+
+	tags, err := cloud.AskTags(opts)
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	userData := ""
+
+	// Several droplets each have their user data rendered with their own name and region once they are planned
+	if !bulk {
+		userData, err = cloud.AskUserData(opts, userdata.Vars{Name: dropletName, Region: selectedRegion, Size: selectedSize, Image: utils.ImageValue(selectedImage)}, userDataLimit)
+
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+
+	createRequest := &godo.DropletCreateRequest{
+		Name:     dropletName,
+		Region:   selectedRegion,
+		Size:     selectedSize,
+		VPCUUID:  selectedVPC,
+		SSHKeys:  sshKeys,
+		Tags:     tags,
+		UserData: userData,
+		// Custom images and snapshots without a slug are created from their ID
+		Image: godo.DropletCreateImage{
+			ID:   selectedImage.ID,
+			Slug: selectedImage.Slug,
+		},
+	}
+
+	setFeatures(createRequest, features)
+
+	for _, volumeID := range volumes.Existing {
+		createRequest.Volumes = append(createRequest.Volumes, godo.DropletCreateVolume{ID: volumeID})
+	}
+
+	return createRequest, volumes, nil
+}
+
+// CreateDroplets will ask the same questions as CreateDroplet once, then create opts.Count droplets
+// named from opts.NameTemplate and spread round-robin across opts.Regions, or all in the chosen region
+// The name and region of each droplet and a summary of their estimated cost is shown before asking if you are sure
+// returns how creating each droplet went, after waiting for them to become active if opts.Wait is set
+func CreateDroplets(ctx context.Context, client *godo.Client, opts cloud.CreateOptions) ([]cloud.CreateResult, error) {
+	createRequest, _, err := askCreateRequest(ctx, client, opts, true)
+
+	if err != nil {
+		return nil, err
+	}
+
+	userDataFile, err := cloud.AskUserDataFile(opts)
+
+	if err != nil {
+		return nil, err
+	}
+
+	nameTemplate := opts.NameTemplate
+
+	if nameTemplate == "" {
+		nameTemplate = cloud.DefaultNameTemplate(createRequest.Name)
+	}
+
+	regions := opts.Regions
+
+	if len(regions) == 0 {
+		regions = []string{createRequest.Region}
+	}
+
+	plan, err := cloud.PlanBulkCreate(nameTemplate, opts.Count, regions)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if userDataFile != "" {
+		for index, planned := range plan {
+			vars := userdata.Vars{Name: planned.Name, Region: planned.Region, Size: createRequest.Size, Image: requestImage(createRequest)}
+
+			plan[index].UserData, err = userdata.Load(userDataFile, vars, userDataLimit)
+
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	names := []string{}
+
+	if !opts.DryRun {
+		color.Cyan("%d droplets will be created:", len(plan))
+	}
+
+	for _, planned := range plan {
+		names = append(names, planned.Name)
+
+		if !opts.DryRun {
+			fmt.Printf("  %s (%s)\n", planned.Name, planned.Region)
+		}
+	}
+
+	summary, err := createSummary(ctx, client, createRequest, &cloud.VolumeAnswers{}, names, regions)
+
+	if err != nil {
+		return nil, err
+	}
+
+	shouldCreate, err := cloud.ReviewCreate(summary, "droplet", opts)
+
+	if err != nil || !shouldCreate {
+		return nil, err
+	}
+
+	results := createDroplets(ctx, client, createRequest, plan)
+
+	// the answers are only remembered once they have made a droplet
+	for _, result := range results {
+		if result.Err == nil {
+			rememberCreateRequest(opts, createRequest)
+			break
+		}
+	}
+
+	if !opts.Wait {
+		return results, nil
+	}
+
+	waitForDroplets(ctx, client, results, opts.WaitTimeout)
+
+	return results, nil
+}
+
+// maxCreateMultiple is the most droplets DigitalOcean will create in one request
+const maxCreateMultiple = 10
+
+// createBatchSize is how many droplets are created at the same time when they can't be created in one request
+const createBatchSize = 5
+
+// createDroplets creates a droplet from createRequest for each of the planned names, regions and user data
+// When they are all in one region with the same user data they are created with CreateMultiple, otherwise
+// they are created one request each, createBatchSize at a time
+// returns how creating each droplet went, in the same order as plan
+func createDroplets(ctx context.Context, client *godo.Client, createRequest *godo.DropletCreateRequest, plan []cloud.BulkServer) []cloud.CreateResult {
+	results := []cloud.CreateResult{}
+	identical := true
+
+	for _, planned := range plan {
+		results = append(results, cloud.CreateResult{Name: planned.Name, Region: planned.Region})
+		identical = identical && planned.Region == plan[0].Region && planned.UserData == plan[0].UserData
+	}
+
+	if identical {
+		request := *createRequest
+		request.UserData = plan[0].UserData
+
+		for start := 0; start < len(results); start += maxCreateMultiple {
+			createMultipleDroplets(ctx, client, &request, results[start:min(start+maxCreateMultiple, len(results))])
+		}
+
+		return results
+	}
+
+	var wg sync.WaitGroup
+	batch := make(chan struct{}, createBatchSize)
+
+	for index := range results {
+		wg.Add(1)
+
+		go func(result *cloud.CreateResult, planned cloud.BulkServer) {
+			defer wg.Done()
+
+			batch <- struct{}{}
+			defer func() { <-batch }()
+
+			request := *createRequest
+			request.Name = planned.Name
+			request.Region = planned.Region
+			request.UserData = planned.UserData
+
+			droplet, _, err := client.Droplets.Create(ctx, &request)
+
+			if err != nil {
+				result.Err = err
+				return
+			}
+
+			server := dropletToServer(*droplet)
+			result.Server = &server
+		}(&results[index], plan[index])
+	}
+
+	wg.Wait()
+
+	return results
+}
+
+// createMultipleDroplets creates a droplet for each result in one CreateMultiple request
+// they must all be in the same region and there can be at most maxCreateMultiple of them
+func createMultipleDroplets(ctx context.Context, client *godo.Client, createRequest *godo.DropletCreateRequest, results []cloud.CreateResult) {
+	names := []string{}
+	for _, result := range results {
+		names = append(names, result.Name)
+	}
+
+	droplets, _, err := client.Droplets.CreateMultiple(ctx, &godo.DropletMultiCreateRequest{
+		Names:            names,
+		Region:           results[0].Region,
+		Size:             createRequest.Size,
+		Image:            createRequest.Image,
+		SSHKeys:          createRequest.SSHKeys,
+		Backups:          createRequest.Backups,
+		IPv6:             createRequest.IPv6,
+		Monitoring:       createRequest.Monitoring,
+		UserData:         createRequest.UserData,
+		Tags:             createRequest.Tags,
+		VPCUUID:          createRequest.VPCUUID,
+		WithDropletAgent: createRequest.WithDropletAgent,
+	})
+
+	for index := range results {
+		if err != nil {
+			results[index].Err = err
+			continue
+		}
+
+		for _, droplet := range droplets {
+			if droplet.Name == results[index].Name {
+				server := dropletToServer(droplet)
+				results[index].Server = &server
+			}
+		}
+
+		if results[index].Server == nil {
+			results[index].Err = fmt.Errorf("droplet %s was missing from the create response", results[index].Name)
+		}
+	}
+}
+
+// getDropletName will validate the given name, or ask the user for one when it is empty
+func getDropletName(name string) (string, error) {
+	if name != "" {
+		if err := utils.ValidateDropletName(name); err != nil {
+			return "", fmt.Errorf("invalid droplet name %q: %w", name, err)
+		}
+
+		return name, nil
+	}
 
 	promptDropletName := promptui.Prompt{
 		Label:    "Droplet Name",
@@ -46,116 +513,322 @@ func CreateDroplet() (*godo.Droplet, error) {
 
 	if promptDropletError != nil {
 		fmt.Printf("Droplet name prompt failed %v\n", promptDropletError)
-		return nil, promptDropletError
+		return "", promptDropletError
+	}
+
+	return dropletName, nil
+}
+
+// getSelectedImage will ask whether to pick a distribution, application or custom image
+// then asks the user to chose an image of that type that is in every one of the regions,
+// distributions are picked in two steps, the distribution and then its version
+// The recent images are offered above the image types so the last one can be picked straight away
+// returns the chosen image
+func getSelectedImage(ctx context.Context, client *godo.Client, regions []string, recent []string) (godo.Image, error) {
+	recentImages := []godo.Image{}
+
+	if len(recent) > 0 {
+		for _, imageType := range imageFork {
+			images, err := imageTypeList(ctx, client, imageType.Value)
+
+			if err != nil {
+				fmt.Printf("Something bad happened getting image list: %s\n\n", err)
+				return godo.Image{}, err
+			}
+
+			for _, image := range utils.FilterImages(images, regions) {
+				if slices.Contains(recent, utils.ImageValue(image)) {
+					recentImages = append(recentImages, image)
+				}
+			}
+		}
 	}
 
-	distAppCustom, distAppCustomErr := utils.AskAndAnswerCustomSelect("Select Image Type", imageFork)
+	imageTypes := append(utils.PinRecent(utils.ParseImageListResults(recentImages), recent), imageFork...)
+
+	distAppCustom, distAppCustomErr := utils.AskAndAnswerCustomSelect("Select Image Type", imageTypes)
 
 	if distAppCustomErr != nil {
 		fmt.Printf("Could not get Image or Distribtion %v\n", distAppCustomErr)
-		return nil, distAppCustomErr
+		return godo.Image{}, distAppCustomErr
 	}
 
-	var selectedImage string
+	for _, image := range recentImages {
+		if utils.ImageValue(image) == distAppCustom {
+			return image, nil
+		}
+	}
 
-	if distAppCustom == "A" {
-		selected, err := getSelectedImageApplicationSlug(ctx, client)
+	images, imageListError := imageTypeList(ctx, client, distAppCustom)
 
-		if err != nil {
-			fmt.Printf("Failed to get application image slug: %s", err)
-			return nil, err
-		}
+	if imageListError != nil {
+		fmt.Printf("Something bad happened getting image list: %s\n\n", imageListError)
+		return godo.Image{}, imageListError
+	}
+
+	images = utils.FilterImages(images, regions)
 
-		selectedImage = selected
+	if len(images) == 0 {
+		return godo.Image{}, fmt.Errorf("no images of that type are available in %s", strings.Join(regions, ", "))
 	}
 
+	imageList := utils.ParseImageListResults(images)
+
 	if distAppCustom == "D" {
-		selected, err := getSelectedImageDistributionSlug(ctx, client)
+		recentDistributions := []string{}
+		for _, value := range recent {
+			for _, image := range images {
+				if utils.ImageValue(image) == value {
+					recentDistributions = append(recentDistributions, image.Distribution)
+				}
+			}
+		}
+
+		distribution, err := utils.AskAndAnswerCustomSelect("Distribution Select", utils.PinRecent(utils.ParseImageDistributionList(images), recentDistributions))
 
 		if err != nil {
-			fmt.Printf("Failed to get distribution image slug: %s", err)
-			return nil, err
+			fmt.Printf("Failed to ask distribution question, %s", err)
+			return godo.Image{}, err
+		}
+
+		imageList = utils.ParseImageVersionList(images, distribution)
+	}
+
+	selectedImage, err := utils.AskAndAnswerCustomSelect("Image Select", utils.PinRecent(imageList, recent))
+
+	if err != nil {
+		fmt.Printf("Failed to ask image question, %s", err)
+		return godo.Image{}, err
+	}
+
+	for _, image := range images {
+		if utils.ImageValue(image) == selectedImage {
+			return image, nil
 		}
+	}
+
+	return godo.Image{}, fmt.Errorf("image %q is not available", selectedImage)
+}
 
-		selectedImage = selected
+// rememberCreateRequest puts the image, size, regions and SSH keys of a create request that made a droplet
+// at the front of the wizard history, unless opts.NoHistory is set
+func rememberCreateRequest(opts cloud.CreateOptions, createRequest *godo.DropletCreateRequest) {
+	recent := cloud.OpenHistory(opts)
+
+	regions := opts.Regions
+	if len(regions) == 0 {
+		regions = []string{createRequest.Region}
+	}
+
+	recent.Remember(providerKey, history.Image, requestImage(createRequest))
+	recent.Remember(providerKey, history.Size, createRequest.Size)
+	recent.Remember(providerKey, history.Region, regions...)
+	recent.Remember(providerKey, history.SSHKeys, requestSSHKeys(createRequest)...)
+	cloud.SaveHistory(recent)
+}
+
+// createSummary returns the summary of the droplets named names that the create request will make
+// in regions, priced from the size list
+func createSummary(ctx context.Context, client *godo.Client, createRequest *godo.DropletCreateRequest, volumes *cloud.VolumeAnswers, names []string, regions []string) (cloud.CreateSummary, error) {
+	sizes, err := allSizes(ctx, client)
+
+	if err != nil {
+		return cloud.CreateSummary{}, fmt.Errorf("failed to get size list: %w", err)
 	}
 
-	if distAppCustom == "C" {
-		selected, err := getSelectedCustomImageSlug(ctx, client)
+	summary := cloud.CreateSummary{
+		Provider:  providerKey,
+		Names:     names,
+		Image:     requestImage(createRequest),
+		Size:      createRequest.Size,
+		Regions:   regions,
+		VPC:       createRequest.VPCUUID,
+		Features:  requestFeatures(createRequest),
+		SSHKeys:   requestSSHKeys(createRequest),
+		Tags:      createRequest.Tags,
+		Volumes:   volumes.Existing,
+		NewVolume: volumes.New,
+	}
+
+	for _, size := range sizes {
+		if size.Slug == createRequest.Size {
+			summary.SetPrice(size.PriceHourly, size.PriceMonthly, surcharges(summary, size.PriceMonthly))
+		}
+	}
+
+	return summary, nil
+}
+
+// surcharges returns what backups and a new volume add to the monthly cost of the droplets in the summary
+// Backups cost utils.BackupPriceRate of each droplet's monthly price and a new volume
+// costs utils.VolumePricePerGiB a month for each GiB
+func surcharges(summary cloud.CreateSummary, monthly float64) []cloud.Surcharge {
+	count := float64(max(len(summary.Names), 1))
+	surcharges := []cloud.Surcharge{}
+
+	if slices.Contains(summary.Features, utils.FeatureBackups) {
+		surcharges = append(surcharges, cloud.Surcharge{Name: "backups", PriceMonthly: monthly * utils.BackupPriceRate * count})
+	}
+
+	if summary.NewVolume != nil {
+		surcharges = append(surcharges, cloud.Surcharge{Name: "volume", PriceMonthly: float64(summary.NewVolume.SizeGiB) * utils.VolumePricePerGiB})
+	}
+
+	return surcharges
+}
+
+// requestImage returns the slug of the create request's image, or its ID when it has no slug
+func requestImage(createRequest *godo.DropletCreateRequest) string {
+	if createRequest.Image.Slug != "" {
+		return createRequest.Image.Slug
+	}
+
+	return strconv.Itoa(createRequest.Image.ID)
+}
+
+// requestSSHKeys returns the fingerprint, or ID, of each SSH key in the create request
+func requestSSHKeys(createRequest *godo.DropletCreateRequest) []string {
+	sshKeys := []string{}
+
+	for _, key := range createRequest.SSHKeys {
+		if key.Fingerprint != "" {
+			sshKeys = append(sshKeys, key.Fingerprint)
+		} else {
+			sshKeys = append(sshKeys, strconv.Itoa(key.ID))
+		}
+	}
+
+	return sshKeys
+}
+
+// regionCheck returns every region along with a check of whether a droplet of the size can be created
+// from the image in a region, with the features, volumes and VPC given in opts. An empty size isn't checked
+func regionCheck(ctx context.Context, client *godo.Client, sizeSlug string, image godo.Image, opts cloud.CreateOptions) ([]godo.Region, func(godo.Region) error, error) {
+	regions, err := allRegions(ctx, client)
+
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get region list: %w", err)
+	}
+
+	sizes, err := allSizes(ctx, client)
+
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get size list: %w", err)
+	}
+
+	size := godo.Size{Slug: sizeSlug}
+	for _, candidate := range sizes {
+		if candidate.Slug == sizeSlug {
+			size = candidate
+		}
+	}
+
+	features := utils.RegionFeatures(opts.Features)
+
+	if len(opts.Volumes) > 0 || opts.NewVolume != nil {
+		features = append(features, utils.RegionFeatureStorage)
+	}
+
+	// A VPC only belongs to one region
+	vpcRegion := ""
+
+	if opts.VPC != "" {
+		vpc, _, err := client.VPCs.Get(ctx, opts.VPC)
 
 		if err != nil {
-			fmt.Printf("Failed to get custom image slug: %s", err)
-			return nil, err
+			return nil, nil, fmt.Errorf("failed to get VPC %q: %w", opts.VPC, err)
 		}
 
-		selectedImage = selected
+		vpcRegion = vpc.RegionSlug
 	}
 
-	selectedSize, err := getSelectedSizeSlug(ctx, client)
+	check := func(region godo.Region) error {
+		if err := utils.CheckRegion(region, size, features); err != nil {
+			return err
+		}
+
+		if len(utils.FilterImages([]godo.Image{image}, []string{region.Slug})) == 0 {
+			return fmt.Errorf("image %q is not available in %s", utils.ImageValue(image), region.Slug)
+		}
+
+		if vpcRegion != "" && region.Slug != vpcRegion {
+			return fmt.Errorf("VPC %q is in %s, not %s", opts.VPC, vpcRegion, region.Slug)
+		}
+
+		return nil
+	}
+
+	return regions, check, nil
+}
+
+// validateRegion checks that the region exists and passes the check from regionCheck
+func validateRegion(slug string, regions []godo.Region, check func(godo.Region) error) error {
+	for _, region := range regions {
+		if region.Slug == slug {
+			return check(region)
+		}
+	}
+
+	return fmt.Errorf("region %q is not available", slug)
+}
+
+// validateSize checks that the size is available and offered in every one of the regions
+func validateSize(ctx context.Context, client *godo.Client, slug string, regions []string) error {
+	sizes, err := allSizes(ctx, client)
 
 	if err != nil {
-		fmt.Printf("Failed to get size slug: %s", err)
-		return nil, err
+		return fmt.Errorf("failed to get size list: %w", err)
 	}
 
-	selectedRegion, err := getSelectedRegionSlug(ctx, client)
+	for _, size := range utils.FilterSizes(sizes, nil) {
+		if size.Slug != slug {
+			continue
+		}
+
+		if len(utils.FilterSizes([]godo.Size{size}, regions)) == 0 {
+			return fmt.Errorf("size %q is not offered in %s", slug, strings.Join(regions, ", "))
+		}
 
-	if err != nil {
-		fmt.Printf("Failed to get region slug: %s", err)
-		return nil, err
+		return nil
 	}
 
-	sshKeyID, err := getSelectedSSHKeyID(ctx, client)
+	return fmt.Errorf("size %q is not available", slug)
+}
 
-	if err != nil {
-		fmt.Printf("Failed to get SSH key ID: %s", err)
-		return nil, err
-	}
+// findImage returns the distribution, application or custom image with the slug or ID
+// the image has to be in every one of the regions
+func findImage(ctx context.Context, client *godo.Client, value string, regions []string) (godo.Image, error) {
+	for _, imageType := range imageFork {
+		images, err := imageTypeList(ctx, client, imageType.Value)
 
-	shouldCreate, err := confirmCreate("Are you sure? (y/n)")
+		if err != nil {
+			return godo.Image{}, fmt.Errorf("failed to get image list: %w", err)
+		}
 
-	if err != nil {
-		return nil, err
-	}
+		for _, image := range utils.FilterImages(images, nil) {
+			if image.Slug != value && strconv.Itoa(image.ID) != value {
+				continue
+			}
 
-	if !shouldCreate {
-		return nil, err
-	}
+			if len(utils.FilterImages([]godo.Image{image}, regions)) == 0 {
+				return godo.Image{}, fmt.Errorf("image %q is not available in %s", value, strings.Join(regions, ", "))
+			}
 
-	createRequest := &godo.DropletCreateRequest{
-		Name:   dropletName,
-		Region: selectedRegion,
-		Size:   selectedSize,
-		SSHKeys: []godo.DropletCreateSSHKey{
-			{ID: sshKeyID},
-		},
-		Image: godo.DropletCreateImage{
-			Slug: selectedImage,
-		},
+			return image, nil
+		}
 	}
 
-	newDroplet, _, createDropletError := client.Droplets.Create(ctx, createRequest)
-
-	return newDroplet, createDropletError
+	return godo.Image{}, fmt.Errorf("image %q is not available", value)
 }
 
 // DestroyDroplet will show the user a list of servers
 // upon selecting the server you will have to confirm with y/n
 // Once confirmed the user will then have to type in the name of the droplet to make sure they are aware of what they're deleting
 // Once entered they will have to do another y/n to confirm that they definitely want it gone
-// The deleted droplets name is returned
-func DestroyDroplet() (*utils.SelectItem, error) {
-	digitalOceanToken, tokenError := getToken()
-
-	if tokenError != nil {
-		return nil, tokenError
-	}
-
-	client := godo.NewFromToken(digitalOceanToken)
-
-	ctx := context.TODO()
-
-	droplets, err := dropletList(ctx, client)
+// Any of these steps can be answered up front with opts, without ever skipping the name check
+// The deleted droplet is returned
+func DestroyDroplet(ctx context.Context, client *godo.Client, opts cloud.DestroyOptions) (*godo.Droplet, error) {
+	droplets, err := dropletList(ctx, client, "")
 
 	if err != nil {
 		return nil, err
@@ -163,9 +836,7 @@ func DestroyDroplet() (*utils.SelectItem, error) {
 
 	selectItemDroplets := utils.ParseDropletListResults(droplets)
 
-	selectDropletPrompt := utils.CreateCustomSelectPrompt("Select droplet to delete", selectItemDroplets)
-
-	selectedDropletIndex, _, err := selectDropletPrompt.Run()
+	selectedDropletIndex, err := getSelectedDropletIndex(droplets, opts.ServerSelector, "Select droplet to delete")
 
 	if err != nil {
 		return nil, err
@@ -179,27 +850,33 @@ func DestroyDroplet() (*utils.SelectItem, error) {
 		return nil, err
 	}
 
-	areYouSure, err := confirmCreate("Are you sure? (y/n)")
+	if !opts.Yes {
+		areYouSure, err := confirmCreate("Are you sure? (y/n)")
 
-	if err != nil {
-		fmt.Printf("Something went wrong asking you to confirm: %s", err)
-		return nil, err
-	}
+		if err != nil {
+			fmt.Printf("Something went wrong asking you to confirm: %s", err)
+			return nil, err
+		}
 
-	if !areYouSure {
-		fmt.Println("You decided not to delete this droplet")
-		return nil, err
+		if !areYouSure {
+			fmt.Println("You decided not to delete this droplet")
+			return nil, err
+		}
 	}
 
-	promptReEnterDropletName := promptui.Prompt{
-		Label: "Re enter droplet name to confirm delete (WARNING DROPLET WILL BE DELETED FOREVER)",
-	}
+	enteredDropletName := opts.ConfirmName
 
-	enteredDropletName, err := promptReEnterDropletName.Run()
+	if enteredDropletName == "" {
+		promptReEnterDropletName := promptui.Prompt{
+			Label: "Re enter droplet name to confirm delete (WARNING DROPLET WILL BE DELETED FOREVER)",
+		}
 
-	if err != nil {
-		fmt.Printf("Droplet name prompt failed %v\n", err)
-		return nil, err
+		enteredDropletName, err = promptReEnterDropletName.Run()
+
+		if err != nil {
+			fmt.Printf("Droplet name prompt failed %v\n", err)
+			return nil, err
+		}
 	}
 
 	// Validate after submission instead of on every keystroke
@@ -222,16 +899,18 @@ func DestroyDroplet() (*utils.SelectItem, error) {
 
 	color.Cyan("Name: %s\nSize: %s\nRegion: %s\nImage: %s\nIP: %s", fullDropletInfo.Name, fullDropletInfo.Size.Slug, fullDropletInfo.Region.Name, fullDropletInfo.Image.Name, selectedDropletIP)
 
-	areYouReallyReallySure, err := confirmCreate("Are you really really sure you want to delete this droplet? (y/n)")
+	if !opts.Yes {
+		areYouReallyReallySure, err := confirmCreate("Are you really really sure you want to delete this droplet? (y/n)")
 
-	if err != nil {
-		fmt.Printf("Something went wrong asking you to confirm deletion: %s", err)
-		return nil, err
-	}
+		if err != nil {
+			fmt.Printf("Something went wrong asking you to confirm deletion: %s", err)
+			return nil, err
+		}
 
-	if !areYouReallyReallySure {
-		fmt.Println("You decided not to delete this droplet")
-		return nil, err
+		if !areYouReallyReallySure {
+			fmt.Println("You decided not to delete this droplet")
+			return nil, err
+		}
 	}
 
 	if _, err := client.Droplets.Delete(ctx, selectedDropletID); err != nil {
@@ -244,106 +923,103 @@ func DestroyDroplet() (*utils.SelectItem, error) {
 		return nil, errors.New("Incorrect droplet name")
 	}
 
-	return &selectedDroplet, nil
+	return &fullDropletInfo, nil
+}
+
+// getSelectedDropletIndex will find the droplet described by selector in the list of droplets
+// if the selector is empty the user is asked to select one with the given label
+// returns the index of the droplet in droplets
+func getSelectedDropletIndex(droplets []godo.Droplet, selector cloud.ServerSelector, label string) (int, error) {
+	servers := []cloud.Server{}
+	for _, droplet := range droplets {
+		servers = append(servers, dropletToServer(droplet))
+	}
+
+	return cloud.SelectServerIndex(servers, selector, label)
 }
 
 // getToken retrieves the DigitalOcean API token using the modern credential manager
 // Priority order: CLI flag → Env var → Keychain → Config file → Interactive prompt
-func getToken() (string, error) {
-	ctx := context.TODO()
-
-	// Create credential manager with all providers including prompt
-	manager := credentials.NewManager(
-		credentials.NewFlagProvider(""), // Flag support for future use
-		credentials.NewEnvProvider(),
-		credentials.NewKeychainProvider(),
-		credentials.NewFileProvider(),
-		credentials.NewPromptProvider(),
-	)
-
-	token, source, err := manager.GetToken(ctx)
+func getToken(ctx context.Context) (string, error) {
+	token, err := credentials.DigitalOcean.GetToken(ctx)
 	if err != nil {
 		return "", fmt.Errorf("failed to get token: %w", err)
 	}
 
-	// If token came from prompt, offer to save it
-	if source.Provider == "prompt" {
-		offerToSaveToken(ctx, token)
-	}
-
 	return token, nil
 }
 
-// offerToSaveToken asks the user if they want to save the token they just entered
-func offerToSaveToken(ctx context.Context, token string) {
-	prompt := promptui.Prompt{
-		Label:     "Save token securely in keychain for future use?",
-		IsConfirm: true,
-	}
-
-	if _, err := prompt.Run(); err != nil {
-		// User declined
-		return
-	}
+// ListDroplets gets all the droplets on the account, or only those with the tag when it isn't empty
+func ListDroplets(ctx context.Context, client *godo.Client, tag string) ([]godo.Droplet, error) {
+	dropletList, dropletListError := dropletList(ctx, client, tag)
 
-	// Try keychain first
-	keychainProvider := credentials.NewKeychainProvider()
-	if keychainProvider.Available() {
-		if err := keychainProvider.SetToken(ctx, token); err == nil {
-			color.Green("✓ Token saved securely in keychain")
-			return
-		}
+	if dropletListError != nil {
+		return nil, fmt.Errorf("unable to get a list of droplets: %w", dropletListError)
 	}
 
-	// Fallback to file if keychain not available
-	color.Yellow("⚠  Keychain not available, using file storage")
-	fileProvider := credentials.NewFileProvider()
-	if err := fileProvider.SetToken(ctx, token); err != nil {
-		color.Red("✗ Failed to save token: %v", err)
-	}
+	return dropletList, nil
 }
 
-// DisplayDropletList gets all the droplets and formats it with some colours.
-// Finally priting it to the terminal
-func DisplayDropletList() {
-	digitalOceanToken, tokenError := getToken()
-
-	if tokenError != nil {
-		fmt.Println("Unable to get DigitalOcean API Token")
+// dropletToServer converts a droplet into the provider independent output format
+func dropletToServer(droplet godo.Droplet) cloud.Server {
+	publicIPv4, _ := droplet.PublicIPv4()
+	privateIPv4, _ := droplet.PrivateIPv4()
+	publicIPv6, _ := droplet.PublicIPv6()
+
+	server := cloud.Server{
+		ID:          strconv.Itoa(droplet.ID),
+		Name:        droplet.Name,
+		Status:      droplet.Status,
+		Size:        droplet.SizeSlug,
+		PublicIPv4:  publicIPv4,
+		PrivateIPv4: privateIPv4,
+		PublicIPv6:  publicIPv6,
+		Tags:        droplet.Tags,
+		VPC:         droplet.VPCUUID,
+		CreatedAt:   droplet.Created,
 	}
 
-	client := godo.NewFromToken(digitalOceanToken)
-
-	ctx := context.TODO()
+	if server.Tags == nil {
+		server.Tags = []string{}
+	}
 
-	dropletList, dropletListError := dropletList(ctx, client)
+	if droplet.Region != nil {
+		server.Region = droplet.Region.Slug
+	}
 
-	if dropletListError != nil {
-		fmt.Println("Unable to get a list of droplets")
+	if droplet.Size != nil {
+		server.PriceMonthly = droplet.Size.PriceMonthly
 	}
 
-	color.Green("\nYour droplets:\n\n")
-	for index, element := range dropletList {
-		ip, err := element.PublicIPv4()
-		if err != nil {
-			fmt.Printf("%v Name: %s\n\n", index, element.Name)
-		} else {
-			red := color.New(color.FgRed).SprintFunc()
-			cyan := color.New(color.FgCyan).SprintFunc()
-			color.Cyan("%v  Name: %s\n   IP: %s\n\n", cyan(index), red(element.Name), red(ip))
+	if droplet.Image != nil {
+		server.Image = droplet.Image.Slug
+		// Custom images and snapshots don't have a slug
+		if server.Image == "" {
+			server.Image = droplet.Image.Name
 		}
 	}
+
+	return server
 }
 
 // dropletList will return a list of droplets for an account using the godo client
-func dropletList(ctx context.Context, client *godo.Client) ([]godo.Droplet, error) {
+// when tag isn't empty only the droplets with that tag are returned
+func dropletList(ctx context.Context, client *godo.Client, tag string) ([]godo.Droplet, error) {
 	// create a list to hold our droplets
 	list := []godo.Droplet{}
 
 	// create options. initially, these will be blank
 	opt := &godo.ListOptions{}
 	for {
-		droplets, resp, err := client.Droplets.List(ctx, opt)
+		var droplets []godo.Droplet
+		var resp *godo.Response
+		var err error
+
+		if tag != "" {
+			droplets, resp, err = client.Droplets.ListByTag(ctx, tag, opt)
+		} else {
+			droplets, resp, err = client.Droplets.List(ctx, opt)
+		}
 		if err != nil {
 			return nil, err
 		}
@@ -370,6 +1046,19 @@ func dropletList(ctx context.Context, client *godo.Client) ([]godo.Droplet, erro
 
 // regionList will return a list of regions using the godo client
 func regionList(ctx context.Context, client *godo.Client) ([]utils.SelectItem, error) {
+	list, err := allRegions(ctx, client)
+
+	if err != nil {
+		return nil, err
+	}
+
+	selectList := utils.ParseRegionListresults(list)
+
+	return selectList, nil
+}
+
+// allRegions will return every region with the sizes and features it offers using the godo client
+func allRegions(ctx context.Context, client *godo.Client) ([]godo.Region, error) {
 	// create a list to hold our droplets
 	list := []godo.Region{}
 
@@ -398,13 +1087,24 @@ func regionList(ctx context.Context, client *godo.Client) ([]utils.SelectItem, e
 		opt.Page = page + 1
 	}
 
-	selectList := utils.ParseRegionListresults(list)
-
-	return selectList, nil
+	return list, nil
 }
 
 // sizeList will return a list of sizes using the godo client
 func sizeList(ctx context.Context, client *godo.Client) ([]utils.SelectItem, error) {
+	list, err := allSizes(ctx, client)
+
+	if err != nil {
+		return nil, err
+	}
+
+	selectList := utils.ParseSizeListResults(list)
+
+	return selectList, nil
+}
+
+// allSizes will return every droplet size with its prices using the godo client
+func allSizes(ctx context.Context, client *godo.Client) ([]godo.Size, error) {
 	// create a list to hold our droplets
 	list := []godo.Size{}
 
@@ -433,20 +1133,148 @@ func sizeList(ctx context.Context, client *godo.Client) ([]utils.SelectItem, err
 		opt.Page = page + 1
 	}
 
-	selectList := utils.ParseSizeListResults(list)
+	return list, nil
+}
 
-	return selectList, nil
+// featureList will return the optional droplet features, with the cost of backups for the size
+// when a region is given, features the region doesn't offer are left out
+func featureList(ctx context.Context, client *godo.Client, slug string, region string) ([]utils.SelectItem, error) {
+	sizes, err := allSizes(ctx, client)
+
+	if err != nil {
+		return nil, err
+	}
+
+	for _, size := range sizes {
+		if size.Slug != slug {
+			continue
+		}
+
+		features := utils.ParseDropletFeatureList(size)
+
+		if region == "" {
+			return features, nil
+		}
+
+		regions, err := allRegions(ctx, client)
+
+		if err != nil {
+			return nil, err
+		}
+
+		for _, candidate := range regions {
+			if candidate.Slug != region {
+				continue
+			}
+
+			offered := []utils.SelectItem{}
+			for _, feature := range features {
+				if utils.CheckRegion(candidate, size, utils.RegionFeatures([]string{feature.Value})) == nil {
+					offered = append(offered, feature)
+				}
+			}
+
+			return offered, nil
+		}
+
+		return nil, fmt.Errorf("region %q is not available", region)
+	}
+
+	return nil, fmt.Errorf("size %q is not available", slug)
+}
+
+// setFeatures turns on the chosen optional features in the create request
+// when features is nil the question was skipped and DigitalOcean's defaults are kept
+func setFeatures(createRequest *godo.DropletCreateRequest, features []string) {
+	if features == nil {
+		return
+	}
+
+	withAgent := false
+
+	for _, feature := range features {
+		switch feature {
+		case utils.FeatureBackups:
+			createRequest.Backups = true
+		case utils.FeatureMonitoring:
+			createRequest.Monitoring = true
+		case utils.FeatureIPv6:
+			createRequest.IPv6 = true
+		case utils.FeatureAgent:
+			withAgent = true
+		}
+	}
+
+	createRequest.WithDropletAgent = &withAgent
+}
+
+// requestFeatures returns the optional features turned on in the create request
+func requestFeatures(createRequest *godo.DropletCreateRequest) []string {
+	features := []string{}
+
+	if createRequest.Backups {
+		features = append(features, utils.FeatureBackups)
+	}
+	if createRequest.Monitoring {
+		features = append(features, utils.FeatureMonitoring)
+	}
+	if createRequest.IPv6 {
+		features = append(features, utils.FeatureIPv6)
+	}
+	if createRequest.WithDropletAgent != nil && *createRequest.WithDropletAgent {
+		features = append(features, utils.FeatureAgent)
+	}
+
+	return features
 }
 
 // imageDistributionList will return a list of distribution images using the godo client
 func imageDistributionList(ctx context.Context, client *godo.Client) ([]utils.SelectItem, error) {
-	// create a list to hold our droplets
+	return imageSelectList(ctx, client, "D")
+}
+
+// imageApplicationList will return a list of application images using the godo client
+func imageApplicationList(ctx context.Context, client *godo.Client) ([]utils.SelectItem, error) {
+	return imageSelectList(ctx, client, "A")
+}
+
+// imageCustomList will return a list of custom user images using the godo client
+func imageCustomList(ctx context.Context, client *godo.Client) ([]utils.SelectItem, error) {
+	return imageSelectList(ctx, client, "C")
+}
+
+// imageSelectList will return the images of one of the imageFork types that can be used as SelectItems
+func imageSelectList(ctx context.Context, client *godo.Client, imageType string) ([]utils.SelectItem, error) {
+	list, err := imageTypeList(ctx, client, imageType)
+
+	if err != nil {
+		return nil, err
+	}
+
+	selectList := utils.ParseImageListResults(utils.FilterImages(list, nil))
+
+	return selectList, nil
+}
+
+// imageTypeList will return every image of one of the imageFork types using the godo client
+// D for distributions, A for applications and C for the user's custom images and snapshots
+func imageTypeList(ctx context.Context, client *godo.Client, imageType string) ([]godo.Image, error) {
+	listFunc := client.Images.ListDistribution
+
+	switch imageType {
+	case "A":
+		listFunc = client.Images.ListApplication
+	case "C":
+		listFunc = client.Images.ListUser
+	}
+
+	// create a list to hold our images
 	list := []godo.Image{}
 
 	// create options. initially, these will be blank
 	opt := &godo.ListOptions{}
 	for {
-		images, resp, err := client.Images.ListDistribution(ctx, opt)
+		images, resp, err := listFunc(ctx, opt)
 		if err != nil {
 			return nil, err
 		}
@@ -468,26 +1296,28 @@ func imageDistributionList(ctx context.Context, client *godo.Client) ([]utils.Se
 		opt.Page = page + 1
 	}
 
-	selectList := utils.ParseImageListResults(list)
-
-	return selectList, nil
+	return list, nil
 }
 
-// imageApplicationList will return a list of application images using the godo client
-func imageApplicationList(ctx context.Context, client *godo.Client) ([]utils.SelectItem, error) {
-	// create a list to hold our droplets
-	list := []godo.Image{}
+// vpcList will return a list of the VPCs in the region using the godo client
+func vpcList(ctx context.Context, client *godo.Client, region string) ([]utils.SelectItem, error) {
+	// create a list to hold our vpcs
+	list := []*godo.VPC{}
 
 	// create options. initially, these will be blank
 	opt := &godo.ListOptions{}
 	for {
-		images, resp, err := client.Images.ListApplication(ctx, opt)
+		vpcs, resp, err := client.VPCs.List(ctx, opt)
 		if err != nil {
 			return nil, err
 		}
 
-		// append the current page's images to our list
-		list = append(list, images...)
+		// VPCs can't be listed by region so only keep the ones in the region we want
+		for _, vpc := range vpcs {
+			if vpc.RegionSlug == region {
+				list = append(list, vpc)
+			}
+		}
 
 		// if we are at the last page, break out the for loop
 		if resp.Links == nil || resp.Links.IsLastPage() {
@@ -503,26 +1333,30 @@ func imageApplicationList(ctx context.Context, client *godo.Client) ([]utils.Sel
 		opt.Page = page + 1
 	}
 
-	selectList := utils.ParseImageListResults(list)
+	selectList := utils.ParseVPCListResults(list)
 
 	return selectList, nil
 }
 
-// imageCustomList will return a list of custom user images using the godo client
-func imageCustomList(ctx context.Context, client *godo.Client) ([]utils.SelectItem, error) {
-	// create a list to hold our droplets
-	list := []godo.Image{}
+// volumeList will return a list of the volumes in the region that aren't attached to a droplet
+func volumeList(ctx context.Context, client *godo.Client, region string) ([]utils.SelectItem, error) {
+	// create a list to hold our volumes
+	list := []godo.Volume{}
 
 	// create options. initially, these will be blank
 	opt := &godo.ListOptions{}
 	for {
-		images, resp, err := client.Images.ListUser(ctx, opt)
+		volumes, resp, err := client.Storage.ListVolumes(ctx, &godo.ListVolumeParams{Region: region, ListOptions: opt})
 		if err != nil {
 			return nil, err
 		}
 
-		// append the current page's images to our list
-		list = append(list, images...)
+		// a volume can only be attached to one droplet so skip the attached ones
+		for _, volume := range volumes {
+			if len(volume.DropletIDs) == 0 {
+				list = append(list, volume)
+			}
+		}
 
 		// if we are at the last page, break out the for loop
 		if resp.Links == nil || resp.Links.IsLastPage() {
@@ -538,13 +1372,13 @@ func imageCustomList(ctx context.Context, client *godo.Client) ([]utils.SelectIt
 		opt.Page = page + 1
 	}
 
-	selectList := utils.ParseImageListResults(list)
+	selectList := utils.ParseVolumeListResults(list)
 
 	return selectList, nil
 }
 
-// sshKeyList will return a list of available SSH keys on your account
-func sshKeyList(ctx context.Context, client *godo.Client) ([]utils.SelectItem, error) {
+// keyList will return all of the SSH keys on your account using the godo client
+func keyList(ctx context.Context, client *godo.Client) ([]godo.Key, error) {
 	// create a list to hold our droplets
 	list := []godo.Key{}
 
@@ -573,158 +1407,165 @@ func sshKeyList(ctx context.Context, client *godo.Client) ([]utils.SelectItem, e
 		opt.Page = page + 1
 	}
 
+	return list, nil
+}
+
+// sshKeyList will return a list of available SSH keys on your account
+func sshKeyList(ctx context.Context, client *godo.Client) ([]utils.SelectItem, error) {
+	list, err := keyList(ctx, client)
+
+	if err != nil {
+		return nil, err
+	}
+
 	selectList := utils.ParseSSHKeyListResults(list)
 
 	return selectList, nil
 }
 
-// getSelectedSSHKeyID will get all ssh keys on the account
-// asks the user to select one
-// once one is selected, convert it into an int
-// return the ID (11111111)
-func getSelectedSSHKeyID(ctx context.Context, client *godo.Client) (int, error) {
-	keyList, err := sshKeyList(ctx, client)
+// resolveSSHKeys will find each of the given SSH keys on the account by ID or fingerprint
+// returns the keys as they should be sent in the create request, by ID so they match the
+// SSH key picker's values when they are remembered in the wizard history
+func resolveSSHKeys(ctx context.Context, client *godo.Client, given []string) ([]godo.DropletCreateSSHKey, error) {
+	keys, err := keyList(ctx, client)
 
 	if err != nil {
-		fmt.Printf("Something bad happened getting region list: %s\n\n", err)
-		return -1, err
+		return nil, fmt.Errorf("failed to get ssh key list: %w", err)
 	}
 
-	selectedKey, err := utils.AskAndAnswerCustomSelect("SSH Key Select", keyList)
+	sshKeys := []godo.DropletCreateSSHKey{}
 
-	if err != nil {
-		fmt.Printf("Failed to ask SSH key question: %s", err)
-		return -1, err
-	}
+	for _, value := range given {
+		found := false
 
-	sshKeyID, strconvError := strconv.Atoi(selectedKey)
+		for _, key := range keys {
+			if strconv.Itoa(key.ID) == value || key.Fingerprint == value {
+				sshKeys = append(sshKeys, godo.DropletCreateSSHKey{ID: key.ID})
+				found = true
+				break
+			}
+		}
 
-	if strconvError != nil {
-		fmt.Println("ssh key id was not an int")
-		return -1, strconvError
+		if !found {
+			return nil, fmt.Errorf("ssh key %q is not available", value)
+		}
 	}
 
-	return sshKeyID, nil
+	return sshKeys, nil
 }
 
-// getSelectedRegionSlug will get all the regions
-// ask the user to chose one
-// returns the slug of the region (nyc1)
-func getSelectedRegionSlug(ctx context.Context, client *godo.Client) (string, error) {
-	regionList, regionListError := regionList(ctx, client)
+// getSelectedSSHKeys will get all ssh keys on the account
+// asks the user to select any number of them, with the recent keys pinned and already selected
+// once selected, convert each into an int
+// return the keys to send in the create request
+func getSelectedSSHKeys(ctx context.Context, client *godo.Client, recent []string) ([]godo.DropletCreateSSHKey, error) {
+	keyList, err := sshKeyList(ctx, client)
 
-	if regionListError != nil {
-		fmt.Printf("Something bad happened getting region list: %s\n\n", regionListError)
-		return "", regionListError
+	if err != nil {
+		fmt.Printf("Something bad happened getting ssh key list: %s\n\n", err)
+		return nil, err
 	}
 
-	selectedRegion, err := utils.AskAndAnswerCustomSelect("Region Select", regionList)
+	selectedKeys, err := utils.AskAndAnswerRecentMultiSelect("SSH Key Select", keyList, recent)
 
 	if err != nil {
-		fmt.Printf("Failed to ask region question: %s", err)
-		return "", err
+		fmt.Printf("Failed to ask SSH key question: %s", err)
+		return nil, err
 	}
 
-	return selectedRegion, nil
-}
-
-// getSelectedSizeSlug will get all sizes of droplets
-// ask the user to chose one
-// returns the slug of the chose size (s-1vcpu-1gb)
-func getSelectedSizeSlug(ctx context.Context, client *godo.Client) (string, error) {
-	sizeList, sizeListError := sizeList(ctx, client)
+	sshKeys := []godo.DropletCreateSSHKey{}
 
-	if sizeListError != nil {
-		fmt.Printf("Something bad happened getting size list: %s\n\n", sizeListError)
-		return "", sizeListError
-	}
+	for _, selectedKey := range selectedKeys {
+		sshKeyID, strconvError := strconv.Atoi(selectedKey)
 
-	selectedSize, err := utils.AskAndAnswerCustomSelect("Size Select", sizeList)
+		if strconvError != nil {
+			fmt.Println("ssh key id was not an int")
+			return nil, strconvError
+		}
 
-	if err != nil {
-		fmt.Printf("Failed to ask size question, %s", err)
-		return "", err
+		sshKeys = append(sshKeys, godo.DropletCreateSSHKey{ID: sshKeyID})
 	}
 
-	return selectedSize, nil
+	return sshKeys, nil
 }
 
-// getSelectedImageApplicationSlug will get the application images
-// asks the use to chose one
-// returns the chosen image slug (cassandra, centos)
-func getSelectedImageApplicationSlug(ctx context.Context, client *godo.Client) (string, error) {
-	imageList, imageListError := imageApplicationList(ctx, client)
+// getSelectedRegionSlug will take the regions that pass the check from regionCheck
+// ask the user to chose one, showing the features each offers with the recent regions pinned to the top
+// returns the slug of the region (nyc1)
+func getSelectedRegionSlug(regions []godo.Region, check func(godo.Region) error, recent []string) (string, error) {
+	compatible := []godo.Region{}
+	for _, region := range regions {
+		if check(region) == nil {
+			compatible = append(compatible, region)
+		}
+	}
 
-	if imageListError != nil {
-		fmt.Printf("Something bad happened getting image list: %s\n\n", imageListError)
-		return "", imageListError
+	if len(compatible) == 0 {
+		return "", errors.New("no region offers the chosen size with the chosen options")
 	}
 
-	selectedImage, err := utils.AskAndAnswerCustomSelect("Image Select", imageList)
+	selectedRegion, err := utils.AskAndAnswerCustomSelect("Region Select", utils.PinRecent(utils.ParseRegionListresults(compatible), recent))
 
 	if err != nil {
-		fmt.Printf("Failed to ask image question, %s", err)
+		fmt.Printf("Failed to ask region question: %s", err)
 		return "", err
 	}
 
-	return selectedImage, nil
+	return selectedRegion, nil
 }
 
-// getSelectedImageDistributionSlug will get all the distribution  images
-// asks the use to chose one
-// returns the chosen image slug (ubuntu-19-10-x64)
-func getSelectedImageDistributionSlug(ctx context.Context, client *godo.Client) (string, error) {
-	imageList, imageListError := imageDistributionList(ctx, client)
+// getSelectedSizeSlug will get all sizes of droplets that are available in the given regions, or without any
+// regions those offered in at least one region the image and the options in opts can be used in
+// ask the user to chose one, showing the specs and prices of each with the recent sizes pinned to the top
+// returns the slug of the chose size (s-1vcpu-1gb)
+func getSelectedSizeSlug(ctx context.Context, client *godo.Client, regions []string, image godo.Image, opts cloud.CreateOptions, recent []string) (string, error) {
+	sizes, sizeListError := allSizes(ctx, client)
 
-	if imageListError != nil {
-		fmt.Printf("Something bad happened getting image list: %s\n\n", imageListError)
-		return "", imageListError
+	if sizeListError != nil {
+		fmt.Printf("Something bad happened getting size list: %s\n\n", sizeListError)
+		return "", sizeListError
 	}
 
-	selectedImage, err := utils.AskAndAnswerCustomSelect("Image Select", imageList)
+	if len(regions) > 0 {
+		sizes = utils.FilterSizes(sizes, regions)
 
-	if err != nil {
-		fmt.Printf("Failed to ask image question, %s", err)
-		return "", err
-	}
+		if len(sizes) == 0 {
+			return "", fmt.Errorf("no sizes are available in %s", strings.Join(regions, ", "))
+		}
+	} else {
+		// the size isn't known yet, so the regions are only checked for the image and options
+		candidates, checkRegion, err := regionCheck(ctx, client, "", image, opts)
 
-	return selectedImage, nil
-}
+		if err != nil {
+			return "", err
+		}
 
-// getSelectedCustomImageSlug will get all the available images
-// asks the use to chose one
-// returns the chosen image slug (ubuntu-19-10-x64)
-func getSelectedCustomImageSlug(ctx context.Context, client *godo.Client) (string, error) {
-	imageList, imageListError := imageCustomList(ctx, client)
+		compatible := []string{}
+		for _, region := range candidates {
+			if checkRegion(region) == nil {
+				compatible = append(compatible, region.Slug)
+			}
+		}
 
-	if imageListError != nil {
-		fmt.Printf("Something bad happened getting image list: %s\n\n", imageListError)
-		return "", imageListError
+		sizes = utils.FilterSizesOfferedInAny(sizes, compatible)
+
+		if len(sizes) == 0 {
+			return "", errors.New("no sizes are available in a region offering the chosen image with the chosen options")
+		}
 	}
 
-	selectedImage, err := utils.AskAndAnswerCustomSelect("Image Select", imageList)
+	selectedSize, err := utils.AskAndAnswerSizeSelect("Size Select", sizes, recent)
 
 	if err != nil {
-		fmt.Printf("Failed to ask image question, %s", err)
+		fmt.Printf("Failed to ask size question, %s", err)
 		return "", err
 	}
 
-	return selectedImage, nil
+	return selectedSize, nil
 }
 
 // confirmCreate asks the user if they are sure they want to create the droplet
 // answering with a "y" will return true
 func confirmCreate(label string) (bool, error) {
-	promptAreYouSure := promptui.Prompt{
-		Label:    label,
-		Validate: utils.ValidateAreYouSure,
-	}
-
-	areYouSure, err := promptAreYouSure.Run()
-
-	if err != nil {
-		return false, err
-	}
-
-	return areYouSure == "y", nil
+	return utils.AskYesNo(label)
 }
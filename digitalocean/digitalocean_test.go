@@ -0,0 +1,619 @@
+package digitalocean
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/http"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/Joel-Valentine/cogo/cloud"
+	"github.com/Joel-Valentine/cogo/utils"
+	"github.com/digitalocean/godo"
+)
+
+func TestResolveSSHKeys(t *testing.T) {
+	client := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"ssh_keys": [
+			{"id": 101, "name": "laptop", "fingerprint": "3b:16:bf:e4:8b:00:8b:b8:59:8c:a9:d3:f0:19:45:fa"},
+			{"id": 102, "name": "on-call", "fingerprint": "9a:2c:41:8e:0f:7d:11:c3:55:e2:6b:9d:04:ab:7c:e1"}
+		], "links": {}, "meta": {"total": 2}}`)
+	}))
+
+	tests := []struct {
+		name        string
+		given       []string
+		expected    []godo.DropletCreateSSHKey
+		expectError bool
+	}{
+		{
+			name:     "by id",
+			given:    []string{"102"},
+			expected: []godo.DropletCreateSSHKey{{ID: 102}},
+		},
+		{
+			name:     "by id and fingerprint",
+			given:    []string{"101", "9a:2c:41:8e:0f:7d:11:c3:55:e2:6b:9d:04:ab:7c:e1"},
+			expected: []godo.DropletCreateSSHKey{{ID: 101}, {ID: 102}},
+		},
+		{
+			name:        "unknown key",
+			given:       []string{"101", "999"},
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sshKeys, err := resolveSSHKeys(context.Background(), client, tt.given)
+
+			if tt.expectError {
+				if err == nil {
+					t.Error("expected error, got nil")
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if len(sshKeys) != len(tt.expected) {
+				t.Fatalf("expected %d keys, got %d", len(tt.expected), len(sshKeys))
+			}
+			for i := range sshKeys {
+				if sshKeys[i] != tt.expected[i] {
+					t.Errorf("expected key %+v, got %+v", tt.expected[i], sshKeys[i])
+				}
+			}
+		})
+	}
+}
+
+func TestDropletList_ByTag(t *testing.T) {
+	client := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if tag := r.URL.Query().Get("tag_name"); tag != "web" {
+			fmt.Fprint(w, `{"droplets": [{"id": 1, "name": "web-1", "tags": ["web"]}, {"id": 2, "name": "db", "tags": []}], "links": {}, "meta": {"total": 2}}`)
+			return
+		}
+		fmt.Fprint(w, `{"droplets": [{"id": 1, "name": "web-1", "tags": ["web"]}], "links": {}, "meta": {"total": 1}}`)
+	}))
+
+	tests := []struct {
+		name     string
+		tag      string
+		expected int
+	}{
+		{
+			name:     "all droplets",
+			tag:      "",
+			expected: 2,
+		},
+		{
+			name:     "by tag",
+			tag:      "web",
+			expected: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			droplets, err := dropletList(context.Background(), client, tt.tag)
+
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if len(droplets) != tt.expected {
+				t.Errorf("expected %d droplets, got %d", tt.expected, len(droplets))
+			}
+		})
+	}
+}
+
+func TestVPCList(t *testing.T) {
+	client := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"vpcs": [
+			{"id": "vpc-1", "name": "default-lon1", "ip_range": "10.106.0.0/20", "region": "lon1", "default": true},
+			{"id": "vpc-2", "name": "default-ams3", "ip_range": "10.110.0.0/20", "region": "ams3", "default": true},
+			{"id": "vpc-3", "name": "backend", "ip_range": "10.200.0.0/24", "region": "lon1"}
+		], "links": {}, "meta": {"total": 3}}`)
+	}))
+
+	vpcs, err := vpcList(context.Background(), client, "lon1")
+
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(vpcs) != 2 {
+		t.Fatalf("expected 2 vpcs in lon1, got %d", len(vpcs))
+	}
+
+	if vpcs[0].Value != "vpc-1" || vpcs[0].Name != "default-lon1 (10.106.0.0/20) default" {
+		t.Errorf("unexpected default vpc %+v", vpcs[0])
+	}
+	if vpcs[1].Value != "vpc-3" || vpcs[1].Name != "backend (10.200.0.0/24)" {
+		t.Errorf("unexpected vpc %+v", vpcs[1])
+	}
+}
+
+func TestRegionCheck(t *testing.T) {
+	client := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v2/regions":
+			fmt.Fprint(w, `{"regions": [
+				{"slug": "lon1", "name": "London 1", "available": true, "features": ["backups", "ipv6", "storage"]},
+				{"slug": "ams3", "name": "Amsterdam 3", "available": true, "features": ["backups"]},
+				{"slug": "nyc2", "name": "New York 2", "available": false, "features": ["backups", "ipv6", "storage"]},
+				{"slug": "sgp1", "name": "Singapore 1", "available": true, "features": ["backups", "ipv6", "storage"]}
+			], "links": {}, "meta": {"total": 4}}`)
+		case "/v2/sizes":
+			fmt.Fprint(w, `{"sizes": [
+				{"slug": "s-1vcpu-1gb", "available": true, "regions": ["lon1", "ams3", "nyc2"]}
+			], "links": {}, "meta": {"total": 1}}`)
+		case "/v2/vpcs/vpc-ams3":
+			fmt.Fprint(w, `{"vpc": {"id": "vpc-ams3", "name": "backend", "region": "ams3"}}`)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+
+	tests := []struct {
+		name       string
+		opts       cloud.CreateOptions
+		image      *godo.Image
+		compatible []string
+		region     string
+		expected   string
+	}{
+		{
+			name:       "size and availability",
+			compatible: []string{"lon1", "ams3"},
+			region:     "sgp1",
+			expected:   `size "s-1vcpu-1gb" is not offered in sgp1`,
+		},
+		{
+			name:       "features given as flags",
+			opts:       cloud.CreateOptions{Features: []string{utils.FeatureIPv6}},
+			compatible: []string{"lon1"},
+			region:     "ams3",
+			expected:   `region "ams3" does not offer ipv6`,
+		},
+		{
+			name:       "volumes need storage",
+			opts:       cloud.CreateOptions{Volumes: []string{"vol-1"}},
+			compatible: []string{"lon1"},
+		},
+		{
+			name:       "vpc",
+			opts:       cloud.CreateOptions{VPC: "vpc-ams3"},
+			compatible: []string{"ams3"},
+			region:     "lon1",
+			expected:   `VPC "vpc-ams3" is in ams3, not lon1`,
+		},
+		{
+			name:       "custom image without a slug",
+			image:      &godo.Image{ID: 42, Regions: []string{"lon1"}},
+			compatible: []string{"lon1"},
+			region:     "ams3",
+			expected:   `image "42" is not available in ams3`,
+		},
+		{
+			name:       "unknown region",
+			compatible: []string{"lon1", "ams3"},
+			region:     "xyz1",
+			expected:   `region "xyz1" is not available`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			image := godo.Image{Slug: "ubuntu-24-04-x64", Regions: []string{"lon1", "ams3", "nyc2", "sgp1"}}
+			if tt.image != nil {
+				image = *tt.image
+			}
+
+			regions, check, err := regionCheck(context.Background(), client, "s-1vcpu-1gb", image, tt.opts)
+
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			compatible := []string{}
+			for _, region := range regions {
+				if check(region) == nil {
+					compatible = append(compatible, region.Slug)
+				}
+			}
+
+			if strings.Join(compatible, ",") != strings.Join(tt.compatible, ",") {
+				t.Errorf("expected %v to be compatible, got %v", tt.compatible, compatible)
+			}
+
+			if tt.region == "" {
+				return
+			}
+
+			err = validateRegion(tt.region, regions, check)
+			if err == nil || err.Error() != tt.expected {
+				t.Errorf("validateRegion() error = %v, want %q", err, tt.expected)
+			}
+		})
+	}
+}
+
+func TestFindImage(t *testing.T) {
+	client := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Query().Get("type") == "distribution":
+			fmt.Fprint(w, `{"images": [
+				{"id": 1, "slug": "ubuntu-24-04-x64", "distribution": "Ubuntu", "status": "available", "regions": ["lon1", "ams3"]},
+				{"id": 2, "slug": "ubuntu-20-04-x64", "distribution": "Ubuntu", "status": "retired", "regions": ["lon1", "ams3"]}
+			], "links": {}, "meta": {"total": 2}}`)
+		case r.URL.Query().Get("private") == "true":
+			fmt.Fprint(w, `{"images": [
+				{"id": 42, "name": "web snapshot", "distribution": "Ubuntu", "status": "available", "regions": ["lon1"]}
+			], "links": {}, "meta": {"total": 1}}`)
+		default:
+			fmt.Fprint(w, `{"images": [], "links": {}, "meta": {"total": 0}}`)
+		}
+	}))
+
+	tests := []struct {
+		name     string
+		value    string
+		regions  []string
+		expected int
+		err      string
+	}{
+		{name: "by slug", value: "ubuntu-24-04-x64", regions: []string{"ams3"}, expected: 1},
+		{name: "custom image by id", value: "42", regions: []string{"lon1"}, expected: 42},
+		{name: "not in region", value: "42", regions: []string{"ams3"}, err: `image "42" is not available in ams3`},
+		{name: "retired", value: "ubuntu-20-04-x64", err: `image "ubuntu-20-04-x64" is not available`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			image, err := findImage(context.Background(), client, tt.value, tt.regions)
+
+			if tt.err != "" {
+				if err == nil || err.Error() != tt.err {
+					t.Errorf("expected error %q, got %v", tt.err, err)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if image.ID != tt.expected {
+				t.Errorf("expected image %d, got %d", tt.expected, image.ID)
+			}
+		})
+	}
+}
+
+func TestSetFeatures(t *testing.T) {
+	tests := []struct {
+		name       string
+		features   []string
+		backups    bool
+		monitoring bool
+		ipv6       bool
+		agent      *bool
+	}{
+		{
+			name:     "not asked keeps the defaults",
+			features: nil,
+		},
+		{
+			name:     "none chosen turns off the agent",
+			features: []string{},
+			agent:    boolPointer(false),
+		},
+		{
+			name:       "all chosen",
+			features:   []string{"backups", "monitoring", "ipv6", "agent"},
+			backups:    true,
+			monitoring: true,
+			ipv6:       true,
+			agent:      boolPointer(true),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			createRequest := &godo.DropletCreateRequest{}
+
+			setFeatures(createRequest, tt.features)
+
+			if createRequest.Backups != tt.backups || createRequest.Monitoring != tt.monitoring || createRequest.IPv6 != tt.ipv6 {
+				t.Errorf("unexpected features in request %+v", createRequest)
+			}
+
+			if (tt.agent == nil) != (createRequest.WithDropletAgent == nil) {
+				t.Fatalf("expected agent %v, got %v", tt.agent, createRequest.WithDropletAgent)
+			}
+			if tt.agent != nil && *tt.agent != *createRequest.WithDropletAgent {
+				t.Errorf("expected agent %v, got %v", *tt.agent, *createRequest.WithDropletAgent)
+			}
+		})
+	}
+}
+
+func boolPointer(value bool) *bool {
+	return &value
+}
+
+func TestVolumeList(t *testing.T) {
+	client := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if region := r.URL.Query().Get("region"); region != "lon1" {
+			t.Errorf("expected volumes to be listed in lon1, got %q", region)
+		}
+		fmt.Fprint(w, `{"volumes": [
+			{"id": "vol-1", "name": "db-data", "size_gigabytes": 100, "filesystem_type": "ext4", "droplet_ids": []},
+			{"id": "vol-2", "name": "in-use", "size_gigabytes": 10, "droplet_ids": [42]},
+			{"id": "vol-3", "name": "raw", "size_gigabytes": 5}
+		], "links": {}, "meta": {"total": 3}}`)
+	}))
+
+	volumes, err := volumeList(context.Background(), client, "lon1")
+
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(volumes) != 2 {
+		t.Fatalf("expected 2 unattached volumes, got %d", len(volumes))
+	}
+
+	if volumes[0].Value != "vol-1" || volumes[0].Name != "db-data (100 GiB, ext4)" {
+		t.Errorf("unexpected volume %+v", volumes[0])
+	}
+	if volumes[1].Value != "vol-3" || volumes[1].Name != "raw (5 GiB)" {
+		t.Errorf("unexpected volume %+v", volumes[1])
+	}
+}
+
+func TestCreateDroplets(t *testing.T) {
+	tests := []struct {
+		name            string
+		plan            []cloud.BulkServer
+		failRegion      string
+		expectedCalls   int
+		expectedMulti   bool
+		expectedFailed  []string
+		expectedCreated []string
+	}{
+		{
+			name:            "one region uses create multiple",
+			plan:            []cloud.BulkServer{{Index: 1, Name: "worker-1", Region: "lon1"}, {Index: 2, Name: "worker-2", Region: "lon1"}, {Index: 3, Name: "worker-3", Region: "lon1"}},
+			expectedCalls:   1,
+			expectedMulti:   true,
+			expectedCreated: []string{"worker-1", "worker-2", "worker-3"},
+		},
+		{
+			name:            "more than ten in one region are split",
+			plan:            bulkPlan(12, "lon1"),
+			expectedCalls:   2,
+			expectedMulti:   true,
+			expectedCreated: bulkNames(12),
+		},
+		{
+			name:            "regions differ",
+			plan:            []cloud.BulkServer{{Index: 1, Name: "worker-1", Region: "lon1"}, {Index: 2, Name: "worker-2", Region: "ams3"}, {Index: 3, Name: "worker-3", Region: "lon1"}},
+			expectedCalls:   3,
+			expectedCreated: []string{"worker-1", "worker-2", "worker-3"},
+		},
+		{
+			name:            "failures are reported per droplet",
+			plan:            []cloud.BulkServer{{Index: 1, Name: "worker-1", Region: "lon1"}, {Index: 2, Name: "worker-2", Region: "ams3"}},
+			failRegion:      "ams3",
+			expectedCalls:   2,
+			expectedFailed:  []string{"worker-2"},
+			expectedCreated: []string{"worker-1"},
+		},
+		{
+			name:            "user data differs",
+			plan:            []cloud.BulkServer{{Index: 1, Name: "worker-1", Region: "lon1", UserData: "name: worker-1"}, {Index: 2, Name: "worker-2", Region: "lon1", UserData: "name: worker-2"}},
+			expectedCalls:   2,
+			expectedCreated: []string{"worker-1", "worker-2"},
+		},
+		{
+			name:            "same user data uses create multiple",
+			plan:            []cloud.BulkServer{{Index: 1, Name: "worker-1", Region: "lon1", UserData: "region: lon1"}, {Index: 2, Name: "worker-2", Region: "lon1", UserData: "region: lon1"}},
+			expectedCalls:   1,
+			expectedMulti:   true,
+			expectedCreated: []string{"worker-1", "worker-2"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var calls atomic.Int32
+			var multi atomic.Bool
+			var mutex sync.Mutex
+			userData := map[string]string{}
+
+			client := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				calls.Add(1)
+
+				var request struct {
+					Name     string   `json:"name"`
+					Names    []string `json:"names"`
+					Region   string   `json:"region"`
+					UserData string   `json:"user_data"`
+				}
+				if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+					w.WriteHeader(http.StatusBadRequest)
+					return
+				}
+
+				mutex.Lock()
+				for _, name := range append(request.Names, request.Name) {
+					userData[name] = request.UserData
+				}
+				mutex.Unlock()
+
+				if request.Region == tt.failRegion {
+					w.WriteHeader(http.StatusUnprocessableEntity)
+					fmt.Fprint(w, `{"id": "unprocessable_entity", "message": "Region is not available"}`)
+					return
+				}
+
+				w.WriteHeader(http.StatusAccepted)
+
+				if len(request.Names) > 0 {
+					multi.Store(true)
+					droplets := []godo.Droplet{}
+					for index, name := range request.Names {
+						droplets = append(droplets, godo.Droplet{ID: index + 1, Name: name, Region: &godo.Region{Slug: request.Region}})
+					}
+					json.NewEncoder(w).Encode(map[string]any{"droplets": droplets})
+					return
+				}
+
+				json.NewEncoder(w).Encode(map[string]any{"droplet": godo.Droplet{ID: 1, Name: request.Name, Region: &godo.Region{Slug: request.Region}}})
+			}))
+
+			results := createDroplets(context.Background(), client, &godo.DropletCreateRequest{Size: "s-1vcpu-1gb"}, tt.plan)
+
+			if int(calls.Load()) != tt.expectedCalls {
+				t.Errorf("expected %d requests, got %d", tt.expectedCalls, calls.Load())
+			}
+			if multi.Load() != tt.expectedMulti {
+				t.Errorf("expected create multiple to be used = %v", tt.expectedMulti)
+			}
+
+			created := []string{}
+			failed := []string{}
+			for index, result := range results {
+				if result.Name != tt.plan[index].Name || result.Region != tt.plan[index].Region {
+					t.Errorf("result %d is for %s in %s, expected %s in %s", index, result.Name, result.Region, tt.plan[index].Name, tt.plan[index].Region)
+				}
+				if result.Err != nil {
+					failed = append(failed, result.Name)
+					continue
+				}
+				if result.Server == nil || result.Server.Name != result.Name {
+					t.Errorf("unexpected server for %s: %+v", result.Name, result.Server)
+				}
+				if userData[result.Name] != tt.plan[index].UserData {
+					t.Errorf("expected %s to be created with user data %q, got %q", result.Name, tt.plan[index].UserData, userData[result.Name])
+				}
+				created = append(created, result.Name)
+			}
+
+			if strings.Join(created, ",") != strings.Join(tt.expectedCreated, ",") {
+				t.Errorf("expected %v to be created, got %v", tt.expectedCreated, created)
+			}
+			if strings.Join(failed, ",") != strings.Join(tt.expectedFailed, ",") {
+				t.Errorf("expected %v to fail, got %v", tt.expectedFailed, failed)
+			}
+		})
+	}
+}
+
+// bulkPlan plans count droplets named worker-N in the region
+func bulkPlan(count int, region string) []cloud.BulkServer {
+	plan := []cloud.BulkServer{}
+	for index, name := range bulkNames(count) {
+		plan = append(plan, cloud.BulkServer{Index: index + 1, Name: name, Region: region})
+	}
+	return plan
+}
+
+// bulkNames returns worker-1 to worker-count
+func bulkNames(count int) []string {
+	names := []string{}
+	for index := 1; index <= count; index++ {
+		names = append(names, fmt.Sprintf("worker-%d", index))
+	}
+	return names
+}
+
+func TestCreateSummary(t *testing.T) {
+	client := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"sizes": [
+			{"slug": "s-1vcpu-1gb", "price_monthly": 6, "price_hourly": 0.00893, "available": true, "regions": ["lon1"]},
+			{"slug": "s-2vcpu-4gb", "price_monthly": 24, "price_hourly": 0.03571, "available": true, "regions": ["lon1"]}
+		], "links": {}, "meta": {"total": 2}}`)
+	}))
+
+	withAgent := false
+	createRequest := &godo.DropletCreateRequest{
+		Name:             "web-1",
+		Region:           "lon1",
+		Size:             "s-2vcpu-4gb",
+		Image:            godo.DropletCreateImage{ID: 5001},
+		SSHKeys:          []godo.DropletCreateSSHKey{{ID: 101}, {Fingerprint: "aa:bb"}},
+		Backups:          true,
+		IPv6:             true,
+		WithDropletAgent: &withAgent,
+	}
+	volumes := &cloud.VolumeAnswers{Existing: []string{}, New: &cloud.NewVolume{Name: "web-1-data", SizeGiB: 50}}
+
+	summary, err := createSummary(context.Background(), client, createRequest, volumes, []string{"web-1"}, []string{"lon1"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if summary.Image != "5001" || strings.Join(summary.SSHKeys, ",") != "101,aa:bb" || strings.Join(summary.Features, ",") != "backups,ipv6" {
+		t.Errorf("unexpected summary %+v", summary)
+	}
+
+	// 24 for the droplet, 4.80 for backups and 5 for the 50 GiB volume
+	if summary.PriceMonthly != 24 || summary.TotalMonthly != 33.8 {
+		t.Errorf("expected 24/mo and a total of 33.80/mo, got %v and %v", summary.PriceMonthly, summary.TotalMonthly)
+	}
+}
+
+func TestSurcharges(t *testing.T) {
+	tests := []struct {
+		name     string
+		summary  cloud.CreateSummary
+		expected []cloud.Surcharge
+	}{
+		{
+			name:     "none",
+			summary:  cloud.CreateSummary{Names: []string{"web-1"}, Features: []string{"monitoring"}},
+			expected: []cloud.Surcharge{},
+		},
+		{
+			name:     "backups",
+			summary:  cloud.CreateSummary{Names: []string{"web-1"}, Features: []string{"backups", "monitoring"}},
+			expected: []cloud.Surcharge{{Name: "backups", PriceMonthly: 1.2}},
+		},
+		{
+			name:     "new volume",
+			summary:  cloud.CreateSummary{Names: []string{"web-1"}, NewVolume: &cloud.NewVolume{Name: "web-1-data", SizeGiB: 100}},
+			expected: []cloud.Surcharge{{Name: "volume", PriceMonthly: 10}},
+		},
+		{
+			name:     "several droplets with backups",
+			summary:  cloud.CreateSummary{Names: []string{"web-1", "web-2", "web-3"}, Features: []string{"backups"}},
+			expected: []cloud.Surcharge{{Name: "backups", PriceMonthly: 3.6}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			actual := surcharges(tt.summary, 6)
+
+			if len(actual) != len(tt.expected) {
+				t.Fatalf("expected surcharges %v, got %v", tt.expected, actual)
+			}
+			for index, surcharge := range tt.expected {
+				if math.Abs(actual[index].PriceMonthly-surcharge.PriceMonthly) > 0.001 || actual[index].Name != surcharge.Name {
+					t.Errorf("expected surcharge %v, got %v", surcharge, actual[index])
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,147 @@
+package digitalocean
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/Joel-Valentine/cogo/cloud"
+	"github.com/Joel-Valentine/cogo/utils"
+	"github.com/digitalocean/godo"
+	"github.com/fatih/color"
+)
+
+// errActionTimeout is returned when a droplet action hasn't finished within its timeout
+var errActionTimeout = errors.New("timed out")
+
+// powerActions runs each power action on a droplet
+var powerActions = map[string]func(context.Context, *godo.Client, int) (*godo.Action, *godo.Response, error){
+	cloud.PowerOn: func(ctx context.Context, client *godo.Client, dropletID int) (*godo.Action, *godo.Response, error) {
+		return client.DropletActions.PowerOn(ctx, dropletID)
+	},
+	cloud.PowerOff: func(ctx context.Context, client *godo.Client, dropletID int) (*godo.Action, *godo.Response, error) {
+		return client.DropletActions.PowerOff(ctx, dropletID)
+	},
+	cloud.PowerCycle: func(ctx context.Context, client *godo.Client, dropletID int) (*godo.Action, *godo.Response, error) {
+		return client.DropletActions.PowerCycle(ctx, dropletID)
+	},
+	cloud.PowerShutdown: func(ctx context.Context, client *godo.Client, dropletID int) (*godo.Action, *godo.Response, error) {
+		return client.DropletActions.Shutdown(ctx, dropletID)
+	},
+	cloud.PowerReboot: func(ctx context.Context, client *godo.Client, dropletID int) (*godo.Action, *godo.Response, error) {
+		return client.DropletActions.Reboot(ctx, dropletID)
+	},
+}
+
+// PowerDroplet will show the user a list of droplets, or pick the one in opts, then run the power action
+// on it and wait for the action to finish. A shutdown that hasn't finished within opts.ShutdownTimeout
+// is followed by a power off. The droplet is returned with its final status
+func PowerDroplet(ctx context.Context, client *godo.Client, opts cloud.PowerOptions) (*godo.Droplet, error) {
+	if err := cloud.ValidatePowerAction(opts.Action); err != nil {
+		return nil, err
+	}
+
+	droplets, err := dropletList(ctx, client, "")
+
+	if err != nil {
+		return nil, err
+	}
+
+	selectedDropletIndex, err := getSelectedDropletIndex(droplets, opts.ServerSelector, fmt.Sprintf("Select droplet to %s", powerLabel(opts.Action)))
+
+	if err != nil {
+		return nil, err
+	}
+
+	return powerDroplet(ctx, client, droplets[selectedDropletIndex].ID, opts)
+}
+
+// powerLabel describes the power action in the droplet select prompt
+func powerLabel(action string) string {
+	switch action {
+	case cloud.PowerOn, cloud.PowerOff:
+		return "power " + action
+	case cloud.PowerCycle:
+		return "power cycle"
+	}
+	return action
+}
+
+// powerDroplet runs the power action on the droplet and waits for it to finish, powering the droplet
+// off when a shutdown doesn't finish within opts.ShutdownTimeout
+// returns the droplet with its final status
+func powerDroplet(ctx context.Context, client *godo.Client, dropletID int, opts cloud.PowerOptions) (*godo.Droplet, error) {
+	action, _, err := powerActions[opts.Action](ctx, client, dropletID)
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to %s droplet %d: %w", powerLabel(opts.Action), dropletID, err)
+	}
+
+	if opts.Action == cloud.PowerShutdown {
+		err = waitForAction(ctx, client, dropletID, action, opts.ShutdownTimeout)
+
+		// only a shutdown that is taking too long is forced, any other failure is returned as it is
+		if err != nil && !errors.Is(err, errActionTimeout) {
+			return nil, err
+		}
+
+		if err != nil {
+			color.Yellow("Droplet %d didn't shut down gracefully (%s), powering it off", dropletID, err)
+
+			action, _, err = client.DropletActions.PowerOff(ctx, dropletID)
+
+			if err != nil {
+				return nil, fmt.Errorf("failed to power off droplet %d: %w", dropletID, err)
+			}
+		}
+	}
+
+	if err := waitForAction(ctx, client, dropletID, action, opts.Timeout); err != nil {
+		return nil, err
+	}
+
+	droplet, _, err := client.Droplets.Get(ctx, dropletID)
+
+	return droplet, err
+}
+
+// waitForAction polls the droplet action until it has completed
+// returns an error if it errored, or wrapping errActionTimeout if it didn't complete within the timeout
+func waitForAction(ctx context.Context, client *godo.Client, dropletID int, action *godo.Action, timeout time.Duration) error {
+	spinner := utils.NewSpinner(fmt.Sprintf("Waiting for %s of droplet %d to finish...", action.Type, dropletID))
+	spinner.Start()
+	defer spinner.Stop()
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	ticker := time.NewTicker(cloud.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		switch action.Status {
+		case godo.ActionCompleted:
+			return nil
+		case "errored":
+			return fmt.Errorf("%s of droplet %d errored", action.Type, dropletID)
+		}
+
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("%w after %s waiting for %s of droplet %d to finish", errActionTimeout, timeout, action.Type, dropletID)
+		case <-ticker.C:
+		}
+
+		latest, _, err := client.DropletActions.Get(ctx, dropletID, action.ID)
+
+		if err != nil {
+			if ctx.Err() != nil {
+				return fmt.Errorf("%w after %s waiting for %s of droplet %d to finish", errActionTimeout, timeout, action.Type, dropletID)
+			}
+			return err
+		}
+
+		action = latest
+	}
+}
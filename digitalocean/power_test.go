@@ -0,0 +1,122 @@
+package digitalocean
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/Joel-Valentine/cogo/cloud"
+)
+
+func TestPowerDroplet(t *testing.T) {
+	originalInterval := cloud.PollInterval
+	cloud.PollInterval = time.Millisecond
+	defer func() { cloud.PollInterval = originalInterval }()
+
+	tests := []struct {
+		name            string
+		action          string
+		shutdownStatus  string
+		expectedActions string
+		expectedError   string
+	}{
+		{
+			name:            "reboot",
+			action:          cloud.PowerReboot,
+			expectedActions: "reboot",
+		},
+		{
+			name:            "graceful shutdown",
+			action:          cloud.PowerShutdown,
+			shutdownStatus:  "completed",
+			expectedActions: "shutdown",
+		},
+		{
+			name:            "shutdown falls back to power off",
+			action:          cloud.PowerShutdown,
+			shutdownStatus:  "in-progress",
+			expectedActions: "shutdown,power_off",
+		},
+		{
+			name:            "errored shutdown is returned",
+			action:          cloud.PowerShutdown,
+			shutdownStatus:  "errored",
+			expectedActions: "shutdown",
+			expectedError:   "shutdown of droplet 1 errored",
+		},
+		{
+			name:            "errored action",
+			action:          cloud.PowerOn,
+			expectedActions: "power_on",
+			expectedError:   "power_on of droplet 1 errored",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			actions := []string{}
+			var polls atomic.Int32
+
+			client := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				switch {
+				case r.Method == http.MethodPost && r.URL.Path == "/v2/droplets/1/actions":
+					var request struct {
+						Type string `json:"type"`
+					}
+					if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+						t.Fatalf("failed to decode action request: %v", err)
+					}
+
+					actions = append(actions, request.Type)
+					fmt.Fprintf(w, `{"action": {"id": %d, "type": %q, "status": "in-progress"}}`, len(actions), request.Type)
+				case r.URL.Path == "/v2/droplets/1/actions/1":
+					status := "completed"
+					if tt.action == cloud.PowerShutdown {
+						status = tt.shutdownStatus
+					} else if tt.expectedError != "" {
+						status = "errored"
+					}
+
+					polls.Add(1)
+					fmt.Fprintf(w, `{"action": {"id": 1, "type": %q, "status": %q}}`, actions[0], status)
+				case r.URL.Path == "/v2/droplets/1/actions/2":
+					fmt.Fprint(w, `{"action": {"id": 2, "type": "power_off", "status": "completed"}}`)
+				case r.URL.Path == "/v2/droplets/1":
+					fmt.Fprint(w, `{"droplet": {"id": 1, "name": "web-1", "status": "off"}}`)
+				default:
+					w.WriteHeader(http.StatusNotFound)
+				}
+			}))
+
+			opts := cloud.PowerOptions{Action: tt.action, Timeout: time.Second, ShutdownTimeout: 20 * time.Millisecond}
+
+			droplet, err := powerDroplet(context.Background(), client, 1, opts)
+
+			if strings.Join(actions, ",") != tt.expectedActions {
+				t.Errorf("expected actions %s, got %v", tt.expectedActions, actions)
+			}
+
+			if tt.expectedError != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.expectedError) {
+					t.Errorf("expected error %q, got %v", tt.expectedError, err)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if droplet.Name != "web-1" || droplet.Status != "off" {
+				t.Errorf("expected the droplet with its final status, got %+v", droplet)
+			}
+			if polls.Load() == 0 {
+				t.Error("expected the action to be polled")
+			}
+		})
+	}
+}
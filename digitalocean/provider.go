@@ -0,0 +1,276 @@
+package digitalocean
+
+import (
+	"context"
+
+	"github.com/Joel-Valentine/cogo/cloud"
+	"github.com/Joel-Valentine/cogo/credentials"
+	"github.com/Joel-Valentine/cogo/utils"
+	"github.com/digitalocean/godo"
+)
+
+// Provider makes DigitalOcean available to the commands through the cloud registry
+type Provider struct {
+	client *godo.Client
+}
+
+var _ cloud.Provider = &Provider{}
+var _ cloud.UserDataProvider = &Provider{}
+var _ cloud.TagProvider = &Provider{}
+var _ cloud.VPCProvider = &Provider{}
+var _ cloud.FeatureProvider = &Provider{}
+var _ cloud.VolumeProvider = &Provider{}
+var _ cloud.BulkCreateProvider = &Provider{}
+var _ cloud.PowerProvider = &Provider{}
+var _ cloud.ResizeProvider = &Provider{}
+var _ cloud.RebuildProvider = &Provider{}
+
+// providerKey is used to select DigitalOcean and to keep its wizard history apart from other providers
+const providerKey = "do"
+
+// NewProvider creates the DigitalOcean cloud provider
+func NewProvider() *Provider {
+	return &Provider{}
+}
+
+// Key returns the identifier used to select DigitalOcean
+func (p *Provider) Key() string {
+	return providerKey
+}
+
+// Name returns the provider name
+func (p *Provider) Name() string {
+	return "DigitalOcean"
+}
+
+// ServerNoun returns what DigitalOcean calls its servers
+func (p *Provider) ServerNoun() string {
+	return "droplet"
+}
+
+// HasCredentials returns true if a token can be found without prompting for one
+func (p *Provider) HasCredentials(ctx context.Context) bool {
+	return credentials.DigitalOcean.HasToken(ctx)
+}
+
+// CredentialService returns where the DigitalOcean token is kept
+func (p *Provider) CredentialService() credentials.Service {
+	return credentials.DigitalOcean
+}
+
+// UserDataLimit returns the largest user data a droplet accepts
+func (p *Provider) UserDataLimit() int {
+	return userDataLimit
+}
+
+// SupportsTags returns true, droplets can be tagged and listed by tag
+func (p *Provider) SupportsTags() bool {
+	return true
+}
+
+// Create runs the droplet create wizard
+func (p *Provider) Create(ctx context.Context, opts cloud.CreateOptions) (*cloud.Server, error) {
+	client, err := p.getClient(ctx)
+
+	if err != nil {
+		return nil, err
+	}
+
+	droplet, err := CreateDroplet(ctx, client, opts)
+
+	if droplet == nil {
+		return nil, err
+	}
+
+	// err is only set along with the droplet when waiting for it to become active failed
+	server := dropletToServer(*droplet)
+	return &server, err
+}
+
+// CreateMany runs the droplet create wizard once then creates opts.Count droplets
+func (p *Provider) CreateMany(ctx context.Context, opts cloud.CreateOptions) ([]cloud.CreateResult, error) {
+	client, err := p.getClient(ctx)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return CreateDroplets(ctx, client, opts)
+}
+
+// List returns all of the droplets on the account, or those with opts.Tag
+func (p *Provider) List(ctx context.Context, opts cloud.ListOptions) ([]cloud.Server, error) {
+	client, err := p.getClient(ctx)
+
+	if err != nil {
+		return nil, err
+	}
+
+	droplets, err := ListDroplets(ctx, client, opts.Tag)
+
+	if err != nil {
+		return nil, err
+	}
+
+	servers := []cloud.Server{}
+	for _, droplet := range droplets {
+		servers = append(servers, dropletToServer(droplet))
+	}
+
+	return servers, nil
+}
+
+// Destroy runs the droplet destroy questions
+func (p *Provider) Destroy(ctx context.Context, opts cloud.DestroyOptions) (*cloud.Server, error) {
+	client, err := p.getClient(ctx)
+
+	if err != nil {
+		return nil, err
+	}
+
+	droplet, err := DestroyDroplet(ctx, client, opts)
+
+	if err != nil || droplet == nil {
+		return nil, err
+	}
+
+	server := dropletToServer(*droplet)
+	return &server, nil
+}
+
+// Power runs the power action on a droplet and waits for it to finish
+func (p *Provider) Power(ctx context.Context, opts cloud.PowerOptions) (*cloud.Server, error) {
+	client, err := p.getClient(ctx)
+
+	if err != nil {
+		return nil, err
+	}
+
+	droplet, err := PowerDroplet(ctx, client, opts)
+
+	if err != nil || droplet == nil {
+		return nil, err
+	}
+
+	server := dropletToServer(*droplet)
+	return &server, nil
+}
+
+// Resize runs the droplet resize questions then resizes it
+func (p *Provider) Resize(ctx context.Context, opts cloud.ResizeOptions) (*cloud.Server, error) {
+	client, err := p.getClient(ctx)
+
+	if err != nil {
+		return nil, err
+	}
+
+	droplet, err := ResizeDroplet(ctx, client, opts)
+
+	if err != nil || droplet == nil {
+		return nil, err
+	}
+
+	server := dropletToServer(*droplet)
+	return &server, nil
+}
+
+// Rebuild runs the droplet rebuild questions then rebuilds it from the chosen image
+func (p *Provider) Rebuild(ctx context.Context, opts cloud.RebuildOptions) (*cloud.Server, error) {
+	client, err := p.getClient(ctx)
+
+	if err != nil {
+		return nil, err
+	}
+
+	droplet, err := RebuildDroplet(ctx, client, opts)
+
+	if err != nil || droplet == nil {
+		return nil, err
+	}
+
+	server := dropletToServer(*droplet)
+	return &server, nil
+}
+
+// Regions returns the regions droplets can be created in
+func (p *Provider) Regions(ctx context.Context) ([]utils.SelectItem, error) {
+	return p.listWithClient(ctx, regionList)
+}
+
+// Sizes returns the sizes droplets can be created with
+func (p *Provider) Sizes(ctx context.Context) ([]utils.SelectItem, error) {
+	return p.listWithClient(ctx, sizeList)
+}
+
+// Images returns the distribution, application and custom images droplets can be created from
+func (p *Provider) Images(ctx context.Context) ([]utils.SelectItem, error) {
+	return p.listWithClient(ctx, func(ctx context.Context, client *godo.Client) ([]utils.SelectItem, error) {
+		images := []utils.SelectItem{}
+
+		for _, listFunc := range []func(context.Context, *godo.Client) ([]utils.SelectItem, error){imageDistributionList, imageApplicationList, imageCustomList} {
+			list, err := listFunc(ctx, client)
+
+			if err != nil {
+				return nil, err
+			}
+
+			images = append(images, list...)
+		}
+
+		return images, nil
+	})
+}
+
+// VPCs returns the VPCs in the region
+func (p *Provider) VPCs(ctx context.Context, region string) ([]utils.SelectItem, error) {
+	return p.listWithClient(ctx, func(ctx context.Context, client *godo.Client) ([]utils.SelectItem, error) {
+		return vpcList(ctx, client, region)
+	})
+}
+
+// Features returns backups, monitoring, IPv6 and the droplet agent with the cost of backups for the size
+func (p *Provider) Features(ctx context.Context, size string) ([]utils.SelectItem, error) {
+	return p.listWithClient(ctx, func(ctx context.Context, client *godo.Client) ([]utils.SelectItem, error) {
+		return featureList(ctx, client, size, "")
+	})
+}
+
+// Volumes returns the volumes in the region that aren't attached to a droplet
+func (p *Provider) Volumes(ctx context.Context, region string) ([]utils.SelectItem, error) {
+	return p.listWithClient(ctx, func(ctx context.Context, client *godo.Client) ([]utils.SelectItem, error) {
+		return volumeList(ctx, client, region)
+	})
+}
+
+// SSHKeys returns the SSH keys on the account
+func (p *Provider) SSHKeys(ctx context.Context) ([]utils.SelectItem, error) {
+	return p.listWithClient(ctx, sshKeyList)
+}
+
+// listWithClient calls listFunc with the API client
+func (p *Provider) listWithClient(ctx context.Context, listFunc func(context.Context, *godo.Client) ([]utils.SelectItem, error)) ([]utils.SelectItem, error) {
+	client, err := p.getClient(ctx)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return listFunc(ctx, client)
+}
+
+// getClient returns the API client, asking for the token the first time it is needed
+func (p *Provider) getClient(ctx context.Context) (*godo.Client, error) {
+	if p.client != nil {
+		return p.client, nil
+	}
+
+	digitalOceanToken, err := getToken(ctx)
+
+	if err != nil {
+		return nil, err
+	}
+
+	p.client = godo.NewFromToken(digitalOceanToken)
+
+	return p.client, nil
+}
@@ -0,0 +1,83 @@
+package digitalocean
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/Joel-Valentine/cogo/cloud"
+	"github.com/Joel-Valentine/cogo/history"
+	"github.com/Joel-Valentine/cogo/utils"
+	"github.com/digitalocean/godo"
+)
+
+// RebuildDroplet will show the user a list of droplets, or pick the one in opts, then ask which image to
+// rebuild it from with the same steps as CreateDroplet, only offering images in the droplet's region
+// The rebuild is confirmed the same way as DestroyDroplet, re-entering the droplet's name, as everything
+// on the droplet is lost. returns the droplet once the rebuild has finished, or nil without an error
+// if the user decided not to rebuild it
+func RebuildDroplet(ctx context.Context, client *godo.Client, opts cloud.RebuildOptions) (*godo.Droplet, error) {
+	droplets, err := dropletList(ctx, client, "")
+
+	if err != nil {
+		return nil, err
+	}
+
+	selectedDropletIndex, err := getSelectedDropletIndex(droplets, opts.ServerSelector, "Select droplet to rebuild")
+
+	if err != nil {
+		return nil, err
+	}
+
+	droplet := droplets[selectedDropletIndex]
+
+	regions := []string{}
+	if droplet.Region != nil {
+		regions = append(regions, droplet.Region.Slug)
+	}
+
+	var image godo.Image
+
+	if opts.Image != "" {
+		image, err = findImage(ctx, client, opts.Image, regions)
+	} else {
+		image, err = getSelectedImage(ctx, client, regions, cloud.OpenHistory(cloud.CreateOptions{}).Recent(providerKey, history.Image))
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	shouldRebuild, err := cloud.ConfirmRebuild(dropletToServer(droplet), "droplet", utils.ImageValue(image), opts)
+
+	if err != nil || !shouldRebuild {
+		return nil, err
+	}
+
+	return rebuildDroplet(ctx, client, droplet.ID, image, opts)
+}
+
+// rebuildDroplet rebuilds the droplet from the image's slug, or its ID when it has no slug,
+// then waits for the rebuild to finish. returns the rebuilt droplet
+func rebuildDroplet(ctx context.Context, client *godo.Client, dropletID int, image godo.Image, opts cloud.RebuildOptions) (*godo.Droplet, error) {
+	var action *godo.Action
+	var err error
+
+	// Custom images and snapshots without a slug are rebuilt from their ID
+	if image.Slug != "" {
+		action, _, err = client.DropletActions.RebuildByImageSlug(ctx, dropletID, image.Slug)
+	} else {
+		action, _, err = client.DropletActions.RebuildByImageID(ctx, dropletID, image.ID)
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to rebuild droplet %d: %w", dropletID, err)
+	}
+
+	if err := waitForAction(ctx, client, dropletID, action, opts.Timeout); err != nil {
+		return nil, err
+	}
+
+	droplet, _, err := client.Droplets.Get(ctx, dropletID)
+
+	return droplet, err
+}
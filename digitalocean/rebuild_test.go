@@ -0,0 +1,79 @@
+package digitalocean
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/Joel-Valentine/cogo/cloud"
+	"github.com/digitalocean/godo"
+)
+
+func TestRebuildDroplet(t *testing.T) {
+	originalInterval := cloud.PollInterval
+	cloud.PollInterval = time.Millisecond
+	defer func() { cloud.PollInterval = originalInterval }()
+
+	tests := []struct {
+		name     string
+		image    godo.Image
+		expected string
+	}{
+		{
+			name:     "by slug",
+			image:    godo.Image{ID: 1001, Slug: "ubuntu-24-04-x64"},
+			expected: `"ubuntu-24-04-x64"`,
+		},
+		{
+			name:     "custom image by ID",
+			image:    godo.Image{ID: 5001, Name: "golden"},
+			expected: "5001",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var requested json.RawMessage
+
+			client := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				switch {
+				case r.Method == http.MethodPost && r.URL.Path == "/v2/droplets/1/actions":
+					var request struct {
+						Type  string          `json:"type"`
+						Image json.RawMessage `json:"image"`
+					}
+					if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+						t.Fatalf("failed to decode action request: %v", err)
+					}
+					if request.Type != "rebuild" {
+						t.Errorf("expected a rebuild, got %s", request.Type)
+					}
+
+					requested = request.Image
+					fmt.Fprint(w, `{"action": {"id": 1, "type": "rebuild", "status": "in-progress"}}`)
+				case r.URL.Path == "/v2/droplets/1/actions/1":
+					fmt.Fprint(w, `{"action": {"id": 1, "type": "rebuild", "status": "completed"}}`)
+				case r.URL.Path == "/v2/droplets/1":
+					fmt.Fprint(w, `{"droplet": {"id": 1, "name": "test-1", "status": "active"}}`)
+				default:
+					w.WriteHeader(http.StatusNotFound)
+				}
+			}))
+
+			droplet, err := rebuildDroplet(context.Background(), client, 1, tt.image, cloud.RebuildOptions{Timeout: time.Second})
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if string(requested) != tt.expected {
+				t.Errorf("expected image %s, got %s", tt.expected, requested)
+			}
+			if droplet.Name != "test-1" {
+				t.Errorf("expected the rebuilt droplet, got %+v", droplet)
+			}
+		})
+	}
+}
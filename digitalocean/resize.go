@@ -0,0 +1,168 @@
+package digitalocean
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/Joel-Valentine/cogo/cloud"
+	"github.com/Joel-Valentine/cogo/utils"
+	"github.com/digitalocean/godo"
+	"github.com/fatih/color"
+)
+
+// ResizeDroplet will show the user a list of droplets, or pick the one in opts, show its current size
+// and ask which of the sizes offered in its region to resize it to, each with the difference in monthly price
+// Sizes with a bigger disk are marked as growing the disk can't be undone, and the user is asked whether to grow it
+// The droplet is powered off for the resize if it is on, and powered back on afterwards
+// returns the resized droplet, or nil without an error if the user decided not to resize it
+func ResizeDroplet(ctx context.Context, client *godo.Client, opts cloud.ResizeOptions) (*godo.Droplet, error) {
+	droplets, err := dropletList(ctx, client, "")
+
+	if err != nil {
+		return nil, err
+	}
+
+	selectedDropletIndex, err := getSelectedDropletIndex(droplets, opts.ServerSelector, "Select droplet to resize")
+
+	if err != nil {
+		return nil, err
+	}
+
+	droplet := droplets[selectedDropletIndex]
+
+	sizes, err := allSizes(ctx, client)
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to get size list: %w", err)
+	}
+
+	current := currentSize(droplet, sizes)
+
+	color.Cyan("Current size: %s", utils.DescribeSize(current))
+
+	region := ""
+	if droplet.Region != nil {
+		region = droplet.Region.Slug
+	}
+
+	newSize, err := getResizeSize(utils.ResizeSizes(sizes, current.Slug, region, droplet.Disk), current, droplet, opts.Size)
+
+	if err != nil {
+		return nil, err
+	}
+
+	growDisk := opts.Disk && newSize.Disk > droplet.Disk
+
+	if newSize.Disk > droplet.Disk && !opts.Disk && !opts.Yes {
+		growDisk, err = confirmCreate(fmt.Sprintf("Also grow the disk from %d GB to %d GB? This can't be undone (y/n)", droplet.Disk, newSize.Disk))
+
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if !opts.Yes {
+		if droplet.Status != "off" {
+			color.Yellow("Droplet [%s] will be powered off for the resize and powered back on afterwards", droplet.Name)
+		}
+
+		shouldResize, err := confirmCreate(fmt.Sprintf("Resize %s from %s to %s? (y/n)", droplet.Name, current.Slug, newSize.Slug))
+
+		if err != nil {
+			return nil, err
+		}
+
+		if !shouldResize {
+			fmt.Println("You decided not to resize this droplet")
+			return nil, nil
+		}
+	}
+
+	return resizeDroplet(ctx, client, droplet, newSize.Slug, growDisk, opts)
+}
+
+// currentSize returns the droplet's size from the size list, which has its prices
+func currentSize(droplet godo.Droplet, sizes []godo.Size) godo.Size {
+	for _, size := range sizes {
+		if size.Slug == droplet.SizeSlug {
+			return size
+		}
+	}
+
+	if droplet.Size != nil {
+		return *droplet.Size
+	}
+
+	return godo.Size{Slug: droplet.SizeSlug}
+}
+
+// getResizeSize checks the given size is one the droplet can be resized to
+// or asks the user to select one when it is empty
+func getResizeSize(sizes []godo.Size, current godo.Size, droplet godo.Droplet, given string) (godo.Size, error) {
+	if given == current.Slug {
+		return godo.Size{}, fmt.Errorf("droplet %s is already %s", droplet.Name, given)
+	}
+
+	if len(sizes) == 0 {
+		return godo.Size{}, fmt.Errorf("there are no sizes droplet %s can be resized to", droplet.Name)
+	}
+
+	if given == "" {
+		selected, err := utils.AskAndAnswerCustomSelect("Resize To", utils.ParseResizeSizeList(sizes, current, droplet.Disk))
+
+		if err != nil {
+			return godo.Size{}, err
+		}
+
+		given = selected
+	}
+
+	for _, size := range sizes {
+		if size.Slug == given {
+			return size, nil
+		}
+	}
+
+	return godo.Size{}, fmt.Errorf("droplet %s can't be resized to %q, the size has to be available in its region with at least a %d GB disk", droplet.Name, given, droplet.Disk)
+}
+
+// resizeDroplet shuts the droplet down if it is on, resizes it, growing the disk when growDisk is set,
+// then powers it back on. A droplet that was powered off is powered back on even if the resize failed
+// returns the droplet once it has been resized
+func resizeDroplet(ctx context.Context, client *godo.Client, droplet godo.Droplet, size string, growDisk bool, opts cloud.ResizeOptions) (*godo.Droplet, error) {
+	poweredOff := false
+
+	if droplet.Status != "off" {
+		if _, err := powerDroplet(ctx, client, droplet.ID, cloud.PowerOptions{Action: cloud.PowerShutdown, Timeout: opts.Timeout, ShutdownTimeout: opts.ShutdownTimeout}); err != nil {
+			return nil, err
+		}
+
+		poweredOff = true
+	}
+
+	action, _, err := client.DropletActions.Resize(ctx, droplet.ID, size, growDisk)
+
+	if err == nil {
+		err = waitForAction(ctx, client, droplet.ID, action, opts.Timeout)
+	}
+
+	if err != nil {
+		err = fmt.Errorf("failed to resize droplet %d: %w", droplet.ID, err)
+
+		if poweredOff {
+			if _, powerErr := powerDroplet(ctx, client, droplet.ID, cloud.PowerOptions{Action: cloud.PowerOn, Timeout: opts.Timeout}); powerErr != nil {
+				color.Yellow("Droplet [%s] was left powered off: %s", droplet.Name, powerErr)
+			}
+		}
+
+		return nil, err
+	}
+
+	if !poweredOff {
+		resized, _, err := client.Droplets.Get(ctx, droplet.ID)
+
+		return resized, err
+	}
+
+	return powerDroplet(ctx, client, droplet.ID, cloud.PowerOptions{Action: cloud.PowerOn, Timeout: opts.Timeout})
+}
@@ -0,0 +1,157 @@
+package digitalocean
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Joel-Valentine/cogo/cloud"
+	"github.com/digitalocean/godo"
+)
+
+func TestResizeDroplet(t *testing.T) {
+	originalInterval := cloud.PollInterval
+	cloud.PollInterval = time.Millisecond
+	defer func() { cloud.PollInterval = originalInterval }()
+
+	tests := []struct {
+		name            string
+		status          string
+		growDisk        bool
+		resizeStatus    string
+		expectedActions string
+		expectError     bool
+	}{
+		{
+			name:            "powered off and back on",
+			status:          "active",
+			resizeStatus:    "completed",
+			expectedActions: "shutdown,resize,power_on",
+		},
+		{
+			name:            "already off",
+			status:          "off",
+			growDisk:        true,
+			resizeStatus:    "completed",
+			expectedActions: "resize",
+		},
+		{
+			name:            "powered back on when the resize fails",
+			status:          "active",
+			resizeStatus:    "errored",
+			expectedActions: "shutdown,resize,power_on",
+			expectError:     true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			actions := []string{}
+			disks := []bool{}
+
+			client := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				switch {
+				case r.Method == http.MethodPost && r.URL.Path == "/v2/droplets/1/actions":
+					var request struct {
+						Type string `json:"type"`
+						Size string `json:"size"`
+						Disk bool   `json:"disk"`
+					}
+					if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+						t.Fatalf("failed to decode action request: %v", err)
+					}
+
+					actions = append(actions, request.Type)
+					if request.Type == "resize" {
+						if request.Size != "s-2vcpu-4gb" {
+							t.Errorf("expected a resize to s-2vcpu-4gb, got %q", request.Size)
+						}
+						disks = append(disks, request.Disk)
+					}
+
+					fmt.Fprintf(w, `{"action": {"id": %d, "type": %q, "status": "in-progress"}}`, len(actions), request.Type)
+				case strings.HasPrefix(r.URL.Path, "/v2/droplets/1/actions/"):
+					var id int
+					fmt.Sscanf(strings.TrimPrefix(r.URL.Path, "/v2/droplets/1/actions/"), "%d", &id)
+
+					status := "completed"
+					if actions[id-1] == "resize" {
+						status = tt.resizeStatus
+					}
+
+					fmt.Fprintf(w, `{"action": {"id": %d, "type": %q, "status": %q}}`, id, actions[id-1], status)
+				case r.URL.Path == "/v2/droplets/1":
+					fmt.Fprint(w, `{"droplet": {"id": 1, "name": "web-1", "status": "active", "size_slug": "s-2vcpu-4gb"}}`)
+				default:
+					w.WriteHeader(http.StatusNotFound)
+				}
+			}))
+
+			droplet := godo.Droplet{ID: 1, Name: "web-1", Status: tt.status, SizeSlug: "s-1vcpu-1gb", Disk: 25}
+			opts := cloud.ResizeOptions{Timeout: time.Second, ShutdownTimeout: time.Second}
+
+			resized, err := resizeDroplet(context.Background(), client, droplet, "s-2vcpu-4gb", tt.growDisk, opts)
+
+			if strings.Join(actions, ",") != tt.expectedActions {
+				t.Errorf("expected actions %s, got %v", tt.expectedActions, actions)
+			}
+			if len(disks) != 1 || disks[0] != tt.growDisk {
+				t.Errorf("expected one resize with disk %v, got %v", tt.growDisk, disks)
+			}
+
+			if tt.expectError {
+				if err == nil {
+					t.Error("expected error, got nil")
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if resized.SizeSlug != "s-2vcpu-4gb" {
+				t.Errorf("expected the resized droplet, got %+v", resized)
+			}
+		})
+	}
+}
+
+func TestGetResizeSize(t *testing.T) {
+	sizes := []godo.Size{
+		{Slug: "s-1vcpu-2gb", Disk: 50},
+		{Slug: "s-2vcpu-4gb", Disk: 80},
+	}
+	current := godo.Size{Slug: "s-1vcpu-1gb", Disk: 25}
+	droplet := godo.Droplet{Name: "web-1", Disk: 25}
+
+	tests := []struct {
+		name          string
+		given         string
+		expectedError string
+	}{
+		{name: "valid size", given: "s-2vcpu-4gb"},
+		{name: "current size", given: "s-1vcpu-1gb", expectedError: "already s-1vcpu-1gb"},
+		{name: "size not offered", given: "s-8vcpu-16gb", expectedError: `can't be resized to "s-8vcpu-16gb"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			size, err := getResizeSize(sizes, current, droplet, tt.given)
+
+			if tt.expectedError != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.expectedError) {
+					t.Errorf("expected error %q, got %v", tt.expectedError, err)
+				}
+				return
+			}
+
+			if err != nil || size.Slug != tt.given {
+				t.Errorf("expected %s, got %+v, %v", tt.given, size, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,90 @@
+package digitalocean
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/Joel-Valentine/cogo/cloud"
+	"github.com/Joel-Valentine/cogo/utils"
+	"github.com/digitalocean/godo"
+)
+
+// waitForDroplet waits for the droplet to become active
+// returns the latest droplet, or an error if that didn't happen within the timeout
+func waitForDroplet(ctx context.Context, client *godo.Client, dropletID int, timeout time.Duration) (*godo.Droplet, error) {
+	var latest *godo.Droplet
+
+	_, err := cloud.WaitForServer(ctx, "droplet", "active", timeout, func(ctx context.Context) (*cloud.Server, error) {
+		droplet, _, err := client.Droplets.Get(ctx, dropletID)
+
+		if err != nil {
+			return nil, err
+		}
+
+		latest = droplet
+		server := dropletToServer(*droplet)
+		return &server, nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return latest, nil
+}
+
+// waitForDroplets waits for all of the created droplets in results at the same time, sharing one timeout
+// Each result's server is replaced by its active droplet, or the result gets the error if waiting for it failed
+// The server is kept when waiting fails, as the droplet was still created
+func waitForDroplets(ctx context.Context, client *godo.Client, results []cloud.CreateResult, timeout time.Duration) {
+	spinner := utils.NewSpinner(fmt.Sprintf("Waiting for %d droplets to become active...", len(results)))
+	spinner.Start()
+	defer spinner.Stop()
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	var wg sync.WaitGroup
+
+	for index := range results {
+		if results[index].Err != nil || results[index].Server == nil {
+			continue
+		}
+
+		wg.Add(1)
+
+		go func(result *cloud.CreateResult) {
+			defer wg.Done()
+
+			dropletID, err := strconv.Atoi(result.Server.ID)
+
+			if err != nil {
+				result.Err = err
+				return
+			}
+
+			server, err := cloud.PollServer(ctx, "droplet", "active", timeout, func(ctx context.Context) (*cloud.Server, error) {
+				droplet, _, err := client.Droplets.Get(ctx, dropletID)
+
+				if err != nil {
+					return nil, err
+				}
+
+				server := dropletToServer(*droplet)
+				return &server, nil
+			})
+
+			if err != nil {
+				result.Err = err
+				return
+			}
+
+			result.Server = server
+		}(&results[index])
+	}
+
+	wg.Wait()
+}
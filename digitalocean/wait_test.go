@@ -0,0 +1,123 @@
+package digitalocean
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/Joel-Valentine/cogo/cloud"
+	"github.com/digitalocean/godo"
+)
+
+// newTestClient creates a godo client that sends all requests to the given handler
+func newTestClient(t *testing.T, handler http.Handler) *godo.Client {
+	t.Helper()
+
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+
+	client, err := godo.New(nil, godo.SetBaseURL(server.URL+"/"))
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+
+	return client
+}
+
+func TestWaitForDroplet_BecomesActive(t *testing.T) {
+	originalInterval := cloud.PollInterval
+	cloud.PollInterval = time.Millisecond
+	defer func() { cloud.PollInterval = originalInterval }()
+
+	var calls atomic.Int32
+	client := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) < 3 {
+			fmt.Fprint(w, `{"droplet": {"id": 1, "name": "web-1", "status": "new", "networks": {"v4": []}}}`)
+			return
+		}
+		fmt.Fprint(w, `{"droplet": {"id": 1, "name": "web-1", "status": "active", "networks": {"v4": [{"ip_address": "203.0.113.10", "type": "public"}]}}}`)
+	}))
+
+	droplet, err := waitForDroplet(context.Background(), client, 1, time.Second)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	ip, _ := droplet.PublicIPv4()
+	if ip != "203.0.113.10" {
+		t.Errorf("expected IP 203.0.113.10, got %q", ip)
+	}
+
+	if calls.Load() != 3 {
+		t.Errorf("expected 3 polls, got %d", calls.Load())
+	}
+}
+
+func TestWaitForDroplet_ActiveWithoutIP(t *testing.T) {
+	originalInterval := cloud.PollInterval
+	cloud.PollInterval = time.Millisecond
+	defer func() { cloud.PollInterval = originalInterval }()
+
+	client := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"droplet": {"id": 1, "name": "web-1", "status": "active", "networks": {"v4": []}}}`)
+	}))
+
+	// the IP can be assigned after the droplet is active, so it is shown as pending rather than waited for
+	droplet, err := waitForDroplet(context.Background(), client, 1, 50*time.Millisecond)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if droplet.Status != "active" {
+		t.Errorf("expected active droplet, got %q", droplet.Status)
+	}
+}
+
+func TestWaitForDroplets(t *testing.T) {
+	originalInterval := cloud.PollInterval
+	cloud.PollInterval = time.Millisecond
+	defer func() { cloud.PollInterval = originalInterval }()
+
+	client := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// droplet 2 never becomes active, so waiting for it times out
+		if strings.HasSuffix(r.URL.Path, "/2") {
+			fmt.Fprint(w, `{"droplet": {"id": 2, "name": "worker-2", "status": "new", "networks": {"v4": []}}}`)
+			return
+		}
+		fmt.Fprint(w, `{"droplet": {"id": 1, "name": "worker-1", "status": "active", "networks": {"v4": [{"ip_address": "203.0.113.10", "type": "public"}]}}}`)
+	}))
+
+	results := []cloud.CreateResult{
+		{Name: "worker-1", Server: &cloud.Server{ID: "1", Name: "worker-1", Status: "new"}},
+		{Name: "worker-2", Server: &cloud.Server{ID: "2", Name: "worker-2", Status: "new"}},
+		{Name: "worker-3", Err: errors.New("region is not available")},
+	}
+
+	timeout := 100 * time.Millisecond
+	start := time.Now()
+
+	waitForDroplets(context.Background(), client, results, timeout)
+
+	// all of them share one timeout rather than each getting their own
+	if elapsed := time.Since(start); elapsed > 2*timeout {
+		t.Errorf("expected waiting to take about %s, took %s", timeout, elapsed)
+	}
+
+	if results[0].Err != nil || results[0].Server.Status != "active" || results[0].Server.PublicIPv4 != "203.0.113.10" {
+		t.Errorf("expected worker-1 to be active, got %+v", results[0])
+	}
+
+	if results[1].Err == nil || results[1].Server == nil {
+		t.Errorf("expected worker-2 to time out and keep its server, got %+v", results[1])
+	}
+
+	if results[2].Server != nil {
+		t.Errorf("expected worker-3 not to be waited for, got %+v", results[2])
+	}
+}
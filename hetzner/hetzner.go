@@ -0,0 +1,364 @@
+// Package hetzner is used for interacting with Hetzner Cloud.
+// currently allows for creation, listing and destroying of servers
+package hetzner
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Joel-Valentine/cogo/cloud"
+	"github.com/Joel-Valentine/cogo/credentials"
+	"github.com/Joel-Valentine/cogo/utils"
+	"github.com/fatih/color"
+	"github.com/hetznercloud/hcloud-go/v2/hcloud"
+)
+
+// endpointEnvVar overrides the API endpoint, the same variable the hcloud CLI uses
+const endpointEnvVar = "HCLOUD_ENDPOINT"
+
+// Provider makes Hetzner Cloud available to the commands through the cloud registry
+type Provider struct {
+	client *hcloud.Client
+}
+
+var _ cloud.Provider = &Provider{}
+var _ cloud.UserDataProvider = &Provider{}
+
+// NewProvider creates the Hetzner Cloud provider
+func NewProvider() *Provider {
+	return &Provider{}
+}
+
+// Key returns the identifier used to select Hetzner Cloud
+func (p *Provider) Key() string {
+	return "hetzner"
+}
+
+// Name returns the provider name
+func (p *Provider) Name() string {
+	return "Hetzner Cloud"
+}
+
+// ServerNoun returns what Hetzner Cloud calls its servers
+func (p *Provider) ServerNoun() string {
+	return "server"
+}
+
+// HasCredentials returns true if a token can be found without prompting for one
+func (p *Provider) HasCredentials(ctx context.Context) bool {
+	return credentials.Hetzner.HasToken(ctx)
+}
+
+// CredentialService returns where the Hetzner Cloud token is kept
+func (p *Provider) CredentialService() credentials.Service {
+	return credentials.Hetzner
+}
+
+// UserDataLimit returns the largest user data a server accepts, 32 KiB
+func (p *Provider) UserDataLimit() int {
+	return 32 * 1024
+}
+
+// Create asks the create wizard questions then creates the server
+// waiting for it to be running if opts.Wait is set
+func (p *Provider) Create(ctx context.Context, opts cloud.CreateOptions) (*cloud.Server, error) {
+	answers, err := cloud.AskCreateQuestions(ctx, p, opts)
+
+	if err != nil || answers == nil {
+		return nil, err
+	}
+
+	// Hetzner only gives a server a root password when it has no SSH keys
+	if err := cloud.RequireSSHKey(answers.SSHKeys, opts); err != nil {
+		return nil, err
+	}
+
+	client, err := p.getClient(ctx)
+
+	if err != nil {
+		return nil, err
+	}
+
+	request := hcloud.ServerCreateOpts{
+		Name:       answers.Name,
+		ServerType: &hcloud.ServerType{Name: answers.Size},
+		Image:      &hcloud.Image{Name: answers.Image},
+		Location:   &hcloud.Location{Name: answers.Region},
+		UserData:   answers.UserData,
+	}
+
+	for _, key := range answers.SSHKeys {
+		id, err := strconv.ParseInt(key, 10, 64)
+
+		if err != nil {
+			return nil, fmt.Errorf("ssh key %q is not an ID: %w", key, err)
+		}
+
+		request.SSHKeys = append(request.SSHKeys, &hcloud.SSHKey{ID: id})
+	}
+
+	result, _, err := client.Server.Create(ctx, request)
+
+	if err != nil {
+		return nil, err
+	}
+
+	server := serverToCloudServer(result.Server)
+
+	if result.RootPassword != "" && opts.ShowRootPassword {
+		cloud.PrintRootPassword(p.ServerNoun(), server.Name, result.RootPassword)
+	}
+
+	if !opts.Wait {
+		return &server, nil
+	}
+
+	color.Green("Server [%s] was created!", server.Name)
+
+	active, err := cloud.WaitForServer(ctx, p.ServerNoun(), "running", opts.WaitTimeout, func(ctx context.Context) (*cloud.Server, error) {
+		latest, _, err := client.Server.GetByID(ctx, result.Server.ID)
+
+		if err != nil {
+			return nil, err
+		}
+
+		if latest == nil {
+			return nil, fmt.Errorf("server %d was not found", result.Server.ID)
+		}
+
+		server := serverToCloudServer(latest)
+		return &server, nil
+	})
+
+	// the server exists even if waiting for it failed, so it is returned along with the error
+	if err != nil {
+		return &server, err
+	}
+
+	return active, nil
+}
+
+// List returns all of the servers in the project
+func (p *Provider) List(ctx context.Context, opts cloud.ListOptions) ([]cloud.Server, error) {
+	client, err := p.getClient(ctx)
+
+	if err != nil {
+		return nil, err
+	}
+
+	hetznerServers, err := client.Server.All(ctx)
+
+	if err != nil {
+		return nil, err
+	}
+
+	servers := []cloud.Server{}
+	for _, server := range hetznerServers {
+		servers = append(servers, serverToCloudServer(server))
+	}
+
+	return servers, nil
+}
+
+// Destroy will show the user a list of servers and ask the same three
+// confirmations as the DigitalOcean destroy before deleting the selected one
+func (p *Provider) Destroy(ctx context.Context, opts cloud.DestroyOptions) (*cloud.Server, error) {
+	servers, err := p.List(ctx, cloud.ListOptions{})
+
+	if err != nil {
+		return nil, err
+	}
+
+	selectedIndex, err := cloud.SelectServerIndex(servers, opts.ServerSelector, "Select server to delete")
+
+	if err != nil {
+		return nil, err
+	}
+
+	selectedServer := servers[selectedIndex]
+
+	shouldDestroy, err := cloud.ConfirmDestroy(selectedServer, p.ServerNoun(), opts)
+
+	if err != nil || !shouldDestroy {
+		return nil, err
+	}
+
+	id, err := strconv.ParseInt(selectedServer.ID, 10, 64)
+
+	if err != nil {
+		return nil, err
+	}
+
+	client, err := p.getClient(ctx)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if _, _, err := client.Server.DeleteWithResult(ctx, &hcloud.Server{ID: id}); err != nil {
+		fmt.Printf("Something went wrong deleting server: %s", err)
+		return nil, err
+	}
+
+	return &selectedServer, nil
+}
+
+// Regions returns the locations servers can be created in
+func (p *Provider) Regions(ctx context.Context) ([]utils.SelectItem, error) {
+	client, err := p.getClient(ctx)
+
+	if err != nil {
+		return nil, err
+	}
+
+	locations, err := client.Location.All(ctx)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return utils.ParseHetznerLocationListResults(locations), nil
+}
+
+// Sizes returns the server types servers can be created with
+func (p *Provider) Sizes(ctx context.Context) ([]utils.SelectItem, error) {
+	client, err := p.getClient(ctx)
+
+	if err != nil {
+		return nil, err
+	}
+
+	serverTypes, err := client.ServerType.All(ctx)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return utils.ParseHetznerServerTypeListResults(serverTypes), nil
+}
+
+// Images returns the system images servers can be created from
+func (p *Provider) Images(ctx context.Context) ([]utils.SelectItem, error) {
+	client, err := p.getClient(ctx)
+
+	if err != nil {
+		return nil, err
+	}
+
+	images, err := client.Image.AllWithOpts(ctx, hcloud.ImageListOpts{Type: []hcloud.ImageType{hcloud.ImageTypeSystem}})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return utils.ParseHetznerImageListResults(images), nil
+}
+
+// SSHKeys returns the SSH keys in the project
+func (p *Provider) SSHKeys(ctx context.Context) ([]utils.SelectItem, error) {
+	client, err := p.getClient(ctx)
+
+	if err != nil {
+		return nil, err
+	}
+
+	keys, err := client.SSHKey.All(ctx)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return utils.ParseHetznerSSHKeyListResults(keys), nil
+}
+
+// getClient returns the API client, asking for the token the first time it is needed
+func (p *Provider) getClient(ctx context.Context) (*hcloud.Client, error) {
+	if p.client != nil {
+		return p.client, nil
+	}
+
+	token, err := credentials.Hetzner.GetToken(ctx)
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to get token: %w", err)
+	}
+
+	opts := []hcloud.ClientOption{hcloud.WithToken(token)}
+	if endpoint := os.Getenv(endpointEnvVar); endpoint != "" {
+		opts = append(opts, hcloud.WithEndpoint(endpoint))
+	}
+
+	p.client = hcloud.NewClient(opts...)
+
+	return p.client, nil
+}
+
+// serverToCloudServer converts a Hetzner server into the provider independent format
+func serverToCloudServer(server *hcloud.Server) cloud.Server {
+	converted := cloud.Server{
+		ID:     strconv.FormatInt(server.ID, 10),
+		Name:   server.Name,
+		Status: string(server.Status),
+		Tags:   []string{},
+	}
+
+	if !server.Created.IsZero() {
+		converted.CreatedAt = server.Created.Format(time.RFC3339)
+	}
+
+	if server.Datacenter != nil && server.Datacenter.Location != nil {
+		converted.Region = server.Datacenter.Location.Name
+	}
+
+	if server.ServerType != nil {
+		converted.Size = server.ServerType.Name
+	}
+
+	if !server.PublicNet.IPv4.IsUnspecified() {
+		converted.PublicIPv4 = server.PublicNet.IPv4.IP.String()
+	}
+
+	// Servers get a whole /64, shown as the network rather than one address in it
+	if !server.PublicNet.IPv6.IsUnspecified() && server.PublicNet.IPv6.Network != nil {
+		converted.PublicIPv6 = server.PublicNet.IPv6.Network.String()
+	}
+
+	if len(server.PrivateNet) > 0 {
+		converted.PrivateIPv4 = server.PrivateNet[0].IP.String()
+		if server.PrivateNet[0].Network != nil {
+			converted.VPC = strconv.FormatInt(server.PrivateNet[0].Network.ID, 10)
+		}
+	}
+
+	if server.Image != nil {
+		converted.Image = server.Image.Name
+	}
+
+	// Hetzner uses key=value labels rather than tags
+	for key, value := range server.Labels {
+		if value == "" {
+			converted.Tags = append(converted.Tags, key)
+		} else {
+			converted.Tags = append(converted.Tags, key+"="+value)
+		}
+	}
+
+	sort.Strings(converted.Tags)
+
+	if server.ServerType != nil {
+		for _, pricing := range server.ServerType.Pricings {
+			if pricing.Location != nil && pricing.Location.Name == converted.Region {
+				if monthly, err := strconv.ParseFloat(strings.TrimSpace(pricing.Monthly.Gross), 64); err == nil {
+					converted.PriceMonthly = monthly
+				}
+			}
+		}
+	}
+
+	return converted
+}
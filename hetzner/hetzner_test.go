@@ -0,0 +1,226 @@
+package hetzner
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"slices"
+	"testing"
+
+	"github.com/Joel-Valentine/cogo/cloud"
+	"github.com/Joel-Valentine/cogo/cloud/cloudtest"
+	"github.com/hetznercloud/hcloud-go/v2/hcloud"
+	"github.com/hetznercloud/hcloud-go/v2/hcloud/schema"
+)
+
+// projectSSHKeys are the keys in the project of the stand-in API
+var projectSSHKeys = []schema.SSHKey{{ID: 7, Name: "laptop"}}
+
+// newTestProvider creates a provider using a stand-in API, which expects every key in sshKeys to be added to new servers
+func newTestProvider(t *testing.T, sshKeys []schema.SSHKey) (*Provider, *cloudtest.Servers[schema.Server]) {
+	t.Helper()
+
+	servers := &cloudtest.Servers[schema.Server]{}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /locations", func(w http.ResponseWriter, r *http.Request) {
+		cloudtest.WriteJSON(w, http.StatusOK, schema.LocationListResponse{Locations: []schema.Location{{ID: 1, Name: "fsn1", Description: "Falkenstein DC Park 1"}}})
+	})
+	mux.HandleFunc("GET /server_types", func(w http.ResponseWriter, r *http.Request) {
+		cloudtest.WriteJSON(w, http.StatusOK, schema.ServerTypeListResponse{ServerTypes: []schema.ServerType{{ID: 1, Name: "cx22", Description: "CX22", Cores: 2, Memory: 4, Disk: 40}}})
+	})
+	mux.HandleFunc("GET /images", func(w http.ResponseWriter, r *http.Request) {
+		name := "ubuntu-24.04"
+		cloudtest.WriteJSON(w, http.StatusOK, schema.ImageListResponse{Images: []schema.Image{{ID: 1, Name: &name, Description: "Ubuntu 24.04", Type: "system"}}})
+	})
+	mux.HandleFunc("GET /ssh_keys", func(w http.ResponseWriter, r *http.Request) {
+		cloudtest.WriteJSON(w, http.StatusOK, schema.SSHKeyListResponse{SSHKeys: sshKeys})
+	})
+	mux.HandleFunc("GET /servers", func(w http.ResponseWriter, r *http.Request) {
+		cloudtest.WriteJSON(w, http.StatusOK, schema.ServerListResponse{Servers: servers.List()})
+	})
+	mux.HandleFunc("POST /servers", func(w http.ResponseWriter, r *http.Request) {
+		var request schema.ServerCreateRequest
+		if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		expectedKeys := []int64{}
+		for _, key := range sshKeys {
+			expectedKeys = append(expectedKeys, key.ID)
+		}
+		if !slices.Equal(request.SSHKeys, expectedKeys) {
+			t.Errorf("expected ssh keys %v to be sent, got %v", expectedKeys, request.SSHKeys)
+		}
+
+		// Hetzner only sets a root password when no SSH keys are added
+		var rootPassword *string
+		if len(request.SSHKeys) == 0 {
+			password := "generated-root-password"
+			rootPassword = &password
+		}
+
+		server := servers.Add(func(id int) schema.Server {
+			image := request.Image.(string)
+			return schema.Server{
+				ID:         int64(id),
+				Name:       request.Name,
+				Status:     "running",
+				ServerType: schema.ServerType{Name: request.ServerType.(string)},
+				Datacenter: schema.Datacenter{Location: schema.Location{Name: request.Location}},
+				Image:      &schema.Image{Name: &image},
+				PublicNet: schema.ServerPublicNet{
+					IPv4: schema.ServerPublicNetIPv4{IP: "203.0.113.10"},
+					IPv6: schema.ServerPublicNetIPv6{IP: "2001:db8::/64"},
+				},
+			}
+		})
+
+		cloudtest.WriteJSON(w, http.StatusCreated, schema.ServerCreateResponse{Server: server, Action: schema.Action{ID: 1, Status: "running"}, RootPassword: rootPassword})
+	})
+	mux.HandleFunc("GET /servers/{id}", func(w http.ResponseWriter, r *http.Request) {
+		server, ok := servers.Get(r.PathValue("id"))
+		if !ok {
+			cloudtest.WriteJSON(w, http.StatusNotFound, schema.ErrorResponse{Error: schema.Error{Code: "not_found", Message: "server not found"}})
+			return
+		}
+		cloudtest.WriteJSON(w, http.StatusOK, schema.ServerGetResponse{Server: server})
+	})
+	mux.HandleFunc("DELETE /servers/{id}", func(w http.ResponseWriter, r *http.Request) {
+		if !servers.Delete(r.PathValue("id")) {
+			cloudtest.WriteJSON(w, http.StatusNotFound, schema.ErrorResponse{Error: schema.Error{Code: "not_found", Message: "server not found"}})
+			return
+		}
+		cloudtest.WriteJSON(w, http.StatusOK, schema.ServerDeleteResponse{Action: schema.Action{ID: 2, Status: "running"}})
+	})
+
+	server := cloudtest.NewServer(t, mux)
+
+	return &Provider{client: hcloud.NewClient(hcloud.WithToken("test-token"), hcloud.WithEndpoint(server.URL))}, servers
+}
+
+func TestProvider_CreateListDestroy(t *testing.T) {
+	ctx := context.Background()
+	provider, servers := newTestProvider(t, projectSSHKeys)
+
+	created, err := provider.Create(ctx, cloud.CreateOptions{
+		Name:    "web-1",
+		Image:   "ubuntu-24.04",
+		Size:    "cx22",
+		Region:  "fsn1",
+		SSHKeys: []string{"7"},
+		Yes:     true,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error creating server: %v", err)
+	}
+
+	if created.Name != "web-1" || created.Region != "fsn1" || created.Image != "ubuntu-24.04" {
+		t.Errorf("unexpected created server %+v", created)
+	}
+
+	listed, err := provider.List(ctx, cloud.ListOptions{})
+	if err != nil {
+		t.Fatalf("unexpected error listing servers: %v", err)
+	}
+
+	if len(listed) != 1 || listed[0].PublicIPv4 != "203.0.113.10" || listed[0].PublicIPv6 != "2001:db8::/64" {
+		t.Errorf("unexpected servers %+v", listed)
+	}
+
+	destroyed, err := provider.Destroy(ctx, cloud.DestroyOptions{
+		ServerSelector: cloud.ServerSelector{Name: "web-1"},
+		ConfirmName:    "web-1",
+		Yes:            true,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error destroying server: %v", err)
+	}
+
+	if destroyed.ID != created.ID {
+		t.Errorf("expected server %s to be destroyed, got %s", created.ID, destroyed.ID)
+	}
+
+	if servers.Len() != 0 {
+		t.Errorf("expected no servers left, got %d", servers.Len())
+	}
+}
+
+func TestProvider_CreateInvalidSize(t *testing.T) {
+	provider, servers := newTestProvider(t, projectSSHKeys)
+
+	_, err := provider.Create(context.Background(), cloud.CreateOptions{
+		Name:    "web-1",
+		Image:   "ubuntu-24.04",
+		Size:    "cx99",
+		Region:  "fsn1",
+		SSHKeys: []string{"7"},
+		Yes:     true,
+	})
+	if err == nil {
+		t.Error("expected error for unknown server type, got nil")
+	}
+
+	if servers.Len() != 0 {
+		t.Errorf("expected no server to be created, got %d", servers.Len())
+	}
+}
+
+func TestProvider_DestroyWrongConfirmName(t *testing.T) {
+	ctx := context.Background()
+	provider, servers := newTestProvider(t, projectSSHKeys)
+
+	if _, err := provider.Create(ctx, cloud.CreateOptions{Name: "web-1", Image: "ubuntu-24.04", Size: "cx22", Region: "fsn1", SSHKeys: []string{"7"}, Yes: true}); err != nil {
+		t.Fatalf("unexpected error creating server: %v", err)
+	}
+
+	_, err := provider.Destroy(ctx, cloud.DestroyOptions{
+		ServerSelector: cloud.ServerSelector{Name: "web-1"},
+		ConfirmName:    "web-2",
+		Yes:            true,
+	})
+	if err == nil {
+		t.Error("expected error for mismatched name, got nil")
+	}
+
+	if servers.Len() != 1 {
+		t.Errorf("expected server to still exist, got %d servers", servers.Len())
+	}
+}
+
+func TestProvider_CreateWithoutSSHKey(t *testing.T) {
+	tests := []struct {
+		name             string
+		showRootPassword bool
+		expectedErr      error
+		expectedCount    int
+	}{
+		{name: "refused", expectedErr: cloud.ErrNoSSHKey, expectedCount: 0},
+		{name: "root password shown", showRootPassword: true, expectedCount: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// without any keys in the project none are chosen
+			provider, servers := newTestProvider(t, []schema.SSHKey{})
+
+			_, err := provider.Create(context.Background(), cloud.CreateOptions{
+				Name:             "web-1",
+				Image:            "ubuntu-24.04",
+				Size:             "cx22",
+				Region:           "fsn1",
+				ShowRootPassword: tt.showRootPassword,
+				Yes:              true,
+			})
+			if !errors.Is(err, tt.expectedErr) {
+				t.Errorf("expected error %v, got %v", tt.expectedErr, err)
+			}
+
+			if servers.Len() != tt.expectedCount {
+				t.Errorf("expected %d servers, got %d", tt.expectedCount, servers.Len())
+			}
+		})
+	}
+}
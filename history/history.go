@@ -0,0 +1,121 @@
+// Package history remembers the most recent answers given to the steps of the create wizard,
+// so the next run can pin them above the full list with the cursor on the last choice
+package history
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/Joel-Valentine/cogo/config"
+)
+
+// FileName is the file in the cogo config directory the history is kept in
+const FileName = "history.json"
+
+// Limit is how many answers are remembered for each step
+const Limit = 5
+
+// Steps of the create wizard that are remembered
+const (
+	Image   = "image"
+	Size    = "size"
+	Region  = "region"
+	SSHKeys = "ssh-keys"
+)
+
+// History holds the recent answers of each provider, a nil History remembers nothing
+type History struct {
+	path string
+
+	// Answers holds the recent answers to each step of each provider keyed by the provider's key
+	// then the step, most recent first
+	Answers map[string]map[string][]string `json:"answers"`
+}
+
+// Load reads the history from the cogo config directory, a missing file is an empty history
+func Load() (*History, error) {
+	dir, err := config.Dir()
+
+	if err != nil {
+		return nil, err
+	}
+
+	h := &History{path: filepath.Join(dir, FileName), Answers: map[string]map[string][]string{}}
+
+	content, err := os.ReadFile(h.path)
+
+	if errors.Is(err, os.ErrNotExist) {
+		return h, nil
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to read history: %w", err)
+	}
+
+	if err := json.Unmarshal(content, h); err != nil {
+		return nil, fmt.Errorf("failed to read history %s: %w", h.path, err)
+	}
+
+	if h.Answers == nil {
+		h.Answers = map[string]map[string][]string{}
+	}
+
+	return h, nil
+}
+
+// Recent returns the recent answers to the provider's step, most recent first
+func (h *History) Recent(provider string, step string) []string {
+	if h == nil {
+		return nil
+	}
+
+	return h.Answers[provider][step]
+}
+
+// Remember puts the values at the front of the recent answers to the provider's step, in the order given
+// any older copies of them are dropped, as are the answers beyond Limit
+func (h *History) Remember(provider string, step string, values ...string) {
+	if h == nil || len(values) == 0 {
+		return
+	}
+
+	if h.Answers[provider] == nil {
+		h.Answers[provider] = map[string][]string{}
+	}
+
+	recent := []string{}
+	seen := map[string]bool{}
+
+	for _, value := range append(append([]string{}, values...), h.Answers[provider][step]...) {
+		if value == "" || seen[value] {
+			continue
+		}
+
+		seen[value] = true
+		recent = append(recent, value)
+	}
+
+	if len(recent) > Limit {
+		recent = recent[:Limit]
+	}
+
+	h.Answers[provider][step] = recent
+}
+
+// Save writes the history back to the file it was loaded from
+func (h *History) Save() error {
+	if h == nil {
+		return nil
+	}
+
+	content, err := json.MarshalIndent(h, "", "  ")
+
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(h.path, content, 0600)
+}
@@ -0,0 +1,122 @@
+package history
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/Joel-Valentine/cogo/config"
+)
+
+func TestRemember(t *testing.T) {
+	tests := []struct {
+		name     string
+		before   []string
+		values   []string
+		expected []string
+	}{
+		{
+			name:     "first answer",
+			values:   []string{"lon1"},
+			expected: []string{"lon1"},
+		},
+		{
+			name:     "most recent first",
+			before:   []string{"ams3"},
+			values:   []string{"lon1"},
+			expected: []string{"lon1", "ams3"},
+		},
+		{
+			name:     "an old answer moves to the front",
+			before:   []string{"ams3", "lon1", "fra1"},
+			values:   []string{"lon1"},
+			expected: []string{"lon1", "ams3", "fra1"},
+		},
+		{
+			name:     "several values keep their order",
+			before:   []string{"3"},
+			values:   []string{"1", "2"},
+			expected: []string{"1", "2", "3"},
+		},
+		{
+			name:     "only the limit is kept",
+			before:   []string{"b", "c", "d", "e", "f"},
+			values:   []string{"a"},
+			expected: []string{"a", "b", "c", "d", "e"},
+		},
+		{
+			name:     "empty values are ignored",
+			before:   []string{"lon1"},
+			values:   []string{""},
+			expected: []string{"lon1"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := &History{Answers: map[string]map[string][]string{}}
+			if tt.before != nil {
+				h.Answers["do"] = map[string][]string{Region: tt.before}
+			}
+
+			h.Remember("do", Region, tt.values...)
+
+			if got := h.Recent("do", Region); strings.Join(got, ",") != strings.Join(tt.expected, ",") {
+				t.Errorf("Recent() = %v, want %v", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestLoadSave(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv(config.DirEnvVar, dir)
+
+	h, err := Load()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if recent := h.Recent("do", Size); len(recent) != 0 {
+		t.Errorf("expected no history without a file, got %v", recent)
+	}
+
+	h.Remember("do", Size, "s-1vcpu-1gb")
+	h.Remember("mock", Size, "s-2vcpu-4gb")
+
+	if err := h.Save(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	reloaded, err := Load()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if recent := reloaded.Recent("do", Size); strings.Join(recent, ",") != "s-1vcpu-1gb" {
+		t.Errorf("expected do's size to be remembered, got %v", recent)
+	}
+	if recent := reloaded.Recent("mock", Size); strings.Join(recent, ",") != "s-2vcpu-4gb" {
+		t.Errorf("expected providers to be kept apart, got %v", recent)
+	}
+
+	if err := os.WriteFile(filepath.Join(dir, FileName), []byte("not json"), 0600); err != nil {
+		t.Fatalf("failed to write history: %v", err)
+	}
+	if _, err := Load(); err == nil {
+		t.Error("expected an error loading a corrupt history")
+	}
+}
+
+func TestNilHistory(t *testing.T) {
+	var h *History
+
+	h.Remember("do", Region, "lon1")
+
+	if recent := h.Recent("do", Region); recent != nil {
+		t.Errorf("expected a nil history to have no answers, got %v", recent)
+	}
+	if err := h.Save(); err != nil {
+		t.Errorf("unexpected error saving a nil history: %v", err)
+	}
+}
@@ -0,0 +1,405 @@
+// Package linode is used for interacting with Linode (Akamai).
+// currently allows for creation, listing and destroying of Linode instances
+package linode
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Joel-Valentine/cogo/cloud"
+	"github.com/Joel-Valentine/cogo/credentials"
+	"github.com/Joel-Valentine/cogo/utils"
+	"github.com/fatih/color"
+	"github.com/linode/linodego"
+)
+
+// Provider makes Linode available to the commands through the cloud registry
+type Provider struct {
+	client *linodego.Client
+}
+
+var _ cloud.Provider = &Provider{}
+var _ cloud.UserDataProvider = &Provider{}
+var _ cloud.TagProvider = &Provider{}
+
+// NewProvider creates the Linode provider
+func NewProvider() *Provider {
+	return &Provider{}
+}
+
+// Key returns the identifier used to select Linode
+func (p *Provider) Key() string {
+	return "linode"
+}
+
+// Name returns the provider name
+func (p *Provider) Name() string {
+	return "Linode"
+}
+
+// ServerNoun returns what Linode calls its servers
+func (p *Provider) ServerNoun() string {
+	return "linode"
+}
+
+// HasCredentials returns true if a token can be found without prompting for one
+func (p *Provider) HasCredentials(ctx context.Context) bool {
+	return credentials.Linode.HasToken(ctx)
+}
+
+// CredentialService returns where the Linode token is kept
+func (p *Provider) CredentialService() credentials.Service {
+	return credentials.Linode
+}
+
+// SupportsTags returns true, Linode instances can be tagged
+func (p *Provider) SupportsTags() bool {
+	return true
+}
+
+// UserDataLimit returns the largest user data an instance accepts
+// it is only available in regions with the Metadata service
+func (p *Provider) UserDataLimit() int {
+	return 16 * 1024
+}
+
+// Create asks the create wizard questions then creates the Linode instance
+// waiting for it to be running if opts.Wait is set
+func (p *Provider) Create(ctx context.Context, opts cloud.CreateOptions) (*cloud.Server, error) {
+	answers, err := cloud.AskCreateQuestions(ctx, p, opts)
+
+	if err != nil || answers == nil {
+		return nil, err
+	}
+
+	if err := cloud.RequireSSHKey(answers.SSHKeys, opts); err != nil {
+		return nil, err
+	}
+
+	client, err := p.getClient(ctx)
+
+	if err != nil {
+		return nil, err
+	}
+
+	// Linode requires a root password even when logging in with SSH keys
+	// it is thrown away when there are keys to log in with
+	rootPass, err := generateRootPass()
+
+	if err != nil {
+		return nil, err
+	}
+
+	request := linodego.InstanceCreateOptions{
+		Label:    answers.Name,
+		Region:   answers.Region,
+		Type:     answers.Size,
+		Image:    answers.Image,
+		RootPass: rootPass,
+		Tags:     answers.Tags,
+	}
+
+	if answers.UserData != "" {
+		request.Metadata = &linodego.InstanceMetadataOptions{UserData: base64.StdEncoding.EncodeToString([]byte(answers.UserData))}
+	}
+
+	if len(answers.SSHKeys) > 0 {
+		request.AuthorizedKeys, err = p.publicKeys(ctx, answers.SSHKeys)
+
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	instance, err := client.CreateInstance(ctx, request)
+
+	if err != nil {
+		return nil, err
+	}
+
+	server := instanceToServer(instance)
+
+	if len(request.AuthorizedKeys) == 0 {
+		cloud.PrintRootPassword(p.ServerNoun(), server.Name, rootPass)
+	}
+
+	if !opts.Wait {
+		return &server, nil
+	}
+
+	color.Green("Linode [%s] was created!", server.Name)
+
+	active, err := cloud.WaitForServer(ctx, p.ServerNoun(), "running", opts.WaitTimeout, func(ctx context.Context) (*cloud.Server, error) {
+		latest, err := client.GetInstance(ctx, instance.ID)
+
+		if err != nil {
+			return nil, err
+		}
+
+		server := instanceToServer(latest)
+		return &server, nil
+	})
+
+	// the server exists even if waiting for it failed, so it is returned along with the error
+	if err != nil {
+		return &server, err
+	}
+
+	return active, nil
+}
+
+// List returns all of the Linode instances on the account, or those with opts.Tag
+func (p *Provider) List(ctx context.Context, opts cloud.ListOptions) ([]cloud.Server, error) {
+	client, err := p.getClient(ctx)
+
+	if err != nil {
+		return nil, err
+	}
+
+	instances, err := client.ListInstances(ctx, nil)
+
+	if err != nil {
+		return nil, err
+	}
+
+	// Instances only reference their type so look up the prices separately
+	prices := map[string]float64{}
+	if types, err := client.ListTypes(ctx, nil); err == nil {
+		for _, linodeType := range types {
+			if linodeType.Price != nil {
+				prices[linodeType.ID] = float64(linodeType.Price.Monthly)
+			}
+		}
+	}
+
+	servers := []cloud.Server{}
+	for _, instance := range instances {
+		server := instanceToServer(&instance)
+		server.PriceMonthly = prices[instance.Type]
+		servers = append(servers, server)
+	}
+
+	return cloud.FilterByTag(servers, opts.Tag), nil
+}
+
+// Destroy will show the user a list of Linode instances and ask the same three
+// confirmations as the DigitalOcean destroy before deleting the selected one
+func (p *Provider) Destroy(ctx context.Context, opts cloud.DestroyOptions) (*cloud.Server, error) {
+	servers, err := p.List(ctx, cloud.ListOptions{})
+
+	if err != nil {
+		return nil, err
+	}
+
+	selectedIndex, err := cloud.SelectServerIndex(servers, opts.ServerSelector, "Select linode to delete")
+
+	if err != nil {
+		return nil, err
+	}
+
+	selectedServer := servers[selectedIndex]
+
+	shouldDestroy, err := cloud.ConfirmDestroy(selectedServer, p.ServerNoun(), opts)
+
+	if err != nil || !shouldDestroy {
+		return nil, err
+	}
+
+	id, err := strconv.Atoi(selectedServer.ID)
+
+	if err != nil {
+		return nil, err
+	}
+
+	client, err := p.getClient(ctx)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if err := client.DeleteInstance(ctx, id); err != nil {
+		fmt.Printf("Something went wrong deleting linode: %s", err)
+		return nil, err
+	}
+
+	return &selectedServer, nil
+}
+
+// Regions returns the regions Linode instances can be created in
+func (p *Provider) Regions(ctx context.Context) ([]utils.SelectItem, error) {
+	client, err := p.getClient(ctx)
+
+	if err != nil {
+		return nil, err
+	}
+
+	regions, err := client.ListRegions(ctx, nil)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return utils.ParseLinodeRegionListResults(regions), nil
+}
+
+// Sizes returns the types Linode instances can be created with
+func (p *Provider) Sizes(ctx context.Context) ([]utils.SelectItem, error) {
+	client, err := p.getClient(ctx)
+
+	if err != nil {
+		return nil, err
+	}
+
+	types, err := client.ListTypes(ctx, nil)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return utils.ParseLinodeTypeListResults(types), nil
+}
+
+// Images returns the images Linode instances can be created from
+func (p *Provider) Images(ctx context.Context) ([]utils.SelectItem, error) {
+	client, err := p.getClient(ctx)
+
+	if err != nil {
+		return nil, err
+	}
+
+	images, err := client.ListImages(ctx, nil)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return utils.ParseLinodeImageListResults(images), nil
+}
+
+// SSHKeys returns the SSH keys on the user's profile
+func (p *Provider) SSHKeys(ctx context.Context) ([]utils.SelectItem, error) {
+	client, err := p.getClient(ctx)
+
+	if err != nil {
+		return nil, err
+	}
+
+	keys, err := client.ListSSHKeys(ctx, nil)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return utils.ParseLinodeSSHKeyListResults(keys), nil
+}
+
+// publicKeys returns the public keys of the profile SSH keys with the given IDs
+// Linode instances are created with the keys themselves rather than their IDs
+func (p *Provider) publicKeys(ctx context.Context, ids []string) ([]string, error) {
+	client, err := p.getClient(ctx)
+
+	if err != nil {
+		return nil, err
+	}
+
+	keys, err := client.ListSSHKeys(ctx, nil)
+
+	if err != nil {
+		return nil, err
+	}
+
+	publicKeys := []string{}
+	for _, id := range ids {
+		found := false
+		for _, key := range keys {
+			if strconv.Itoa(key.ID) == id {
+				publicKeys = append(publicKeys, strings.TrimSpace(key.SSHKey))
+				found = true
+				break
+			}
+		}
+
+		if !found {
+			return nil, fmt.Errorf("ssh key %q is not available", id)
+		}
+	}
+
+	return publicKeys, nil
+}
+
+// getClient returns the API client, asking for the token the first time it is needed
+// the endpoint can be changed with LINODE_URL, which the client reads itself
+func (p *Provider) getClient(ctx context.Context) (*linodego.Client, error) {
+	if p.client != nil {
+		return p.client, nil
+	}
+
+	token, err := credentials.Linode.GetToken(ctx)
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to get token: %w", err)
+	}
+
+	client := linodego.NewClient(nil)
+	client.SetToken(token)
+
+	p.client = &client
+
+	return p.client, nil
+}
+
+// generateRootPass returns a random password that meets Linode's strength requirements
+func generateRootPass() (string, error) {
+	random := make([]byte, 24)
+
+	if _, err := rand.Read(random); err != nil {
+		return "", fmt.Errorf("failed to generate root password: %w", err)
+	}
+
+	// The suffix makes sure every character class is present
+	return base64.RawURLEncoding.EncodeToString(random) + "aZ9!", nil
+}
+
+// instanceToServer converts a Linode instance into the provider independent format
+func instanceToServer(instance *linodego.Instance) cloud.Server {
+	server := cloud.Server{
+		ID:     strconv.Itoa(instance.ID),
+		Name:   instance.Label,
+		Status: string(instance.Status),
+		Region: instance.Region,
+		Size:   instance.Type,
+		Image:  instance.Image,
+		Tags:   instance.Tags,
+	}
+
+	if instance.Created != nil {
+		server.CreatedAt = instance.Created.Format(time.RFC3339)
+	}
+
+	if server.Tags == nil {
+		server.Tags = []string{}
+	}
+
+	for _, ip := range instance.IPv4 {
+		if ip == nil {
+			continue
+		}
+		if ip.IsPrivate() {
+			if server.PrivateIPv4 == "" {
+				server.PrivateIPv4 = ip.String()
+			}
+		} else if server.PublicIPv4 == "" {
+			server.PublicIPv4 = ip.String()
+		}
+	}
+
+	// The API includes the prefix length, e.g. 2600:3c00::f03c:91ff:fe24:3a2f/128
+	server.PublicIPv6 = strings.Split(instance.IPv6, "/")[0]
+
+	return server
+}
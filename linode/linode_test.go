@@ -0,0 +1,311 @@
+package linode
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/Joel-Valentine/cogo/cloud"
+	"github.com/Joel-Valentine/cogo/cloud/cloudtest"
+	"github.com/linode/linodego"
+)
+
+// createRequests are the create instance requests the stand-in API received
+type createRequests struct {
+	mu       sync.Mutex
+	requests []linodego.InstanceCreateOptions
+}
+
+func (c *createRequests) first(t *testing.T) linodego.InstanceCreateOptions {
+	t.Helper()
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if len(c.requests) == 0 {
+		t.Fatal("expected a create request")
+	}
+
+	return c.requests[0]
+}
+
+// list writes a single page of a Linode list response
+func list[T any](w http.ResponseWriter, data []T) {
+	cloudtest.WriteJSON(w, http.StatusOK, map[string]any{"data": data, "page": 1, "pages": 1, "results": len(data)})
+}
+
+// profileSSHKeys are the keys on the profile of the stand-in API
+var profileSSHKeys = []linodego.SSHKey{{ID: 7, Label: "laptop", SSHKey: "ssh-ed25519 AAAA laptop\n"}, {ID: 8, Label: "ci", SSHKey: "ssh-ed25519 BBBB ci"}}
+
+func newTestProvider(t *testing.T, sshKeys []linodego.SSHKey) (*Provider, *cloudtest.Servers[linodego.Instance], *createRequests) {
+	t.Helper()
+
+	instances := &cloudtest.Servers[linodego.Instance]{}
+	requests := &createRequests{}
+	notFound := map[string]any{"errors": []map[string]string{{"reason": "Not found"}}}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /regions", func(w http.ResponseWriter, r *http.Request) {
+		list(w, []linodego.Region{{ID: "eu-west", Label: "London, UK"}})
+	})
+	mux.HandleFunc("GET /linode/types", func(w http.ResponseWriter, r *http.Request) {
+		list(w, []linodego.LinodeType{{ID: "g6-nanode-1", Label: "Nanode 1GB", VCPUs: 1, Memory: 1024, Disk: 25600, Price: &linodego.LinodePrice{Monthly: 5}}})
+	})
+	mux.HandleFunc("GET /images", func(w http.ResponseWriter, r *http.Request) {
+		list(w, []linodego.Image{{ID: "linode/ubuntu24.04", Label: "Ubuntu 24.04 LTS"}})
+	})
+	mux.HandleFunc("GET /profile/sshkeys", func(w http.ResponseWriter, r *http.Request) {
+		list(w, sshKeys)
+	})
+	mux.HandleFunc("GET /linode/instances", func(w http.ResponseWriter, r *http.Request) {
+		list(w, instances.List())
+	})
+	mux.HandleFunc("POST /linode/instances", func(w http.ResponseWriter, r *http.Request) {
+		var request linodego.InstanceCreateOptions
+		if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		requests.mu.Lock()
+		requests.requests = append(requests.requests, request)
+		requests.mu.Unlock()
+
+		private := net.ParseIP("192.168.130.5")
+		public := net.ParseIP("203.0.113.10")
+
+		instance := instances.Add(func(id int) linodego.Instance {
+			return linodego.Instance{
+				ID:     id,
+				Label:  request.Label,
+				Status: linodego.InstanceRunning,
+				Region: request.Region,
+				Type:   request.Type,
+				Image:  request.Image,
+				Tags:   request.Tags,
+				IPv4:   []*net.IP{&private, &public},
+				IPv6:   "2001:db8::1/128",
+			}
+		})
+
+		cloudtest.WriteJSON(w, http.StatusOK, instance)
+	})
+	mux.HandleFunc("GET /linode/instances/{id}", func(w http.ResponseWriter, r *http.Request) {
+		instance, ok := instances.Get(r.PathValue("id"))
+		if !ok {
+			cloudtest.WriteJSON(w, http.StatusNotFound, notFound)
+			return
+		}
+		cloudtest.WriteJSON(w, http.StatusOK, instance)
+	})
+	mux.HandleFunc("DELETE /linode/instances/{id}", func(w http.ResponseWriter, r *http.Request) {
+		if !instances.Delete(r.PathValue("id")) {
+			cloudtest.WriteJSON(w, http.StatusNotFound, notFound)
+			return
+		}
+		cloudtest.WriteJSON(w, http.StatusOK, map[string]any{})
+	})
+
+	// the client adds the API version to the endpoint
+	server := cloudtest.NewServer(t, http.StripPrefix("/"+linodego.APIVersion, mux))
+
+	client := linodego.NewClient(nil)
+	client.SetToken("test-token")
+	client.SetBaseURL(server.URL)
+
+	return &Provider{client: &client}, instances, requests
+}
+
+func TestProvider_CreateListDestroy(t *testing.T) {
+	ctx := context.Background()
+	provider, instances, requests := newTestProvider(t, profileSSHKeys)
+
+	created, err := provider.Create(ctx, cloud.CreateOptions{
+		Name:    "web-1",
+		Image:   "linode/ubuntu24.04",
+		Size:    "g6-nanode-1",
+		Region:  "eu-west",
+		SSHKeys: []string{"7", "8"},
+		Tags:    []string{"web"},
+		Yes:     true,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error creating linode: %v", err)
+	}
+
+	if created.Name != "web-1" || created.Region != "eu-west" {
+		t.Errorf("unexpected created linode %+v", created)
+	}
+
+	request := requests.first(t)
+	if len(request.AuthorizedKeys) != 2 || request.AuthorizedKeys[0] != "ssh-ed25519 AAAA laptop" || request.AuthorizedKeys[1] != "ssh-ed25519 BBBB ci" {
+		t.Errorf("expected both public keys to be sent, got %v", request.AuthorizedKeys)
+	}
+
+	if request.RootPass == "" {
+		t.Error("expected a root password to be sent")
+	}
+
+	servers, err := provider.List(ctx, cloud.ListOptions{})
+	if err != nil {
+		t.Fatalf("unexpected error listing linodes: %v", err)
+	}
+
+	if len(servers) != 1 {
+		t.Fatalf("expected 1 linode, got %d", len(servers))
+	}
+
+	server := servers[0]
+	if server.PublicIPv4 != "203.0.113.10" || server.PrivateIPv4 != "192.168.130.5" || server.PublicIPv6 != "2001:db8::1" || server.PriceMonthly != 5 {
+		t.Errorf("unexpected linode %+v", server)
+	}
+
+	if len(server.Tags) != 1 || server.Tags[0] != "web" {
+		t.Errorf("expected tags [web], got %v", server.Tags)
+	}
+
+	servers, err = provider.List(ctx, cloud.ListOptions{Tag: "db"})
+	if err != nil {
+		t.Fatalf("unexpected error listing linodes by tag: %v", err)
+	}
+
+	if len(servers) != 0 {
+		t.Errorf("expected no linodes tagged db, got %d", len(servers))
+	}
+
+	destroyed, err := provider.Destroy(ctx, cloud.DestroyOptions{
+		ServerSelector: cloud.ServerSelector{Name: "web-1"},
+		ConfirmName:    "web-1",
+		Yes:            true,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error destroying linode: %v", err)
+	}
+
+	if destroyed.ID != created.ID {
+		t.Errorf("expected linode %s to be destroyed, got %s", created.ID, destroyed.ID)
+	}
+
+	if instances.Len() != 0 {
+		t.Errorf("expected no linodes left, got %d", instances.Len())
+	}
+}
+
+func TestProvider_CreateInvalidRegion(t *testing.T) {
+	provider, instances, _ := newTestProvider(t, profileSSHKeys)
+
+	_, err := provider.Create(context.Background(), cloud.CreateOptions{
+		Name:    "web-1",
+		Image:   "linode/ubuntu24.04",
+		Size:    "g6-nanode-1",
+		Region:  "mars-1",
+		SSHKeys: []string{"7"},
+		Yes:     true,
+	})
+	if err == nil {
+		t.Error("expected error for unknown region, got nil")
+	}
+
+	if instances.Len() != 0 {
+		t.Errorf("expected no linode to be created, got %d", instances.Len())
+	}
+}
+
+func TestProvider_CreateWithoutSSHKey(t *testing.T) {
+	tests := []struct {
+		name             string
+		showRootPassword bool
+		expectedErr      error
+		expectedCount    int
+	}{
+		{name: "refused", expectedErr: cloud.ErrNoSSHKey, expectedCount: 0},
+		{name: "root password shown", showRootPassword: true, expectedCount: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// without any keys on the profile none are chosen
+			provider, instances, _ := newTestProvider(t, []linodego.SSHKey{})
+
+			_, err := provider.Create(context.Background(), cloud.CreateOptions{
+				Name:             "web-1",
+				Image:            "linode/ubuntu24.04",
+				Size:             "g6-nanode-1",
+				Region:           "eu-west",
+				ShowRootPassword: tt.showRootPassword,
+				Yes:              true,
+			})
+			if !errors.Is(err, tt.expectedErr) {
+				t.Errorf("expected error %v, got %v", tt.expectedErr, err)
+			}
+
+			if instances.Len() != tt.expectedCount {
+				t.Errorf("expected %d linodes, got %d", tt.expectedCount, instances.Len())
+			}
+		})
+	}
+}
+
+func TestGenerateRootPass(t *testing.T) {
+	first, err := generateRootPass()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	second, err := generateRootPass()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if first == second {
+		t.Error("expected different passwords")
+	}
+
+	if len(first) < 32 || !strings.ContainsAny(first, "!") {
+		t.Errorf("password %q is not strong enough", first)
+	}
+}
+
+func TestProvider_CreateWithUserData(t *testing.T) {
+	provider, _, requests := newTestProvider(t, profileSSHKeys)
+
+	userDataFile := filepath.Join(t.TempDir(), "web.yaml")
+	if err := os.WriteFile(userDataFile, []byte("#cloud-config\nhostname: {{ .Name }}\n"), 0600); err != nil {
+		t.Fatalf("failed to write user data: %v", err)
+	}
+
+	_, err := provider.Create(context.Background(), cloud.CreateOptions{
+		Name:         "web-1",
+		Image:        "linode/ubuntu24.04",
+		Size:         "g6-nanode-1",
+		Region:       "eu-west",
+		SSHKeys:      []string{"7"},
+		UserDataFile: userDataFile,
+		Yes:          true,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error creating linode: %v", err)
+	}
+
+	metadata := requests.first(t).Metadata
+	if metadata == nil {
+		t.Fatal("expected metadata to be sent")
+	}
+
+	userData, err := base64.StdEncoding.DecodeString(metadata.UserData)
+	if err != nil {
+		t.Fatalf("user data was not base64: %v", err)
+	}
+
+	if string(userData) != "#cloud-config\nhostname: web-1\n" {
+		t.Errorf("unexpected user data %q", userData)
+	}
+}
@@ -0,0 +1,67 @@
+package mock
+
+import "github.com/digitalocean/godo"
+
+// The fake catalog uses the DigitalOcean types so its prompts look exactly like the DigitalOcean ones
+
+var regions = []godo.Region{
+	{Slug: "lon1", Name: "London 1", Available: true},
+	{Slug: "ams3", Name: "Amsterdam 3", Available: true},
+	{Slug: "fra1", Name: "Frankfurt 1", Available: true},
+	{Slug: "nyc1", Name: "New York 1", Available: true},
+	{Slug: "sfo3", Name: "San Francisco 3", Available: true},
+	{Slug: "sgp1", Name: "Singapore 1", Available: true},
+}
+
+var sizes = []godo.Size{
+	{Slug: "s-1vcpu-512mb-10gb", Vcpus: 1, Memory: 512, Disk: 10, PriceMonthly: 4, PriceHourly: 0.00595, Available: true},
+	{Slug: "s-1vcpu-1gb", Vcpus: 1, Memory: 1024, Disk: 25, PriceMonthly: 6, PriceHourly: 0.00893, Available: true},
+	{Slug: "s-1vcpu-2gb", Vcpus: 1, Memory: 2048, Disk: 50, PriceMonthly: 12, PriceHourly: 0.01786, Available: true},
+	{Slug: "s-2vcpu-2gb", Vcpus: 2, Memory: 2048, Disk: 60, PriceMonthly: 18, PriceHourly: 0.02679, Available: true},
+	{Slug: "s-2vcpu-4gb", Vcpus: 2, Memory: 4096, Disk: 80, PriceMonthly: 24, PriceHourly: 0.03571, Available: true},
+	{Slug: "s-4vcpu-8gb", Vcpus: 4, Memory: 8192, Disk: 160, PriceMonthly: 48, PriceHourly: 0.07143, Available: true},
+}
+
+var images = []godo.Image{
+	{ID: 1001, Slug: "ubuntu-24-04-x64", Name: "24.04 (LTS) x64", Distribution: "Ubuntu", Public: true},
+	{ID: 1002, Slug: "ubuntu-22-04-x64", Name: "22.04 (LTS) x64", Distribution: "Ubuntu", Public: true},
+	{ID: 1003, Slug: "debian-12-x64", Name: "12 x64", Distribution: "Debian", Public: true},
+	{ID: 1004, Slug: "fedora-40-x64", Name: "40 x64", Distribution: "Fedora", Public: true},
+	{ID: 1005, Slug: "docker-20-04", Name: "Docker on Ubuntu", Distribution: "Ubuntu", Public: true},
+}
+
+var sshKeys = []godo.Key{
+	{ID: 4001, Name: "demo-laptop", Fingerprint: "3b:16:bf:e4:8b:00:8b:b8:59:8c:a9:d3:f0:19:45:fa"},
+	{ID: 4002, Name: "demo-ci", Fingerprint: "9a:2c:41:8e:0f:7d:11:c3:55:e2:6b:9d:04:ab:7c:e1"},
+}
+
+var vpcs = []godo.VPC{
+	{ID: "5a4981aa-9653-4bd1-bef5-d6bff52042e4", Name: "default-lon1", IPRange: "10.106.0.0/20", RegionSlug: "lon1", Default: true},
+	{ID: "8e7c10f4-27d6-4e7a-9c1e-3f9b2d61a0c7", Name: "demo-backend", IPRange: "10.200.0.0/24", RegionSlug: "lon1"},
+	{ID: "0d3e8f61-52b7-4c38-a1d4-7e6f90b2c815", Name: "default-ams3", IPRange: "10.110.0.0/20", RegionSlug: "ams3", Default: true},
+	{ID: "c2b5a7e9-1f04-4d6a-8b3e-95d0f7a41c26", Name: "default-fra1", IPRange: "10.114.0.0/20", RegionSlug: "fra1", Default: true},
+	{ID: "71f9d3c0-8a2e-4b5f-b6c7-2d14e0a9f358", Name: "default-nyc1", IPRange: "10.116.0.0/20", RegionSlug: "nyc1", Default: true},
+	{ID: "e4a06b2d-3c91-4f87-9d5a-b8c1f7e2036a", Name: "default-sfo3", IPRange: "10.124.0.0/20", RegionSlug: "sfo3", Default: true},
+	{ID: "3f8d2c5b-b7a0-4e19-86f4-0c9e1a5d7b42", Name: "default-sgp1", IPRange: "10.104.0.0/20", RegionSlug: "sgp1", Default: true},
+}
+
+// vpcsInRegion returns the catalog VPCs in the region
+func vpcsInRegion(region string) []*godo.VPC {
+	list := []*godo.VPC{}
+	for index := range vpcs {
+		if vpcs[index].RegionSlug == region {
+			list = append(list, &vpcs[index])
+		}
+	}
+	return list
+}
+
+// sizeBySlug returns the catalog size with the given slug
+func sizeBySlug(slug string) (godo.Size, bool) {
+	for _, size := range sizes {
+		if size.Slug == slug {
+			return size, true
+		}
+	}
+	return godo.Size{}, false
+}
@@ -0,0 +1,466 @@
+// Package mock is an offline cloud provider for demos, training and tests.
+// It serves a fake catalog and keeps its droplets in a local JSON state file,
+// so the whole create, list and destroy wizard works without a token or network
+package mock
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/Joel-Valentine/cogo/cloud"
+	"github.com/Joel-Valentine/cogo/config"
+	"github.com/Joel-Valentine/cogo/utils"
+	"github.com/digitalocean/godo"
+)
+
+// StateEnvVar can be set to keep the mock droplets in another file
+const StateEnvVar = "COGO_MOCK_STATE"
+
+// stateFileName is the name of the state file in the cogo config directory
+const stateFileName = "mock-state.json"
+
+// firstID is the ID given to the first mock droplet
+const firstID = 100001
+
+// state is what is kept in the state file
+type state struct {
+	NextID   int            `json:"next_id"`
+	Droplets []cloud.Server `json:"droplets"`
+
+	// Features are the optional features turned on for each droplet, by the droplet's ID
+	Features map[string][]string `json:"features,omitempty"`
+}
+
+// Provider is a cloud provider that never leaves the machine
+type Provider struct {
+	statePath string
+}
+
+var _ cloud.Provider = &Provider{}
+var _ cloud.UserDataProvider = &Provider{}
+var _ cloud.TagProvider = &Provider{}
+var _ cloud.VPCProvider = &Provider{}
+var _ cloud.FeatureProvider = &Provider{}
+var _ cloud.PriceProvider = &Provider{}
+var _ cloud.PowerProvider = &Provider{}
+var _ cloud.ResizeProvider = &Provider{}
+var _ cloud.RebuildProvider = &Provider{}
+
+// NewProvider creates the mock provider
+func NewProvider() *Provider {
+	return &Provider{}
+}
+
+// Key returns the identifier used to select the mock provider
+func (p *Provider) Key() string {
+	return "mock"
+}
+
+// Name returns the provider name
+func (p *Provider) Name() string {
+	return "Mock (offline demo)"
+}
+
+// ServerNoun returns what the mock provider calls its servers
+func (p *Provider) ServerNoun() string {
+	return "droplet"
+}
+
+// HasCredentials returns true, the mock provider needs no credentials so it is always offered
+func (p *Provider) HasCredentials(ctx context.Context) bool {
+	return true
+}
+
+// SupportsTags returns true, mock droplets keep their tags in the state file
+func (p *Provider) SupportsTags() bool {
+	return true
+}
+
+// UserDataLimit returns the same limit as DigitalOcean so user data can be tried out
+// it is validated but not kept
+func (p *Provider) UserDataLimit() int {
+	return 64 * 1024
+}
+
+// Create asks the same create wizard questions as DigitalOcean then saves the droplet to the state file
+// Mock droplets are active with an IP straight away
+func (p *Provider) Create(ctx context.Context, opts cloud.CreateOptions) (*cloud.Server, error) {
+	answers, err := cloud.AskCreateQuestions(ctx, p, opts)
+
+	if err != nil || answers == nil {
+		return nil, err
+	}
+
+	current, err := p.load()
+
+	if err != nil {
+		return nil, err
+	}
+
+	id := current.NextID
+	current.NextID++
+
+	size, _ := sizeBySlug(answers.Size)
+
+	// Like DigitalOcean, droplets go in the region's default VPC unless another was chosen
+	vpc := answers.VPC
+	if vpc == "" {
+		for _, regionVPC := range vpcsInRegion(answers.Region) {
+			if regionVPC.Default {
+				vpc = regionVPC.ID
+			}
+		}
+	}
+
+	server := cloud.Server{
+		ID:           strconv.Itoa(id),
+		Name:         answers.Name,
+		Status:       "active",
+		Region:       answers.Region,
+		Size:         answers.Size,
+		Image:        answers.Image,
+		PublicIPv4:   fmt.Sprintf("203.0.113.%d", id%254+1),
+		PrivateIPv4:  fmt.Sprintf("10.110.0.%d", id%254+1),
+		Tags:         answers.Tags,
+		VPC:          vpc,
+		CreatedAt:    time.Now().UTC().Format(time.RFC3339),
+		PriceMonthly: price(size, answers.Features),
+	}
+
+	if slices.Contains(answers.Features, utils.FeatureIPv6) {
+		server.PublicIPv6 = fmt.Sprintf("2001:db8::%x", id)
+	}
+
+	if len(answers.Features) > 0 {
+		if current.Features == nil {
+			current.Features = map[string][]string{}
+		}
+		current.Features[server.ID] = answers.Features
+	}
+
+	current.Droplets = append(current.Droplets, server)
+
+	if err := p.save(current); err != nil {
+		return nil, err
+	}
+
+	return &server, nil
+}
+
+// List returns the droplets in the state file, or those with opts.Tag
+func (p *Provider) List(ctx context.Context, opts cloud.ListOptions) ([]cloud.Server, error) {
+	current, err := p.load()
+
+	if err != nil {
+		return nil, err
+	}
+
+	return cloud.FilterByTag(current.Droplets, opts.Tag), nil
+}
+
+// Destroy will show the user a list of droplets and ask the same three
+// confirmations as the DigitalOcean destroy before removing the selected one
+func (p *Provider) Destroy(ctx context.Context, opts cloud.DestroyOptions) (*cloud.Server, error) {
+	current, err := p.load()
+
+	if err != nil {
+		return nil, err
+	}
+
+	selectedIndex, err := cloud.SelectServerIndex(current.Droplets, opts.ServerSelector, "Select droplet to delete")
+
+	if err != nil {
+		return nil, err
+	}
+
+	selectedServer := current.Droplets[selectedIndex]
+
+	shouldDestroy, err := cloud.ConfirmDestroy(selectedServer, p.ServerNoun(), opts)
+
+	if err != nil || !shouldDestroy {
+		return nil, err
+	}
+
+	current.Droplets = append(current.Droplets[:selectedIndex], current.Droplets[selectedIndex+1:]...)
+	delete(current.Features, selectedServer.ID)
+
+	if err := p.save(current); err != nil {
+		return nil, err
+	}
+
+	return &selectedServer, nil
+}
+
+// Power sets the status of a mock droplet as DigitalOcean would once the power action has finished
+// mock droplets power off straight away, so a shutdown never needs to fall back to a power off
+func (p *Provider) Power(ctx context.Context, opts cloud.PowerOptions) (*cloud.Server, error) {
+	if err := cloud.ValidatePowerAction(opts.Action); err != nil {
+		return nil, err
+	}
+
+	current, err := p.load()
+
+	if err != nil {
+		return nil, err
+	}
+
+	selectedIndex, err := cloud.SelectServerIndex(current.Droplets, opts.ServerSelector, "Select droplet to "+opts.Action)
+
+	if err != nil {
+		return nil, err
+	}
+
+	switch opts.Action {
+	case cloud.PowerOff, cloud.PowerShutdown:
+		current.Droplets[selectedIndex].Status = "off"
+	default:
+		current.Droplets[selectedIndex].Status = "active"
+	}
+
+	if err := p.save(current); err != nil {
+		return nil, err
+	}
+
+	return &current.Droplets[selectedIndex], nil
+}
+
+// Resize asks for or validates the new size of a mock droplet from the sizes in its region, then saves it
+// Mock droplets don't need powering off and their disk always grows with the size
+func (p *Provider) Resize(ctx context.Context, opts cloud.ResizeOptions) (*cloud.Server, error) {
+	current, err := p.load()
+
+	if err != nil {
+		return nil, err
+	}
+
+	selectedIndex, err := cloud.SelectServerIndex(current.Droplets, opts.ServerSelector, "Select droplet to resize")
+
+	if err != nil {
+		return nil, err
+	}
+
+	server := &current.Droplets[selectedIndex]
+	currentSize, _ := sizeBySlug(server.Size)
+	// every mock region offers every size
+	resizeSizes := utils.ResizeSizes(sizes, server.Size, "", currentSize.Disk)
+
+	resizeList := utils.ParseResizeSizeList(resizeSizes, currentSize, currentSize.Disk)
+
+	newSize := opts.Size
+
+	if newSize == "" {
+		newSize, err = utils.AskAndAnswerCustomSelect("Resize To", resizeList)
+
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if _, ok := utils.FindSelectItem(resizeList, newSize); !ok {
+		return nil, fmt.Errorf("droplet %s can't be resized to %q", server.Name, newSize)
+	}
+
+	size, _ := sizeBySlug(newSize)
+
+	if !opts.Yes {
+		shouldResize, err := utils.AskYesNo(fmt.Sprintf("Resize %s from %s to %s? (y/n)", server.Name, server.Size, newSize))
+
+		if err != nil || !shouldResize {
+			return nil, err
+		}
+	}
+
+	server.Size = newSize
+	server.PriceMonthly = price(size, current.Features[server.ID])
+
+	if err := p.save(current); err != nil {
+		return nil, err
+	}
+
+	return server, nil
+}
+
+// Rebuild asks for or validates the image to rebuild a mock droplet from, confirms like Destroy
+// then saves the droplet with its new image
+func (p *Provider) Rebuild(ctx context.Context, opts cloud.RebuildOptions) (*cloud.Server, error) {
+	current, err := p.load()
+
+	if err != nil {
+		return nil, err
+	}
+
+	selectedIndex, err := cloud.SelectServerIndex(current.Droplets, opts.ServerSelector, "Select droplet to rebuild")
+
+	if err != nil {
+		return nil, err
+	}
+
+	imageList := utils.ParseImageListResults(images)
+
+	image := opts.Image
+
+	if image == "" {
+		image, err = utils.AskAndAnswerCustomSelect("Image Select", imageList)
+
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if _, ok := utils.FindSelectItem(imageList, image); !ok {
+		return nil, fmt.Errorf("image %q is not available", image)
+	}
+
+	shouldRebuild, err := cloud.ConfirmRebuild(current.Droplets[selectedIndex], p.ServerNoun(), image, opts)
+
+	if err != nil || !shouldRebuild {
+		return nil, err
+	}
+
+	current.Droplets[selectedIndex].Image = image
+
+	if err := p.save(current); err != nil {
+		return nil, err
+	}
+
+	return &current.Droplets[selectedIndex], nil
+}
+
+// Regions returns the fake regions
+func (p *Provider) Regions(ctx context.Context) ([]utils.SelectItem, error) {
+	return utils.ParseRegionListresults(regions), nil
+}
+
+// Sizes returns the fake sizes
+func (p *Provider) Sizes(ctx context.Context) ([]utils.SelectItem, error) {
+	return utils.ParseSizeListResults(sizes), nil
+}
+
+// Images returns the fake images
+func (p *Provider) Images(ctx context.Context) ([]utils.SelectItem, error) {
+	return utils.ParseImageListResults(images), nil
+}
+
+// VPCs returns the fake VPCs in the region
+func (p *Provider) VPCs(ctx context.Context, region string) ([]utils.SelectItem, error) {
+	return utils.ParseVPCListResults(vpcsInRegion(region)), nil
+}
+
+// Features returns the same optional features as DigitalOcean, backups are added to the droplet's price
+// and IPv6 gives it an IPv6 address
+func (p *Provider) Features(ctx context.Context, slug string) ([]utils.SelectItem, error) {
+	size, ok := sizeBySlug(slug)
+
+	if !ok {
+		return nil, fmt.Errorf("size %q is not available", slug)
+	}
+
+	return utils.ParseDropletFeatureList(size), nil
+}
+
+// SizePrice returns the hourly and monthly price of the size from the fake catalog
+func (p *Provider) SizePrice(ctx context.Context, slug string) (float64, float64, error) {
+	size, ok := sizeBySlug(slug)
+
+	if !ok {
+		return 0, 0, fmt.Errorf("size %q is not available", slug)
+	}
+
+	return size.PriceHourly, size.PriceMonthly, nil
+}
+
+// price returns the monthly price of a droplet of the size with the features, backups cost a share of the size's price
+func price(size godo.Size, features []string) float64 {
+	if slices.Contains(features, utils.FeatureBackups) {
+		return size.PriceMonthly + size.PriceMonthly*utils.BackupPriceRate
+	}
+
+	return size.PriceMonthly
+}
+
+// Surcharges returns what backups add to the monthly cost of the droplets in the summary, priced like Create does
+func (p *Provider) Surcharges(summary cloud.CreateSummary, monthly float64) []cloud.Surcharge {
+	if !slices.Contains(summary.Features, utils.FeatureBackups) {
+		return []cloud.Surcharge{}
+	}
+
+	count := float64(max(len(summary.Names), 1))
+
+	return []cloud.Surcharge{{Name: "backups", PriceMonthly: monthly * utils.BackupPriceRate * count}}
+}
+
+// SSHKeys returns the fake SSH keys
+func (p *Provider) SSHKeys(ctx context.Context) ([]utils.SelectItem, error) {
+	return utils.ParseSSHKeyListResults(sshKeys), nil
+}
+
+// path returns where the state file is kept
+// $COGO_MOCK_STATE if it is set, otherwise mock-state.json in the cogo config directory
+func (p *Provider) path() (string, error) {
+	if p.statePath != "" {
+		return p.statePath, nil
+	}
+
+	if path := os.Getenv(StateEnvVar); path != "" {
+		return path, nil
+	}
+
+	dir, err := config.Dir()
+
+	if err != nil {
+		return "", fmt.Errorf("failed to find the cogo config directory: %w", err)
+	}
+
+	return filepath.Join(dir, stateFileName), nil
+}
+
+// load reads the state file, a missing file is an empty state
+func (p *Provider) load() (*state, error) {
+	path, err := p.path()
+
+	if err != nil {
+		return nil, err
+	}
+
+	current := &state{NextID: firstID, Droplets: []cloud.Server{}}
+
+	data, err := os.ReadFile(path)
+
+	if errors.Is(err, os.ErrNotExist) {
+		return current, nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	if err := json.Unmarshal(data, current); err != nil {
+		return nil, fmt.Errorf("failed to read mock state %s: %w", path, err)
+	}
+
+	return current, nil
+}
+
+// save writes the state file
+func (p *Provider) save(current *state) error {
+	path, err := p.path()
+
+	if err != nil {
+		return err
+	}
+
+	data, err := json.MarshalIndent(current, "", "  ")
+
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(path, data, 0600)
+}
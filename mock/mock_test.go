@@ -0,0 +1,456 @@
+package mock
+
+import (
+	"context"
+	"math"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/Joel-Valentine/cogo/cloud"
+	"github.com/Joel-Valentine/cogo/config"
+	"github.com/Joel-Valentine/cogo/history"
+)
+
+func newTestProvider(t *testing.T) *Provider {
+	t.Helper()
+
+	// keep the wizard history out of the user's config directory
+	t.Setenv(config.DirEnvVar, t.TempDir())
+
+	return &Provider{statePath: filepath.Join(t.TempDir(), "state.json")}
+}
+
+func TestProvider_CreateListDestroy(t *testing.T) {
+	ctx := context.Background()
+	provider := newTestProvider(t)
+
+	if !provider.HasCredentials(ctx) {
+		t.Error("expected the mock provider to always have credentials")
+	}
+
+	created, err := provider.Create(ctx, cloud.CreateOptions{
+		Name:    "web-1",
+		Image:   "ubuntu-24-04-x64",
+		Size:    "s-1vcpu-1gb",
+		Region:  "lon1",
+		SSHKeys: []string{"4001"},
+		Yes:     true,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error creating droplet: %v", err)
+	}
+
+	if created.Status != "active" || created.PublicIPv4 == "" || created.PriceMonthly != 6 {
+		t.Errorf("unexpected created droplet %+v", created)
+	}
+
+	// A new provider reads the same state file, as a later cogo run would
+	reloaded := &Provider{statePath: provider.statePath}
+
+	servers, err := reloaded.List(ctx, cloud.ListOptions{})
+	if err != nil {
+		t.Fatalf("unexpected error listing droplets: %v", err)
+	}
+
+	if len(servers) != 1 || servers[0].ID != created.ID || servers[0].Name != "web-1" {
+		t.Errorf("unexpected droplets %+v", servers)
+	}
+
+	destroyed, err := reloaded.Destroy(ctx, cloud.DestroyOptions{
+		ServerSelector: cloud.ServerSelector{ID: created.ID},
+		ConfirmName:    "web-1",
+		Yes:            true,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error destroying droplet: %v", err)
+	}
+
+	if destroyed.ID != created.ID {
+		t.Errorf("expected droplet %s to be destroyed, got %s", created.ID, destroyed.ID)
+	}
+
+	servers, err = provider.List(ctx, cloud.ListOptions{})
+	if err != nil {
+		t.Fatalf("unexpected error listing droplets: %v", err)
+	}
+
+	if len(servers) != 0 {
+		t.Errorf("expected no droplets left, got %d", len(servers))
+	}
+}
+
+func TestProvider_CreateInVPC(t *testing.T) {
+	ctx := context.Background()
+	provider := newTestProvider(t)
+
+	tests := []struct {
+		name        string
+		region      string
+		vpc         string
+		expected    string
+		expectError bool
+	}{
+		{
+			name:     "region default",
+			region:   "lon1",
+			expected: "5a4981aa-9653-4bd1-bef5-d6bff52042e4",
+		},
+		{
+			name:     "chosen vpc",
+			region:   "lon1",
+			vpc:      "8e7c10f4-27d6-4e7a-9c1e-3f9b2d61a0c7",
+			expected: "8e7c10f4-27d6-4e7a-9c1e-3f9b2d61a0c7",
+		},
+		{
+			name:        "vpc in another region",
+			region:      "ams3",
+			vpc:         "8e7c10f4-27d6-4e7a-9c1e-3f9b2d61a0c7",
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			created, err := provider.Create(ctx, cloud.CreateOptions{
+				Name:    "web-1",
+				Image:   "ubuntu-24-04-x64",
+				Size:    "s-1vcpu-1gb",
+				Region:  tt.region,
+				VPC:     tt.vpc,
+				SSHKeys: []string{"4001"},
+				Yes:     true,
+			})
+
+			if tt.expectError {
+				if err == nil {
+					t.Error("expected error, got nil")
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("unexpected error creating droplet: %v", err)
+			}
+			if created.VPC != tt.expected {
+				t.Errorf("expected vpc %s, got %s", tt.expected, created.VPC)
+			}
+		})
+	}
+}
+
+func TestProvider_CreateWithFeatures(t *testing.T) {
+	ctx := context.Background()
+	provider := newTestProvider(t)
+
+	created, err := provider.Create(ctx, cloud.CreateOptions{
+		Name:     "db-1",
+		Image:    "ubuntu-24-04-x64",
+		Size:     "s-1vcpu-1gb",
+		Region:   "lon1",
+		SSHKeys:  []string{"4001"},
+		Features: []string{"backups", "ipv6"},
+		Yes:      true,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error creating droplet: %v", err)
+	}
+
+	// backups add 20% to the $6 droplet
+	if created.PriceMonthly != 7.2 || created.PublicIPv6 == "" {
+		t.Errorf("unexpected created droplet %+v", created)
+	}
+
+	_, err = provider.Create(ctx, cloud.CreateOptions{
+		Name:     "db-2",
+		Image:    "ubuntu-24-04-x64",
+		Size:     "s-1vcpu-1gb",
+		Region:   "lon1",
+		SSHKeys:  []string{"4001"},
+		Features: []string{"snapshots"},
+		Yes:      true,
+	})
+	if err == nil {
+		t.Error("expected error for unknown feature, got nil")
+	}
+}
+
+func TestProvider_ListByTag(t *testing.T) {
+	ctx := context.Background()
+	provider := newTestProvider(t)
+
+	for _, tags := range [][]string{{"web", "prod"}, {"db"}, {"web"}} {
+		_, err := provider.Create(ctx, cloud.CreateOptions{
+			Name:    "droplet",
+			Image:   "ubuntu-24-04-x64",
+			Size:    "s-1vcpu-1gb",
+			Region:  "lon1",
+			SSHKeys: []string{"4001"},
+			Tags:    tags,
+			Yes:     true,
+		})
+		if err != nil {
+			t.Fatalf("unexpected error creating droplet: %v", err)
+		}
+	}
+
+	tests := []struct {
+		tag      string
+		expected int
+	}{
+		{tag: "", expected: 3},
+		{tag: "web", expected: 2},
+		{tag: "prod", expected: 1},
+		{tag: "staging", expected: 0},
+	}
+
+	for _, tt := range tests {
+		servers, err := provider.List(ctx, cloud.ListOptions{Tag: tt.tag})
+		if err != nil {
+			t.Fatalf("unexpected error listing droplets: %v", err)
+		}
+
+		if len(servers) != tt.expected {
+			t.Errorf("expected %d droplets tagged %q, got %d", tt.expected, tt.tag, len(servers))
+		}
+	}
+}
+
+func TestProvider_CreateUniqueIDs(t *testing.T) {
+	ctx := context.Background()
+	provider := newTestProvider(t)
+
+	opts := cloud.CreateOptions{Image: "debian-12-x64", Size: "s-1vcpu-1gb", Region: "ams3", SSHKeys: []string{"4002"}, Yes: true}
+
+	ids := map[string]bool{}
+	for _, name := range []string{"web-1", "web-2", "web-3"} {
+		opts.Name = name
+
+		created, err := provider.Create(ctx, opts)
+		if err != nil {
+			t.Fatalf("unexpected error creating droplet: %v", err)
+		}
+
+		if ids[created.ID] {
+			t.Errorf("ID %s was used twice", created.ID)
+		}
+		ids[created.ID] = true
+	}
+}
+
+func TestProvider_CreateInvalidSize(t *testing.T) {
+	provider := newTestProvider(t)
+
+	_, err := provider.Create(context.Background(), cloud.CreateOptions{
+		Name:    "web-1",
+		Image:   "ubuntu-24-04-x64",
+		Size:    "s-99vcpu-1tb",
+		Region:  "lon1",
+		SSHKeys: []string{"4001"},
+		Yes:     true,
+	})
+	if err == nil {
+		t.Error("expected error for unknown size, got nil")
+	}
+
+	if _, err := os.Stat(provider.statePath); !os.IsNotExist(err) {
+		t.Errorf("expected no state file to be written, got %v", err)
+	}
+}
+
+func TestProvider_CorruptState(t *testing.T) {
+	provider := newTestProvider(t)
+
+	if err := os.WriteFile(provider.statePath, []byte("not json"), 0600); err != nil {
+		t.Fatalf("failed to write state: %v", err)
+	}
+
+	if _, err := provider.List(context.Background(), cloud.ListOptions{}); err == nil {
+		t.Error("expected error for corrupt state, got nil")
+	}
+}
+
+func TestProvider_CreateRemembersAnswers(t *testing.T) {
+	tests := []struct {
+		name      string
+		noHistory bool
+		expected  string
+	}{
+		{name: "remembered", expected: "lon1"},
+		{name: "no history", noHistory: true, expected: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			provider := newTestProvider(t)
+
+			_, err := provider.Create(context.Background(), cloud.CreateOptions{
+				Name:      "web-1",
+				Image:     "ubuntu-24-04-x64",
+				Size:      "s-1vcpu-1gb",
+				Region:    "lon1",
+				SSHKeys:   []string{"4001"},
+				Yes:       true,
+				NoHistory: tt.noHistory,
+			})
+			if err != nil {
+				t.Fatalf("unexpected error creating droplet: %v", err)
+			}
+
+			recent, err := history.Load()
+			if err != nil {
+				t.Fatalf("unexpected error loading history: %v", err)
+			}
+
+			if got := strings.Join(recent.Recent("mock", history.Region), ","); got != tt.expected {
+				t.Errorf("expected recent regions %q, got %q", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestProvider_CreateDryRun(t *testing.T) {
+	provider := newTestProvider(t)
+
+	created, err := provider.Create(context.Background(), cloud.CreateOptions{
+		Name:    "web-1",
+		Image:   "ubuntu-24-04-x64",
+		Size:    "s-1vcpu-1gb",
+		Region:  "lon1",
+		SSHKeys: []string{"4001"},
+		Yes:     true,
+		DryRun:  true,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if created != nil {
+		t.Errorf("expected nothing to be created on a dry run, got %+v", created)
+	}
+
+	if _, err := os.Stat(provider.statePath); !os.IsNotExist(err) {
+		t.Errorf("expected no state file to be written, got %v", err)
+	}
+
+	price, _, err := provider.SizePrice(context.Background(), "s-1vcpu-1gb")
+	if err != nil || price != 0.00893 {
+		t.Errorf("expected the catalog's hourly price, got %v, %v", price, err)
+	}
+
+	surcharges := provider.Surcharges(cloud.CreateSummary{Names: []string{"web-1", "web-2"}, Features: []string{"backups"}}, 6)
+	if len(surcharges) != 1 || surcharges[0].Name != "backups" || math.Abs(surcharges[0].PriceMonthly-2.4) > 0.001 {
+		t.Errorf("expected backups to add 2.40/mo for two droplets, got %v", surcharges)
+	}
+}
+
+func TestProvider_Power(t *testing.T) {
+	ctx := context.Background()
+	provider := newTestProvider(t)
+
+	created, err := provider.Create(ctx, cloud.CreateOptions{Name: "web-1", Image: "ubuntu-24-04-x64", Size: "s-1vcpu-1gb", Region: "lon1", SSHKeys: []string{"4001"}, Yes: true})
+	if err != nil {
+		t.Fatalf("unexpected error creating droplet: %v", err)
+	}
+
+	for _, tt := range []struct {
+		action   string
+		expected string
+	}{
+		{action: cloud.PowerShutdown, expected: "off"},
+		{action: cloud.PowerOn, expected: "active"},
+		{action: cloud.PowerOff, expected: "off"},
+		{action: cloud.PowerCycle, expected: "active"},
+	} {
+		powered, err := provider.Power(ctx, cloud.PowerOptions{ServerSelector: cloud.ServerSelector{Name: "web-1"}, Action: tt.action})
+		if err != nil {
+			t.Fatalf("unexpected error running %s: %v", tt.action, err)
+		}
+		if powered.ID != created.ID || powered.Status != tt.expected {
+			t.Errorf("expected %s to leave droplet %s %s, got %+v", tt.action, created.ID, tt.expected, powered)
+		}
+	}
+
+	listed, err := provider.List(ctx, cloud.ListOptions{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if listed[0].Status != "active" {
+		t.Errorf("expected the status to be saved, got %s", listed[0].Status)
+	}
+
+	if _, err := provider.Power(ctx, cloud.PowerOptions{ServerSelector: cloud.ServerSelector{Name: "web-1"}, Action: "restart"}); err == nil {
+		t.Error("expected error for unknown action, got nil")
+	}
+}
+
+func TestProvider_Resize(t *testing.T) {
+	ctx := context.Background()
+	provider := newTestProvider(t)
+
+	_, err := provider.Create(ctx, cloud.CreateOptions{Name: "web-1", Image: "ubuntu-24-04-x64", Size: "s-1vcpu-1gb", Region: "lon1", SSHKeys: []string{"4001"}, Features: []string{"backups"}, Yes: true})
+	if err != nil {
+		t.Fatalf("unexpected error creating droplet: %v", err)
+	}
+
+	if _, err := provider.Resize(ctx, cloud.ResizeOptions{ServerSelector: cloud.ServerSelector{Name: "web-1"}, Size: "s-1vcpu-1gb", Yes: true}); err == nil {
+		t.Error("expected error resizing to the current size, got nil")
+	}
+
+	resized, err := provider.Resize(ctx, cloud.ResizeOptions{ServerSelector: cloud.ServerSelector{Name: "web-1"}, Size: "s-2vcpu-4gb", Yes: true})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// 24 for the size and 4.80 for its backups
+	if resized.Size != "s-2vcpu-4gb" || resized.PriceMonthly != 28.8 {
+		t.Errorf("expected s-2vcpu-4gb at 28.80/mo, got %s at %v", resized.Size, resized.PriceMonthly)
+	}
+
+	if _, err := provider.Resize(ctx, cloud.ResizeOptions{ServerSelector: cloud.ServerSelector{Name: "web-1"}, Size: "s-1vcpu-1gb", Yes: true}); err == nil {
+		t.Error("expected error resizing to a smaller disk, got nil")
+	}
+
+	_, err = provider.Create(ctx, cloud.CreateOptions{Name: "web-2", Image: "ubuntu-24-04-x64", Size: "s-1vcpu-1gb", Region: "lon1", SSHKeys: []string{"4001"}, Features: []string{"monitoring"}, Yes: true})
+	if err != nil {
+		t.Fatalf("unexpected error creating droplet: %v", err)
+	}
+
+	resized, err = provider.Resize(ctx, cloud.ResizeOptions{ServerSelector: cloud.ServerSelector{Name: "web-2"}, Size: "s-2vcpu-4gb", Yes: true})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// without backups only the size is paid for
+	if resized.PriceMonthly != 24 {
+		t.Errorf("expected s-2vcpu-4gb at 24/mo, got %v", resized.PriceMonthly)
+	}
+}
+
+func TestProvider_Rebuild(t *testing.T) {
+	ctx := context.Background()
+	provider := newTestProvider(t)
+
+	_, err := provider.Create(ctx, cloud.CreateOptions{Name: "test-1", Image: "ubuntu-22-04-x64", Size: "s-1vcpu-1gb", Region: "lon1", SSHKeys: []string{"4001"}, Yes: true})
+	if err != nil {
+		t.Fatalf("unexpected error creating droplet: %v", err)
+	}
+
+	selector := cloud.ServerSelector{Name: "test-1"}
+
+	if _, err := provider.Rebuild(ctx, cloud.RebuildOptions{ServerSelector: selector, Image: "ubuntu-24-04-x64", ConfirmName: "test-2", Yes: true}); err == nil {
+		t.Error("expected error when the name doesn't match, got nil")
+	}
+
+	if _, err := provider.Rebuild(ctx, cloud.RebuildOptions{ServerSelector: selector, Image: "windows-2022", ConfirmName: "test-1", Yes: true}); err == nil {
+		t.Error("expected error for unknown image, got nil")
+	}
+
+	rebuilt, err := provider.Rebuild(ctx, cloud.RebuildOptions{ServerSelector: selector, Image: "ubuntu-24-04-x64", ConfirmName: "test-1", Yes: true})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if rebuilt.Image != "ubuntu-24-04-x64" {
+		t.Errorf("expected the droplet to be rebuilt from ubuntu-24-04-x64, got %s", rebuilt.Image)
+	}
+}
@@ -0,0 +1,156 @@
+// Package output renders lists of servers in formats that are easy to consume
+// from other tools, such as json for jq or csv for spreadsheets
+package output
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/Joel-Valentine/cogo/cloud"
+	"github.com/fatih/color"
+	"gopkg.in/yaml.v3"
+)
+
+// Supported output formats
+const (
+	JSON = "json"
+	YAML = "yaml"
+	CSV  = "csv"
+	Wide = "wide"
+	Name = "name"
+	ID   = "id"
+)
+
+// Formats is a list of all output formats that can be rendered
+var Formats = []string{JSON, YAML, CSV, Wide, Name, ID}
+
+// columns are the headings used by the csv and wide formats, in order
+var columns = []string{"ID", "NAME", "STATUS", "REGION", "SIZE", "IMAGE", "PUBLIC IPV4", "PRIVATE IPV4", "PUBLIC IPV6", "TAGS", "VPC", "CREATED AT", "PRICE MONTHLY"}
+
+// ValidateFormat will check whether format is one of the supported output formats
+func ValidateFormat(format string) error {
+	for _, supported := range Formats {
+		if format == supported {
+			return nil
+		}
+	}
+	return fmt.Errorf("unknown output format %q, must be one of: %s", format, strings.Join(Formats, ", "))
+}
+
+// Render writes the servers to w in the given format
+func Render(w io.Writer, format string, servers []cloud.Server) error {
+	// Make sure an empty list is rendered as [] rather than null
+	if servers == nil {
+		servers = []cloud.Server{}
+	}
+
+	switch format {
+	case JSON:
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(servers)
+	case YAML:
+		encoder := yaml.NewEncoder(w)
+		encoder.SetIndent(2)
+		if err := encoder.Encode(servers); err != nil {
+			return err
+		}
+		return encoder.Close()
+	case CSV:
+		return renderCSV(w, servers)
+	case Wide:
+		return renderWide(w, servers)
+	case Name:
+		for _, server := range servers {
+			fmt.Fprintln(w, server.Name)
+		}
+		return nil
+	case ID:
+		for _, server := range servers {
+			fmt.Fprintln(w, server.ID)
+		}
+		return nil
+	}
+
+	return ValidateFormat(format)
+}
+
+// row returns the server's fields in the same order as columns
+func row(s cloud.Server) []string {
+	return []string{
+		s.ID,
+		s.Name,
+		s.Status,
+		s.Region,
+		s.Size,
+		s.Image,
+		s.PublicIPv4,
+		s.PrivateIPv4,
+		s.PublicIPv6,
+		strings.Join(s.Tags, ";"),
+		s.VPC,
+		s.CreatedAt,
+		strconv.FormatFloat(s.PriceMonthly, 'f', 2, 64),
+	}
+}
+
+func renderCSV(w io.Writer, servers []cloud.Server) error {
+	writer := csv.NewWriter(w)
+
+	if err := writer.Write(columns); err != nil {
+		return err
+	}
+
+	for _, server := range servers {
+		if err := writer.Write(row(server)); err != nil {
+			return err
+		}
+	}
+
+	writer.Flush()
+	return writer.Error()
+}
+
+func renderWide(w io.Writer, servers []cloud.Server) error {
+	writer := tabwriter.NewWriter(w, 0, 0, 3, ' ', 0)
+
+	fmt.Fprintln(writer, strings.Join(columns, "\t"))
+
+	for _, server := range servers {
+		cells := row(server)
+		// Leave empty cells visible so the columns still line up when read by eye
+		for index, cell := range cells {
+			if cell == "" {
+				cells[index] = "-"
+			}
+		}
+		fmt.Fprintln(writer, strings.Join(cells, "\t"))
+	}
+
+	return writer.Flush()
+}
+
+// Display prints the servers with some colours for reading in a terminal
+// noun is what the provider calls its servers (droplet, instance...)
+func Display(noun string, servers []cloud.Server) {
+	color.Green("\nYour %ss:\n\n", noun)
+	for index, server := range servers {
+		tags := ""
+		if len(server.Tags) > 0 {
+			tags = "   Tags: " + strings.Join(server.Tags, ", ") + "\n"
+		}
+
+		if server.PublicIPv4 == "" {
+			fmt.Printf("%v Name: %s\n%s\n", index, server.Name, tags)
+		} else {
+			red := color.New(color.FgRed).SprintFunc()
+			cyan := color.New(color.FgCyan).SprintFunc()
+			color.Cyan("%v  Name: %s\n   IP: %s\n%s\n", cyan(index), red(server.Name), red(server.PublicIPv4), tags)
+		}
+	}
+}
@@ -0,0 +1,109 @@
+package output
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/Joel-Valentine/cogo/cloud"
+)
+
+var testServers = []cloud.Server{
+	{
+		ID:           "1",
+		Name:         "web-1",
+		Status:       "active",
+		Region:       "lon1",
+		Size:         "s-1vcpu-1gb",
+		Image:        "ubuntu-24-04-x64",
+		PublicIPv4:   "203.0.113.10",
+		Tags:         []string{"web", "prod"},
+		PriceMonthly: 6,
+	},
+	{
+		ID:     "2",
+		Name:   "db",
+		Status: "new",
+		Tags:   []string{},
+	},
+}
+
+func TestRender_JSON(t *testing.T) {
+	var buf bytes.Buffer
+
+	if err := Render(&buf, JSON, testServers); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var decoded []cloud.Server
+	if err := json.Unmarshal(buf.Bytes(), &decoded); err != nil {
+		t.Fatalf("output was not valid json: %v", err)
+	}
+
+	if len(decoded) != 2 || decoded[0].PublicIPv4 != "203.0.113.10" {
+		t.Errorf("unexpected decoded servers: %+v", decoded)
+	}
+}
+
+func TestRender_EmptyJSON(t *testing.T) {
+	var buf bytes.Buffer
+
+	if err := Render(&buf, JSON, nil); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if strings.TrimSpace(buf.String()) != "[]" {
+		t.Errorf("expected empty list, got %q", buf.String())
+	}
+}
+
+func TestRender_CSV(t *testing.T) {
+	var buf bytes.Buffer
+
+	if err := Render(&buf, CSV, testServers); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("expected header and 2 rows, got %d lines", len(lines))
+	}
+
+	expected := "1,web-1,active,lon1,s-1vcpu-1gb,ubuntu-24-04-x64,203.0.113.10,,,web;prod,,,6.00"
+	if lines[1] != expected {
+		t.Errorf("expected row %q, got %q", expected, lines[1])
+	}
+}
+
+func TestRender_NameAndID(t *testing.T) {
+	tests := []struct {
+		format   string
+		expected string
+	}{
+		{format: Name, expected: "web-1\ndb\n"},
+		{format: ID, expected: "1\n2\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.format, func(t *testing.T) {
+			var buf bytes.Buffer
+
+			if err := Render(&buf, tt.format, testServers); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if buf.String() != tt.expected {
+				t.Errorf("expected %q, got %q", tt.expected, buf.String())
+			}
+		})
+	}
+}
+
+func TestRender_UnknownFormat(t *testing.T) {
+	var buf bytes.Buffer
+
+	if err := Render(&buf, "xml", testServers); err == nil {
+		t.Error("expected error, got nil")
+	}
+}
@@ -0,0 +1,264 @@
+// Package preset keeps named sets of create answers as plain YAML files, so the same kind of
+// server can be created again with only the missing answers asked for, e.g.
+// cogo create --preset web --name web-3
+package preset
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/Joel-Valentine/cogo/cloud"
+	"github.com/Joel-Valentine/cogo/config"
+	"gopkg.in/yaml.v3"
+)
+
+// DirName is the directory in the cogo config directory that presets are kept in
+const DirName = "presets"
+
+// extension is the file extension of saved presets
+const extension = ".yaml"
+
+var namePattern = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9_-]*$`)
+
+// ErrNotFound is returned when there is no preset with the given name
+var ErrNotFound = errors.New("preset not found")
+
+// Preset is a set of create answers, any left empty are asked for as usual
+type Preset struct {
+	Provider string   `yaml:"provider,omitempty"`
+	Image    string   `yaml:"image,omitempty"`
+	Size     string   `yaml:"size,omitempty"`
+	Region   string   `yaml:"region,omitempty"`
+	VPC      string   `yaml:"vpc,omitempty"`
+	Features []string `yaml:"features,omitempty"`
+	SSHKeys  []string `yaml:"ssh_keys,omitempty"`
+	Tags     []string `yaml:"tags,omitempty"`
+	UserData string   `yaml:"user_data,omitempty"`
+}
+
+// FromOptions returns the answers in opts that a preset keeps
+func FromOptions(provider string, opts cloud.CreateOptions) Preset {
+	return Preset{
+		Provider: provider,
+		Image:    opts.Image,
+		Size:     opts.Size,
+		Region:   opts.Region,
+		VPC:      opts.VPC,
+		Features: opts.Features,
+		SSHKeys:  opts.SSHKeys,
+		Tags:     opts.Tags,
+		UserData: opts.UserDataFile,
+	}
+}
+
+// IsEmpty reports whether the preset has no answers
+func (p Preset) IsEmpty() bool {
+	return p.Provider == "" && p.Image == "" && p.Size == "" && p.Region == "" && p.VPC == "" &&
+		p.Features == nil && len(p.SSHKeys) == 0 && len(p.Tags) == 0 && p.UserData == ""
+}
+
+// Apply fills in the answers in opts that weren't already given, so flags override the preset
+func (p Preset) Apply(opts *cloud.CreateOptions) {
+	if opts.Image == "" {
+		opts.Image = p.Image
+	}
+	if opts.Size == "" {
+		opts.Size = p.Size
+	}
+	if opts.Region == "" && len(opts.Regions) == 0 {
+		opts.Region = p.Region
+	}
+	if opts.VPC == "" {
+		opts.VPC = p.VPC
+	}
+	if opts.Features == nil && p.Features != nil {
+		opts.Features = p.Features
+	}
+	if len(opts.SSHKeys) == 0 {
+		opts.SSHKeys = p.SSHKeys
+	}
+	if len(opts.Tags) == 0 {
+		opts.Tags = p.Tags
+	}
+	if opts.UserDataFile == "" {
+		opts.UserDataFile = p.UserData
+	}
+}
+
+// ValidateName checks the name can be used as the file name of a preset
+func ValidateName(name string) error {
+	if !namePattern.MatchString(name) {
+		return fmt.Errorf("preset name %q must start with a letter or number and only contain letters, numbers, - and _", name)
+	}
+	return nil
+}
+
+// Dir returns the directory presets are kept in
+func Dir() (string, error) {
+	dir, err := config.Dir()
+
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(dir, DirName), nil
+}
+
+// path returns where the preset is kept, a name that is a path to a YAML file
+// (one checked into a team repo for example) is used as it is
+func path(name string) (string, error) {
+	if strings.ContainsRune(name, os.PathSeparator) || strings.HasSuffix(name, ".yaml") || strings.HasSuffix(name, ".yml") {
+		return name, nil
+	}
+
+	if err := ValidateName(name); err != nil {
+		return "", err
+	}
+
+	dir, err := Dir()
+
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(dir, name+extension), nil
+}
+
+// Load reads the preset with the name, or the preset file at the path
+func Load(name string) (Preset, error) {
+	file, err := path(name)
+
+	if err != nil {
+		return Preset{}, err
+	}
+
+	content, err := os.ReadFile(file)
+
+	if errors.Is(err, os.ErrNotExist) {
+		return Preset{}, fmt.Errorf("%w: %s", ErrNotFound, name)
+	}
+
+	if err != nil {
+		return Preset{}, fmt.Errorf("failed to read preset: %w", err)
+	}
+
+	var p Preset
+
+	// Unknown fields are refused so a typo in a hand written preset isn't silently ignored
+	decoder := yaml.NewDecoder(bytes.NewReader(content))
+	decoder.KnownFields(true)
+
+	if err := decoder.Decode(&p); err != nil && !errors.Is(err, io.EOF) {
+		return Preset{}, fmt.Errorf("preset %s is not valid: %w", name, err)
+	}
+
+	// A relative user data file is next to the preset, not wherever cogo is run from
+	if p.UserData != "" && !filepath.IsAbs(p.UserData) {
+		p.UserData = filepath.Join(filepath.Dir(file), p.UserData)
+	}
+
+	return p, nil
+}
+
+// Save writes the preset under the name, replacing any preset that already has it
+func Save(name string, p Preset) error {
+	if err := ValidateName(name); err != nil {
+		return err
+	}
+
+	file, err := path(name)
+
+	if err != nil {
+		return err
+	}
+
+	// The user data file was given relative to where cogo was run, which Load can't know
+	if p.UserData != "" {
+		userData, err := filepath.Abs(p.UserData)
+
+		if err != nil {
+			return err
+		}
+
+		p.UserData = userData
+	}
+
+	content, err := Marshal(p)
+
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(file), 0700); err != nil {
+		return err
+	}
+
+	return os.WriteFile(file, content, 0600)
+}
+
+// Marshal returns the preset as the YAML it is saved as
+func Marshal(p Preset) ([]byte, error) {
+	return yaml.Marshal(p)
+}
+
+// Delete removes the preset with the name
+func Delete(name string) error {
+	if err := ValidateName(name); err != nil {
+		return err
+	}
+
+	file, err := path(name)
+
+	if err != nil {
+		return err
+	}
+
+	err = os.Remove(file)
+
+	if errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("%w: %s", ErrNotFound, name)
+	}
+
+	return err
+}
+
+// List returns the names of the saved presets sorted by name
+// A missing directory has no presets
+func List() ([]string, error) {
+	dir, err := Dir()
+
+	if err != nil {
+		return nil, err
+	}
+
+	entries, err := os.ReadDir(dir)
+
+	if errors.Is(err, os.ErrNotExist) {
+		return []string{}, nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	names := []string{}
+
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), extension) {
+			continue
+		}
+
+		names = append(names, strings.TrimSuffix(entry.Name(), extension))
+	}
+
+	sort.Strings(names)
+
+	return names, nil
+}
@@ -0,0 +1,206 @@
+package preset
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/Joel-Valentine/cogo/cloud"
+	"github.com/Joel-Valentine/cogo/config"
+)
+
+func TestSaveLoadListDelete(t *testing.T) {
+	t.Setenv(config.DirEnvVar, t.TempDir())
+
+	names, err := List()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(names) != 0 {
+		t.Errorf("expected no presets without a directory, got %v", names)
+	}
+
+	web := Preset{Provider: "do", Image: "ubuntu-24-04-x64", Size: "s-1vcpu-1gb", Region: "lon1", Tags: []string{"web"}, Features: []string{"monitoring"}}
+
+	for name, p := range map[string]Preset{"web": web, "db": {Size: "s-2vcpu-4gb"}} {
+		if err := Save(name, p); err != nil {
+			t.Fatalf("failed to save %s: %v", name, err)
+		}
+	}
+
+	names, err = List()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if strings.Join(names, ",") != "db,web" {
+		t.Errorf("expected db,web, got %v", names)
+	}
+
+	loaded, err := Load("web")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if loaded.Image != web.Image || loaded.Region != web.Region || strings.Join(loaded.Tags, ",") != "web" || strings.Join(loaded.Features, ",") != "monitoring" {
+		t.Errorf("loaded %+v, saved %+v", loaded, web)
+	}
+
+	if err := Save("app", Preset{UserData: "./app.yaml"}); err != nil {
+		t.Fatalf("failed to save app: %v", err)
+	}
+
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	loaded, err = Load("app")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if loaded.UserData != filepath.Join(wd, "app.yaml") {
+		t.Errorf("expected user data relative to the working directory, got %s", loaded.UserData)
+	}
+
+	if err := Delete("web"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if _, err := Load("web"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("expected ErrNotFound after delete, got %v", err)
+	}
+	if err := Delete("web"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("expected ErrNotFound deleting twice, got %v", err)
+	}
+}
+
+func TestLoad_File(t *testing.T) {
+	t.Setenv(config.DirEnvVar, t.TempDir())
+
+	dir := t.TempDir()
+
+	tests := []struct {
+		name        string
+		content     string
+		expected    Preset
+		expectError bool
+	}{
+		{
+			name:     "team preset",
+			content:  "size: s-2vcpu-4gb\nssh_keys:\n  - \"12345\"\nuser_data: ./db.yaml\n",
+			expected: Preset{Size: "s-2vcpu-4gb", SSHKeys: []string{"12345"}, UserData: filepath.Join(dir, "db.yaml")},
+		},
+		{
+			name:     "absolute user data",
+			content:  "user_data: /etc/cogo/db.yaml\n",
+			expected: Preset{UserData: "/etc/cogo/db.yaml"},
+		},
+		{
+			name:     "empty",
+			content:  "",
+			expected: Preset{},
+		},
+		{
+			name:        "unknown field",
+			content:     "sise: s-2vcpu-4gb\n",
+			expectError: true,
+		},
+	}
+
+	for index, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			file := filepath.Join(dir, string(rune('a'+index))+".yaml")
+			if err := os.WriteFile(file, []byte(tt.content), 0600); err != nil {
+				t.Fatalf("failed to write preset: %v", err)
+			}
+
+			loaded, err := Load(file)
+
+			if tt.expectError {
+				if err == nil {
+					t.Error("expected error, got nil")
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if loaded.Size != tt.expected.Size || loaded.UserData != tt.expected.UserData || strings.Join(loaded.SSHKeys, ",") != strings.Join(tt.expected.SSHKeys, ",") {
+				t.Errorf("loaded %+v, expected %+v", loaded, tt.expected)
+			}
+		})
+	}
+}
+
+func TestValidateName(t *testing.T) {
+	tests := []struct {
+		name  string
+		valid bool
+	}{
+		{name: "web", valid: true},
+		{name: "db_2-large", valid: true},
+		{name: "", valid: false},
+		{name: "-web", valid: false},
+		{name: "../web", valid: false},
+		{name: "web prod", valid: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateName(tt.name)
+
+			if tt.valid && err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+			if !tt.valid && err == nil {
+				t.Error("expected error, got nil")
+			}
+		})
+	}
+}
+
+func TestApply(t *testing.T) {
+	p := Preset{Image: "ubuntu-24-04-x64", Size: "s-1vcpu-1gb", Region: "lon1", Features: []string{"backups"}, Tags: []string{"web"}}
+
+	tests := []struct {
+		name     string
+		opts     cloud.CreateOptions
+		expected cloud.CreateOptions
+	}{
+		{
+			name:     "fills in what is missing",
+			opts:     cloud.CreateOptions{Name: "web-3"},
+			expected: cloud.CreateOptions{Name: "web-3", Image: "ubuntu-24-04-x64", Size: "s-1vcpu-1gb", Region: "lon1", Features: []string{"backups"}, Tags: []string{"web"}},
+		},
+		{
+			name:     "flags override the preset",
+			opts:     cloud.CreateOptions{Size: "s-2vcpu-4gb", Features: []string{}, Tags: []string{"api"}},
+			expected: cloud.CreateOptions{Image: "ubuntu-24-04-x64", Size: "s-2vcpu-4gb", Region: "lon1", Features: []string{}, Tags: []string{"api"}},
+		},
+		{
+			name:     "regions replace the region",
+			opts:     cloud.CreateOptions{Regions: []string{"ams3", "fra1"}},
+			expected: cloud.CreateOptions{Image: "ubuntu-24-04-x64", Size: "s-1vcpu-1gb", Regions: []string{"ams3", "fra1"}, Features: []string{"backups"}, Tags: []string{"web"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := tt.opts
+			p.Apply(&opts)
+
+			if opts.Name != tt.expected.Name || opts.Image != tt.expected.Image || opts.Size != tt.expected.Size || opts.Region != tt.expected.Region {
+				t.Errorf("got %+v, expected %+v", opts, tt.expected)
+			}
+			if strings.Join(opts.Regions, ",") != strings.Join(tt.expected.Regions, ",") || strings.Join(opts.Tags, ",") != strings.Join(tt.expected.Tags, ",") {
+				t.Errorf("got %+v, expected %+v", opts, tt.expected)
+			}
+			if (opts.Features == nil) != (tt.expected.Features == nil) || strings.Join(opts.Features, ",") != strings.Join(tt.expected.Features, ",") {
+				t.Errorf("got features %v, expected %v", opts.Features, tt.expected.Features)
+			}
+		})
+	}
+}
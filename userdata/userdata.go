@@ -0,0 +1,144 @@
+// Package userdata renders and validates the cloud-init user data given to new servers.
+// User data files are Go templates, so they can use the server's name and region or
+// environment variables, e.g. {{ .Name }} or {{ env "DEPLOY_KEY" }}
+package userdata
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/template"
+
+	"github.com/Joel-Valentine/cogo/config"
+	"github.com/Joel-Valentine/cogo/utils"
+	"gopkg.in/yaml.v3"
+)
+
+// DirName is the directory in the cogo config directory that templates are kept in
+const DirName = "user-data"
+
+// cloudConfigHeader is the first line of user data that cloud-init reads as YAML
+const cloudConfigHeader = "#cloud-config"
+
+// Vars are the values a template can use
+type Vars struct {
+	Name   string
+	Region string
+	Size   string
+	Image  string
+}
+
+// Render executes the user data template with vars
+// {{ env "NAME" }} returns the value of an environment variable
+func Render(name string, content string, vars Vars) (string, error) {
+	tmpl, err := template.New(name).
+		Option("missingkey=error").
+		Funcs(template.FuncMap{"env": os.Getenv}).
+		Parse(content)
+
+	if err != nil {
+		return "", fmt.Errorf("failed to parse user data template %s: %w", name, err)
+	}
+
+	var rendered bytes.Buffer
+	if err := tmpl.Execute(&rendered, vars); err != nil {
+		return "", fmt.Errorf("failed to render user data template %s: %w", name, err)
+	}
+
+	return rendered.String(), nil
+}
+
+// Validate checks the user data is at most limit bytes and, if it is #cloud-config,
+// that the rest of it is a YAML mapping. Scripts and other formats are only size checked
+func Validate(data string, limit int) error {
+	if len(data) > limit {
+		return fmt.Errorf("user data is %d bytes, the limit is %d bytes", len(data), limit)
+	}
+
+	if !strings.HasPrefix(data, cloudConfigHeader) {
+		return nil
+	}
+
+	var document interface{}
+	if err := yaml.Unmarshal([]byte(data), &document); err != nil {
+		return fmt.Errorf("user data is not valid cloud-config YAML: %w", err)
+	}
+
+	if document == nil {
+		return nil
+	}
+
+	if _, ok := document.(map[string]interface{}); !ok {
+		return errors.New("user data is not valid cloud-config YAML: the top level must be a mapping")
+	}
+
+	return nil
+}
+
+// Load reads the user data file at path, renders it with vars and validates the result
+func Load(path string, vars Vars, limit int) (string, error) {
+	content, err := os.ReadFile(path)
+
+	if err != nil {
+		return "", fmt.Errorf("failed to read user data: %w", err)
+	}
+
+	rendered, err := Render(filepath.Base(path), string(content), vars)
+
+	if err != nil {
+		return "", err
+	}
+
+	if err := Validate(rendered, limit); err != nil {
+		return "", fmt.Errorf("%s: %w", path, err)
+	}
+
+	return rendered, nil
+}
+
+// Dir returns the directory user data templates are kept in
+func Dir() (string, error) {
+	dir, err := config.Dir()
+
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(dir, DirName), nil
+}
+
+// Templates returns the files in the template directory as SelectItems sorted by name, the value is the file's path
+// A missing directory has no templates
+func Templates() ([]utils.SelectItem, error) {
+	dir, err := Dir()
+
+	if err != nil {
+		return nil, err
+	}
+
+	entries, err := os.ReadDir(dir)
+
+	if errors.Is(err, os.ErrNotExist) {
+		return []utils.SelectItem{}, nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	selectList := []utils.SelectItem{}
+
+	for _, entry := range entries {
+		if entry.IsDir() || strings.HasPrefix(entry.Name(), ".") {
+			continue
+		}
+
+		listItem := utils.SelectItem{Name: entry.Name(), Value: filepath.Join(dir, entry.Name())}
+		selectList = append(selectList, listItem)
+	}
+
+	return selectList, nil
+}
@@ -0,0 +1,155 @@
+package userdata
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/Joel-Valentine/cogo/config"
+)
+
+func TestRender(t *testing.T) {
+	t.Setenv("COGO_TEST_DEPLOY_KEY", "secret")
+
+	vars := Vars{Name: "web-1", Region: "lon1", Size: "s-1vcpu-1gb", Image: "ubuntu-24-04-x64"}
+
+	tests := []struct {
+		name        string
+		content     string
+		expected    string
+		expectError bool
+	}{
+		{
+			name:     "server values",
+			content:  "#cloud-config\nhostname: {{ .Name }}.{{ .Region }}\n",
+			expected: "#cloud-config\nhostname: web-1.lon1\n",
+		},
+		{
+			name:     "environment variable",
+			content:  "key: {{ env \"COGO_TEST_DEPLOY_KEY\" }}",
+			expected: "key: secret",
+		},
+		{
+			name:        "unknown value",
+			content:     "{{ .Nope }}",
+			expectError: true,
+		},
+		{
+			name:        "bad template",
+			content:     "{{ .Name ",
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rendered, err := Render("test", tt.content, vars)
+
+			if tt.expectError {
+				if err == nil {
+					t.Error("expected error, got nil")
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if rendered != tt.expected {
+				t.Errorf("expected %q, got %q", tt.expected, rendered)
+			}
+		})
+	}
+}
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name        string
+		data        string
+		limit       int
+		expectError bool
+	}{
+		{
+			name:  "cloud-config",
+			data:  "#cloud-config\npackages:\n  - nginx\n",
+			limit: 1024,
+		},
+		{
+			name:  "empty cloud-config",
+			data:  "#cloud-config\n",
+			limit: 1024,
+		},
+		{
+			name:  "shell script is not parsed",
+			data:  "#!/bin/bash\necho: [\n",
+			limit: 1024,
+		},
+		{
+			name:        "invalid yaml",
+			data:        "#cloud-config\npackages: [nginx\n",
+			limit:       1024,
+			expectError: true,
+		},
+		{
+			name:        "not a mapping",
+			data:        "#cloud-config\n- nginx\n",
+			limit:       1024,
+			expectError: true,
+		},
+		{
+			name:        "too large",
+			data:        "#!/bin/bash\n" + strings.Repeat("#", 100),
+			limit:       100,
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := Validate(tt.data, tt.limit)
+
+			if tt.expectError && err == nil {
+				t.Error("expected error, got nil")
+			}
+			if !tt.expectError && err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+		})
+	}
+}
+
+func TestTemplates(t *testing.T) {
+	t.Setenv(config.DirEnvVar, t.TempDir())
+
+	templates, err := Templates()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(templates) != 0 {
+		t.Errorf("expected no templates without a directory, got %v", templates)
+	}
+
+	dir, err := Dir()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	for _, name := range []string{"web.yaml", "db.yaml", ".hidden"} {
+		if err := os.MkdirAll(dir, 0700); err != nil {
+			t.Fatalf("failed to create dir: %v", err)
+		}
+		if err := os.WriteFile(filepath.Join(dir, name), []byte("#cloud-config\n"), 0600); err != nil {
+			t.Fatalf("failed to write template: %v", err)
+		}
+	}
+
+	templates, err = Templates()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(templates) != 2 || templates[0].Name != "db.yaml" || templates[1].Value != filepath.Join(dir, "web.yaml") {
+		t.Errorf("unexpected templates %v", templates)
+	}
+}
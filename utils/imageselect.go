@@ -0,0 +1,105 @@
+package utils
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/digitalocean/godo"
+)
+
+// ImageValue returns what a droplet is created from for the image, its slug
+// or its ID for custom images and snapshots that have no slug
+func ImageValue(image godo.Image) string {
+	if image.Slug != "" {
+		return image.Slug
+	}
+	return strconv.Itoa(image.ID)
+}
+
+// FilterImages returns the images droplets can be created from, leaving out any that are retired,
+// deleted or still being made, and any that aren't in every one of the regions
+func FilterImages(list []godo.Image, regions []string) []godo.Image {
+	filtered := []godo.Image{}
+
+	for _, image := range list {
+		if image.Status != "" && image.Status != "available" {
+			continue
+		}
+
+		inRegions := true
+		for _, region := range regions {
+			if !contains(image.Regions, region) {
+				inRegions = false
+			}
+		}
+
+		if inRegions {
+			filtered = append(filtered, image)
+		}
+	}
+
+	return filtered
+}
+
+// imageDetails returns the minimum disk size and creation date of the image, leaving out whichever isn't known
+func imageDetails(image godo.Image) string {
+	details := []string{}
+
+	if image.MinDiskSize > 0 {
+		details = append(details, fmt.Sprintf("min disk %d GB", image.MinDiskSize))
+	}
+
+	if created, err := time.Parse(time.RFC3339, image.Created); err == nil {
+		details = append(details, "created "+created.Format("2006-01-02"))
+	}
+
+	return strings.Join(details, ", ")
+}
+
+// ParseImageDistributionList will return the distributions of the images as SelectItems to be used for promptui
+// in the order they first appear, with how many versions there are of each
+func ParseImageDistributionList(list []godo.Image) []SelectItem {
+	distributions := []string{}
+	versions := map[string]int{}
+
+	for _, image := range list {
+		if versions[image.Distribution] == 0 {
+			distributions = append(distributions, image.Distribution)
+		}
+		versions[image.Distribution]++
+	}
+
+	selectList := []SelectItem{}
+
+	for _, distribution := range distributions {
+		name := fmt.Sprintf("%s (%d versions)", distribution, versions[distribution])
+		if versions[distribution] == 1 {
+			name = fmt.Sprintf("%s (1 version)", distribution)
+		}
+		selectList = append(selectList, SelectItem{Name: name, Value: distribution})
+	}
+
+	return selectList
+}
+
+// ParseImageVersionList will return the images of the distribution as SelectItems to be used for promptui
+// newest first, showing the minimum disk size and creation date of each
+func ParseImageVersionList(list []godo.Image, distribution string) []SelectItem {
+	versions := []godo.Image{}
+
+	for _, image := range list {
+		if image.Distribution == distribution {
+			versions = append(versions, image)
+		}
+	}
+
+	// RFC3339 dates in the same zone sort as strings
+	sort.SliceStable(versions, func(i, j int) bool {
+		return versions[i].Created > versions[j].Created
+	})
+
+	return ParseImageListResults(versions)
+}
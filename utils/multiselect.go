@@ -0,0 +1,151 @@
+package utils
+
+import (
+	"fmt"
+
+	"github.com/manifoldco/promptui"
+)
+
+// Actions shown above the items of a multi-select prompt
+const (
+	multiSelectDone      = "done"
+	multiSelectToggleAll = "all"
+)
+
+// multiSelectOption is a row of a multi-select prompt, either an item that can be toggled or an action
+type multiSelectOption struct {
+	SelectItem
+	Checked bool
+	Action  string
+}
+
+// multiSelection keeps track of which items of a multi-select prompt are selected
+type multiSelection struct {
+	list     []SelectItem
+	selected []bool
+}
+
+// newMultiSelection creates a selection of the list with nothing selected
+func newMultiSelection(list []SelectItem) *multiSelection {
+	return &multiSelection{
+		list:     list,
+		selected: make([]bool, len(list)),
+	}
+}
+
+// toggle selects the item at index if it isn't selected, otherwise deselects it
+func (m *multiSelection) toggle(index int) {
+	m.selected[index] = !m.selected[index]
+}
+
+// toggleAll selects every item, or deselects every item if they are all selected already
+func (m *multiSelection) toggleAll() {
+	allSelected := m.count() == len(m.list)
+
+	for index := range m.selected {
+		m.selected[index] = !allSelected
+	}
+}
+
+// count returns how many items are selected
+func (m *multiSelection) count() int {
+	count := 0
+	for _, selected := range m.selected {
+		if selected {
+			count++
+		}
+	}
+	return count
+}
+
+// values returns the values of the selected items in list order
+func (m *multiSelection) values() []string {
+	values := []string{}
+	for index, item := range m.list {
+		if m.selected[index] {
+			values = append(values, item.Value)
+		}
+	}
+	return values
+}
+
+// options returns the rows of the prompt, the actions followed by every item
+func (m *multiSelection) options() []multiSelectOption {
+	toggleAllName := "Select all"
+	if m.count() == len(m.list) {
+		toggleAllName = "Deselect all"
+	}
+
+	options := []multiSelectOption{
+		{SelectItem: SelectItem{Name: fmt.Sprintf("Done (%d selected)", m.count())}, Action: multiSelectDone},
+		{SelectItem: SelectItem{Name: toggleAllName}, Action: multiSelectToggleAll},
+	}
+
+	for index, item := range m.list {
+		options = append(options, multiSelectOption{SelectItem: item, Checked: m.selected[index]})
+	}
+
+	return options
+}
+
+// createCustomMultiSelectPrompt will create a customised formatted select prompt where each item
+// is shown with whether it is checked. It uses the same searcher as CreateCustomSelectPrompt
+func createCustomMultiSelectPrompt(title string, options []multiSelectOption) promptui.Select {
+	selectList := []SelectItem{}
+	for _, option := range options {
+		selectList = append(selectList, option.SelectItem)
+	}
+
+	prompt := CreateCustomSelectPrompt(title, selectList)
+
+	prompt.Items = options
+	prompt.HideSelected = true
+	prompt.Templates = &promptui.SelectTemplates{
+		Label: "{{ . }}? (enter toggles)",
+
+		Active:   `> {{ if .Action }}{{ .Name | green }}{{ else }}{{ if .Checked }}[x]{{ else }}[ ]{{ end }} {{ .Name | cyan }} ({{ .Value | red }}){{ end }}`,
+		Inactive: `  {{ if .Action }}{{ .Name | green }}{{ else }}{{ if .Checked }}[x]{{ else }}[ ]{{ end }} {{ .Name | cyan }} ({{ .Value | red }}){{ end }}`,
+	}
+
+	return prompt
+}
+
+// AskAndAnswerCustomMultiSelect will ask the user to select any number of items from the list
+// Selecting an item toggles it, and the prompt is asked again until Done is selected
+// returns the values of the selected items
+func AskAndAnswerCustomMultiSelect(title string, list []SelectItem) ([]string, error) {
+	return askMultiSelect(title, newMultiSelection(list))
+}
+
+// askMultiSelect asks the multi-select prompt until Done is selected, starting from the selection given
+func askMultiSelect(title string, selection *multiSelection) ([]string, error) {
+	cursor := 0
+	scroll := 0
+
+	for {
+		options := selection.options()
+		prompt := createCustomMultiSelectPrompt(title, options)
+
+		index, _, err := prompt.RunCursorAt(cursor, scroll)
+
+		if err != nil {
+			return nil, err
+		}
+
+		switch options[index].Action {
+		case multiSelectDone:
+			selected := selection.values()
+			fmt.Printf("%s: %d selected\n", title, len(selected))
+			return selected, nil
+		case multiSelectToggleAll:
+			selection.toggleAll()
+		default:
+			// the actions come first so the item index is offset by them
+			selection.toggle(index - (len(options) - len(selection.list)))
+		}
+
+		// open the prompt again where the user left it
+		cursor = index
+		scroll = prompt.ScrollPosition()
+	}
+}
@@ -0,0 +1,47 @@
+package utils
+
+// recentSuffix marks the items that were pinned to the top of a list because they were chosen recently
+const recentSuffix = " (recent)"
+
+// PinRecent returns the list with the items whose value is in recent moved to the top, in the order
+// of recent (most recent first) and marked as recent, so the prompt opens with the cursor on the last choice
+// values in recent that aren't in the list are ignored
+func PinRecent(list []SelectItem, recent []string) []SelectItem {
+	pinned := []SelectItem{}
+	isPinned := map[string]bool{}
+
+	for _, value := range recent {
+		item, ok := FindSelectItem(list, value)
+
+		if !ok || isPinned[value] {
+			continue
+		}
+
+		isPinned[value] = true
+		pinned = append(pinned, SelectItem{Name: item.Name + recentSuffix, Value: item.Value})
+	}
+
+	for _, item := range list {
+		if !isPinned[item.Value] {
+			pinned = append(pinned, item)
+		}
+	}
+
+	return pinned
+}
+
+// AskAndAnswerRecentMultiSelect is AskAndAnswerCustomMultiSelect with the recent items pinned to the top
+// of the list and already selected, so choosing the same items again is a single enter on Done
+func AskAndAnswerRecentMultiSelect(title string, list []SelectItem, recent []string) ([]string, error) {
+	pinned := PinRecent(list, recent)
+
+	selection := newMultiSelection(pinned)
+
+	for index, item := range pinned {
+		if contains(recent, item.Value) {
+			selection.toggle(index)
+		}
+	}
+
+	return askMultiSelect(title, selection)
+}
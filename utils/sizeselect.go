@@ -0,0 +1,336 @@
+package utils
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/digitalocean/godo"
+	"github.com/manifoldco/promptui"
+)
+
+// Orders the size prompt can be sorted in, typing sort: followed by one in the search box
+// shows the row that sorts the sizes that way
+const (
+	SizeSortPrice  = "price"
+	SizeSortMemory = "memory"
+)
+
+// sizeRow is a row of the size prompt, either a size or an action that sorts the sizes
+type sizeRow struct {
+	SelectItem
+	Size   godo.Size
+	Action string
+}
+
+// FilterSizes returns the sizes that are available, and offered in every one of the regions
+func FilterSizes(list []godo.Size, regions []string) []godo.Size {
+	filtered := []godo.Size{}
+
+	for _, size := range list {
+		if !size.Available {
+			continue
+		}
+
+		offered := true
+		for _, region := range regions {
+			if !contains(size.Regions, region) {
+				offered = false
+			}
+		}
+
+		if offered {
+			filtered = append(filtered, size)
+		}
+	}
+
+	return filtered
+}
+
+// FilterSizesOfferedInAny returns the sizes that are available and offered in at least one of the regions
+func FilterSizesOfferedInAny(list []godo.Size, regions []string) []godo.Size {
+	filtered := []godo.Size{}
+
+	for _, size := range FilterSizes(list, nil) {
+		for _, region := range regions {
+			if contains(size.Regions, region) {
+				filtered = append(filtered, size)
+				break
+			}
+		}
+	}
+
+	return filtered
+}
+
+// SortSizes returns the sizes cheapest first, or with the least memory first, ties are kept in order
+func SortSizes(list []godo.Size, by string) []godo.Size {
+	sorted := append([]godo.Size{}, list...)
+
+	sort.SliceStable(sorted, func(i, j int) bool {
+		if by == SizeSortMemory {
+			return sorted[i].Memory < sorted[j].Memory
+		}
+		return sorted[i].PriceMonthly < sorted[j].PriceMonthly
+	})
+
+	return sorted
+}
+
+// sizeColumns returns the specs and prices of the size as aligned columns
+func sizeColumns(size godo.Size) string {
+	memory := fmt.Sprintf("%d MiB", size.Memory)
+	if size.Memory >= 1024 {
+		memory = fmt.Sprintf("%g GiB", float64(size.Memory)/1024)
+	}
+
+	return fmt.Sprintf("%3d vCPU  %8s RAM  %7s disk  %6s transfer  %9s/mo  %8s/hr",
+		size.Vcpus,
+		memory,
+		fmt.Sprintf("%d GB", size.Disk),
+		fmt.Sprintf("%g TB", size.Transfer),
+		fmt.Sprintf("$%.2f", size.PriceMonthly),
+		fmt.Sprintf("$%.4f", size.PriceHourly),
+	)
+}
+
+// parseComparison reads a search word such as <=12 into its operator and number
+// a word without an operator compares with ==
+func parseComparison(word string) (string, float64, bool) {
+	operator := ""
+	for _, candidate := range []string{"<=", ">=", "<", ">", "="} {
+		if strings.HasPrefix(word, candidate) {
+			operator = candidate
+			break
+		}
+	}
+
+	value, err := strconv.ParseFloat(strings.TrimPrefix(word, operator), 64)
+
+	if err != nil {
+		return "", 0, false
+	}
+
+	if operator == "" {
+		operator = "="
+	}
+
+	return operator, value, true
+}
+
+// compare reports whether value satisfies the operator against target
+func compare(value float64, operator string, target float64) bool {
+	switch operator {
+	case "<=":
+		return value <= target
+	case ">=":
+		return value >= target
+	case "<":
+		return value < target
+	case ">":
+		return value > target
+	}
+	return value == target
+}
+
+// matchSizeSearch reports whether the size matches every word of the search term
+// $<=12 and the like compare the monthly price, >=4gb and the like compare the memory in GiB
+// and any other word has to be in the row shown for the size
+func matchSizeSearch(size godo.Size, row string, term string) bool {
+	for _, word := range strings.Fields(strings.ToLower(term)) {
+		if strings.HasPrefix(word, "$") {
+			if operator, price, ok := parseComparison(strings.TrimPrefix(word, "$")); ok {
+				if !compare(size.PriceMonthly, operator, price) {
+					return false
+				}
+				continue
+			}
+		}
+
+		if strings.HasSuffix(word, "gb") {
+			if operator, memory, ok := parseComparison(strings.TrimSuffix(word, "gb")); ok {
+				if !compare(float64(size.Memory)/1024, operator, memory) {
+					return false
+				}
+				continue
+			}
+		}
+
+		if !strings.Contains(strings.ToLower(row), word) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// sizeRows returns the sort actions followed by a row for each size, the recent sizes are pinned
+// above the rest in the order of recent and marked as recent
+func sizeRows(list []godo.Size, recent []string) []sizeRow {
+	rows := []sizeRow{
+		{SelectItem: SelectItem{Name: "Sort by price (sort:price)"}, Action: SizeSortPrice},
+		{SelectItem: SelectItem{Name: "Sort by memory (sort:memory)"}, Action: SizeSortMemory},
+	}
+
+	slugWidth := 0
+	for _, size := range list {
+		slugWidth = max(slugWidth, len(size.Slug))
+	}
+
+	pinned := []sizeRow{}
+	others := []sizeRow{}
+
+	for _, size := range list {
+		name := fmt.Sprintf("%-*s  %s", slugWidth, size.Slug, sizeColumns(size))
+		row := sizeRow{SelectItem: SelectItem{Name: name, Value: size.Slug}, Size: size}
+
+		if contains(recent, size.Slug) {
+			row.Name += recentSuffix
+			pinned = append(pinned, row)
+		} else {
+			others = append(others, row)
+		}
+	}
+
+	sort.SliceStable(pinned, func(i, j int) bool {
+		return indexOf(recent, pinned[i].Value) < indexOf(recent, pinned[j].Value)
+	})
+
+	return append(append(rows, pinned...), others...)
+}
+
+// sizeRowSearcher matches sizes with matchSizeSearch, the sort actions are only shown
+// when the search starts with sort:
+func sizeRowSearcher(rows []sizeRow) func(input string, index int) bool {
+	return func(input string, index int) bool {
+		row := rows[index]
+		input = strings.ToLower(strings.TrimSpace(input))
+
+		if row.Action != "" {
+			return strings.HasPrefix(input, "sort:") && strings.Contains(row.Name, input)
+		}
+
+		return matchSizeSearch(row.Size, row.Name, input)
+	}
+}
+
+// AskAndAnswerSizeSelect will ask the user to select one of the sizes, shown with their specs and prices
+// Searching with $<=12 or >=4gb filters by monthly price or memory, and searching sort:price or
+// sort:memory shows the row that sorts the sizes that way. The recent sizes are pinned above the rest
+// with the cursor on the most recent
+// returns the slug of the chosen size
+func AskAndAnswerSizeSelect(title string, list []godo.Size, recent []string) (string, error) {
+	sortedBy := SizeSortPrice
+
+	for {
+		rows := sizeRows(SortSizes(list, sortedBy), recent)
+
+		selectList := []SelectItem{}
+		for _, row := range rows {
+			selectList = append(selectList, row.SelectItem)
+		}
+
+		prompt := CreateCustomSelectPrompt(title, selectList)
+
+		prompt.Items = rows
+		prompt.Size = 12
+		prompt.Searcher = sizeRowSearcher(rows)
+
+		// the first size, which is the most recent when there are any, rather than the sort actions
+		prompt.CursorPos = 2
+		prompt.Templates = &promptui.SelectTemplates{
+			Label: "{{ . }}? (/ to search: $<=12, >=4gb, sort:memory)",
+
+			Active:   `> {{ if .Action }}{{ .Name | green }}{{ else }}{{ .Name | cyan }}{{ end }}`,
+			Inactive: `  {{ if .Action }}{{ .Name | green }}{{ else }}{{ .Name }}{{ end }}`,
+			Selected: `{{ if .Action }}{{ .Name | green }}{{ else }}> {{ .Value | red | cyan }}{{ end }}`,
+		}
+
+		index, _, err := prompt.Run()
+
+		if err != nil {
+			return "", err
+		}
+
+		if rows[index].Action == "" {
+			return rows[index].Value, nil
+		}
+
+		sortedBy = rows[index].Action
+	}
+}
+
+// DescribeSize returns the slug of the size followed by its specs and prices
+func DescribeSize(size godo.Size) string {
+	return size.Slug + "  " + strings.TrimSpace(sizeColumns(size))
+}
+
+// ResizeSizes returns the sizes a droplet of the current size with a disk of disk GB can be resized to
+// in the region, cheapest first. Sizes with a smaller disk are left out as a disk can't shrink
+// an empty region doesn't narrow down the sizes
+func ResizeSizes(list []godo.Size, current string, region string, disk int) []godo.Size {
+	sizes := []godo.Size{}
+
+	regions := []string{}
+	if region != "" {
+		regions = append(regions, region)
+	}
+
+	for _, size := range FilterSizes(list, regions) {
+		if size.Slug != current && size.Disk >= disk {
+			sizes = append(sizes, size)
+		}
+	}
+
+	return SortSizes(sizes, SizeSortPrice)
+}
+
+// ParseResizeSizeList will return the sizes a droplet can be resized to as SelectItems to be used for promptui
+// each shows its specs, how much more or less it costs a month than the current size, and whether it has a
+// bigger disk than the droplet's disk of disk GB, which can't be undone if the disk is resized
+func ParseResizeSizeList(list []godo.Size, current godo.Size, disk int) []SelectItem {
+	slugWidth := 0
+	for _, size := range list {
+		slugWidth = max(slugWidth, len(size.Slug))
+	}
+
+	selectList := []SelectItem{}
+
+	for _, size := range list {
+		difference := size.PriceMonthly - current.PriceMonthly
+
+		name := fmt.Sprintf("%-*s  %s  %s/mo", slugWidth, size.Slug, sizeColumns(size), formatPriceDifference(difference))
+
+		if size.Disk > disk {
+			name += fmt.Sprintf("  [disk +%d GB, irreversible]", size.Disk-disk)
+		}
+
+		selectList = append(selectList, SelectItem{Name: name, Value: size.Slug})
+	}
+
+	return selectList
+}
+
+// formatPriceDifference returns the difference with its sign, e.g. +$6.00 or -$12.00
+func formatPriceDifference(difference float64) string {
+	if difference < 0 {
+		return fmt.Sprintf("-$%.2f", -difference)
+	}
+	return fmt.Sprintf("+$%.2f", difference)
+}
+
+// contains reports whether the list has the value
+func contains(list []string, value string) bool {
+	return indexOf(list, value) != -1
+}
+
+// indexOf returns the index of the value in the list, or -1 if it isn't in it
+func indexOf(list []string, value string) int {
+	for index, item := range list {
+		if item == value {
+			return index
+		}
+	}
+	return -1
+}
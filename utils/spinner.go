@@ -0,0 +1,73 @@
+package utils
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/mattn/go-isatty"
+)
+
+var spinnerFrames = []string{"⠋", "⠙", "⠹", "⠸", "⠼", "⠴", "⠦", "⠧", "⠇", "⠏"}
+
+// Spinner shows a progress animation on stderr while waiting for something to finish
+// When stderr is not a terminal the message is printed once instead of animating
+type Spinner struct {
+	message string
+	out     io.Writer
+	animate bool
+	stop    chan struct{}
+	wg      sync.WaitGroup
+}
+
+// NewSpinner creates a spinner that shows the given message next to the animation
+func NewSpinner(message string) *Spinner {
+	return &Spinner{
+		message: message,
+		out:     os.Stderr,
+		animate: isatty.IsTerminal(os.Stderr.Fd()) || isatty.IsCygwinTerminal(os.Stderr.Fd()),
+	}
+}
+
+// Start begins animating the spinner until Stop is called
+func (s *Spinner) Start() {
+	if !s.animate {
+		fmt.Fprintln(s.out, s.message)
+		return
+	}
+
+	s.stop = make(chan struct{})
+	s.wg.Add(1)
+
+	go func() {
+		defer s.wg.Done()
+
+		ticker := time.NewTicker(100 * time.Millisecond)
+		defer ticker.Stop()
+
+		for frame := 0; ; frame++ {
+			fmt.Fprintf(s.out, "\r%s %s", spinnerFrames[frame%len(spinnerFrames)], s.message)
+
+			select {
+			case <-s.stop:
+				// Clear the spinner line so following output starts on a clean line
+				fmt.Fprintf(s.out, "\r\033[K")
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+// Stop ends the animation and clears the spinner from the terminal
+func (s *Spinner) Stop() {
+	if s.stop == nil {
+		return
+	}
+
+	close(s.stop)
+	s.wg.Wait()
+	s.stop = nil
+}
@@ -2,10 +2,15 @@ package utils
 
 import (
 	"errors"
+	"fmt"
+	"regexp"
 	"strconv"
 	"strings"
 
+	ec2 "github.com/Joel-Valentine/cogo/aws/api"
 	"github.com/digitalocean/godo"
+	"github.com/hetznercloud/hcloud-go/v2/hcloud"
+	"github.com/linode/linodego"
 	"github.com/manifoldco/promptui"
 )
 
@@ -27,25 +32,28 @@ func CreateCustomSelectPrompt(title string, completeList []SelectItem) promptui.
 		Selected: "> {{ .Name | red | cyan }}",
 	}
 
-	searcher := func(input string, index int) bool {
-		item := selectList[index]
-		name := strings.ReplaceAll(strings.ToLower(item.Name), " ", "")
-		input = strings.ReplaceAll(strings.ToLower(input), " ", "")
-
-		return strings.Contains(name, input)
-	}
-
 	prompt := promptui.Select{
 		Label:     title,
 		Items:     selectList,
 		Templates: templates,
 		Size:      8,
-		Searcher:  searcher,
+		Searcher:  selectItemSearcher(selectList),
 	}
 
 	return prompt
 }
 
+// selectItemSearcher matches the names of the items in the list, ignoring case and spaces
+func selectItemSearcher(selectList []SelectItem) func(input string, index int) bool {
+	return func(input string, index int) bool {
+		item := selectList[index]
+		name := strings.ReplaceAll(strings.ToLower(item.Name), " ", "")
+		input = strings.ReplaceAll(strings.ToLower(input), " ", "")
+
+		return strings.Contains(name, input)
+	}
+}
+
 // GetAnswerFromCustomPrompt will take in a select prompt (usually custom), a list of items
 // returns the selected answer as a string
 func GetAnswerFromCustomPrompt(prompt promptui.Select, list []SelectItem) (string, error) {
@@ -60,6 +68,18 @@ func GetAnswerFromCustomPrompt(prompt promptui.Select, list []SelectItem) (strin
 	return selected.Value, nil
 }
 
+// FindSelectItem will look for the item with the given value in a list of SelectItems
+// returns the item and whether it was found
+func FindSelectItem(list []SelectItem, value string) (SelectItem, bool) {
+	for _, item := range list {
+		if item.Value == value {
+			return item, true
+		}
+	}
+
+	return SelectItem{}, false
+}
+
 // ValidateAreYouSure will check whether they entered yes
 func ValidateAreYouSure(input string) error {
 	if input == "y" || input == "n" {
@@ -79,12 +99,160 @@ func ValidateDropletName(input string) error {
 	return nil
 }
 
+// tagPattern matches the tags DigitalOcean accepts, which are also valid on the other providers
+var tagPattern = regexp.MustCompile(`^[a-zA-Z0-9_:\-]{1,255}$`)
+
+// ValidateTags will check whether they entered a comma separated list of valid tags
+// entering nothing is allowed, it means no tags
+func ValidateTags(input string) error {
+	for _, tag := range ParseTags(input) {
+		if !tagPattern.MatchString(tag) {
+			return fmt.Errorf("Tag %q must be at most 255 letters, numbers, colons, dashes or underscores", tag)
+		}
+	}
+	return nil
+}
+
+// ParseTags splits a comma separated list of tags, dropping spaces and empty tags
+func ParseTags(input string) []string {
+	tags := []string{}
+	for _, tag := range strings.Split(input, ",") {
+		tag = strings.TrimSpace(tag)
+		if tag != "" {
+			tags = append(tags, tag)
+		}
+	}
+	return tags
+}
+
+// volumeNamePattern matches the volume names DigitalOcean accepts
+var volumeNamePattern = regexp.MustCompile(`^[a-z][a-z0-9-]{0,63}$`)
+
+// ValidateVolumeName will check whether they entered a valid volume name
+func ValidateVolumeName(input string) error {
+	if !volumeNamePattern.MatchString(input) {
+		return errors.New("Must start with a letter and be at most 64 lowercase letters, numbers or dashes")
+	}
+	return nil
+}
+
+// ValidateVolumeSize will check whether they entered a whole number of GiB
+func ValidateVolumeSize(input string) error {
+	size, err := strconv.ParseInt(input, 10, 64)
+	if err != nil || size < 1 || size > 16384 {
+		return errors.New("Must be a whole number of GiB between 1 and 16384")
+	}
+	return nil
+}
+
+// FilesystemLabelLimits is the longest filesystem label each filesystem a new volume can be formatted with allows
+var FilesystemLabelLimits = map[string]int{"ext4": 16, "xfs": 12}
+
+// ValidateFilesystemLabel will check whether the label fits the filesystem, an empty label is allowed
+func ValidateFilesystemLabel(filesystem string, label string) error {
+	if label == "" {
+		return nil
+	}
+	if _, ok := FilesystemLabelLimits[filesystem]; !ok {
+		return errors.New("Label needs the volume to be formatted with ext4 or xfs")
+	}
+	if len(label) > FilesystemLabelLimits[filesystem] {
+		return fmt.Errorf("Label must be at most %d characters for %s", FilesystemLabelLimits[filesystem], filesystem)
+	}
+	if strings.Contains(label, " ") {
+		return errors.New("Label must not contain a space")
+	}
+	return nil
+}
+
+// Region features a droplet can need, as named in the Features of a godo.Region
+const (
+	RegionFeatureBackups = "backups"
+	RegionFeatureIPv6    = "ipv6"
+	RegionFeatureAgent   = "install_agent"
+	RegionFeatureStorage = "storage"
+)
+
+// regionBadges are shown next to a region for each of its features, in this order
+var regionBadges = []struct {
+	feature string
+	badge   string
+}{
+	{RegionFeatureBackups, "backups"},
+	{RegionFeatureIPv6, "IPv6"},
+	{RegionFeatureStorage, "volumes"},
+	{RegionFeatureAgent, "agent"},
+	{"metadata", "metadata"},
+}
+
+// RegionFeatures returns the region features needed by the droplet features, monitoring works everywhere
+func RegionFeatures(features []string) []string {
+	needed := []string{}
+
+	for _, feature := range features {
+		switch feature {
+		case FeatureBackups:
+			needed = append(needed, RegionFeatureBackups)
+		case FeatureIPv6:
+			needed = append(needed, RegionFeatureIPv6)
+		case FeatureAgent:
+			needed = append(needed, RegionFeatureAgent)
+		}
+	}
+
+	return needed
+}
+
+// CheckRegion returns why a droplet of the size needing the region features can't be created in the region
+// An empty size, one that hasn't been chosen yet, isn't checked. returns nil when it can
+func CheckRegion(region godo.Region, size godo.Size, features []string) error {
+	if !region.Available {
+		return fmt.Errorf("region %q is not available", region.Slug)
+	}
+
+	if size.Slug != "" && !contains(size.Regions, region.Slug) {
+		return fmt.Errorf("size %q is not offered in %s", size.Slug, region.Slug)
+	}
+
+	for _, feature := range features {
+		if !contains(region.Features, feature) {
+			return fmt.Errorf("region %q does not offer %s", region.Slug, feature)
+		}
+	}
+
+	return nil
+}
+
+// FilterRegions returns the regions a droplet of the size needing the region features can be created in
+func FilterRegions(list []godo.Region, size godo.Size, features []string) []godo.Region {
+	filtered := []godo.Region{}
+
+	for _, region := range list {
+		if CheckRegion(region, size, features) == nil {
+			filtered = append(filtered, region)
+		}
+	}
+
+	return filtered
+}
+
 // ParseRegionListresults will return a list of DigitalOcean regions as SelectItems to be used for promptui
+// regions are shown with badges for the features they offer
 func ParseRegionListresults(list []godo.Region) []SelectItem {
 	selectList := []SelectItem{}
 
 	for _, element := range list {
-		listItem := SelectItem{Name: element.Name, Value: element.Slug}
+		name := element.Name
+		badges := []string{}
+		for _, badge := range regionBadges {
+			if contains(element.Features, badge.feature) {
+				badges = append(badges, badge.badge)
+			}
+		}
+		if len(badges) > 0 {
+			name += " [" + strings.Join(badges, ", ") + "]"
+		}
+		listItem := SelectItem{Name: name, Value: element.Slug}
 		selectList = append(selectList, listItem)
 	}
 
@@ -92,11 +260,16 @@ func ParseRegionListresults(list []godo.Region) []SelectItem {
 }
 
 // ParseImageListResults will return a list of DigitalOcean images as SelectItems to be used for promptui
+// images without a slug are selected by their ID, and the minimum disk size and creation date are shown when known
 func ParseImageListResults(list []godo.Image) []SelectItem {
 	selectList := []SelectItem{}
 
 	for _, element := range list {
-		listItem := SelectItem{Name: element.Name, Value: element.Slug}
+		name := element.Name
+		if details := imageDetails(element); details != "" {
+			name += " (" + details + ")"
+		}
+		listItem := SelectItem{Name: name, Value: ImageValue(element)}
 		selectList = append(selectList, listItem)
 	}
 
@@ -104,11 +277,73 @@ func ParseImageListResults(list []godo.Image) []SelectItem {
 }
 
 // ParseSizeListResults will return a list of DigitalOcean sizes as SelectItems to be used for promptui
+// the name shows the size's specs and prices as columns and sizes that aren't available are left out
 func ParseSizeListResults(list []godo.Size) []SelectItem {
 	selectList := []SelectItem{}
 
+	for _, element := range FilterSizes(list, nil) {
+		listItem := SelectItem{Name: sizeColumns(element), Value: element.Slug}
+		selectList = append(selectList, listItem)
+	}
+
+	return selectList
+}
+
+// ParseVPCListResults will return a list of DigitalOcean VPCs as SelectItems to be used for promptui
+// the name shows the VPC's IP range and whether it is the region's default
+func ParseVPCListResults(list []*godo.VPC) []SelectItem {
+	selectList := []SelectItem{}
+
+	for _, element := range list {
+		name := fmt.Sprintf("%s (%s)", element.Name, element.IPRange)
+		if element.Default {
+			name += " default"
+		}
+		listItem := SelectItem{Name: name, Value: element.ID}
+		selectList = append(selectList, listItem)
+	}
+
+	return selectList
+}
+
+// Optional droplet features, these are the values of the SelectItems from ParseDropletFeatureList
+const (
+	FeatureBackups    = "backups"
+	FeatureMonitoring = "monitoring"
+	FeatureIPv6       = "ipv6"
+	FeatureAgent      = "agent"
+)
+
+// DropletFeatures lists every optional droplet feature
+var DropletFeatures = []string{FeatureBackups, FeatureMonitoring, FeatureIPv6, FeatureAgent}
+
+// BackupPriceRate is what weekly backups cost as a share of the droplet's monthly price
+const BackupPriceRate = 0.2
+
+// VolumePricePerGiB is what a block storage volume costs a month for each GiB
+const VolumePricePerGiB = 0.10
+
+// ParseDropletFeatureList will return the optional droplet features as SelectItems to be used for promptui
+// backups show what they would add to the monthly price of the given size
+func ParseDropletFeatureList(size godo.Size) []SelectItem {
+	return []SelectItem{
+		{Name: fmt.Sprintf("Backups (weekly, +$%.2f/mo)", size.PriceMonthly*BackupPriceRate), Value: FeatureBackups},
+		{Name: "Monitoring", Value: FeatureMonitoring},
+		{Name: "IPv6", Value: FeatureIPv6},
+		{Name: "Droplet agent (web console access)", Value: FeatureAgent},
+	}
+}
+
+// ParseVolumeListResults will return a list of DigitalOcean volumes as SelectItems to be used for promptui
+func ParseVolumeListResults(list []godo.Volume) []SelectItem {
+	selectList := []SelectItem{}
+
 	for _, element := range list {
-		listItem := SelectItem{Name: element.Slug, Value: element.Slug}
+		name := fmt.Sprintf("%s (%d GiB", element.Name, element.SizeGigaBytes)
+		if element.FilesystemType != "" {
+			name += ", " + element.FilesystemType
+		}
+		listItem := SelectItem{Name: name + ")", Value: element.ID}
 		selectList = append(selectList, listItem)
 	}
 
@@ -141,12 +376,204 @@ func ParseDropletListResults(list []godo.Droplet) []SelectItem {
 	return selectList
 }
 
-// AskForProvider will ask the user which provider they would like to use
+// ParseHetznerLocationListResults will return a list of Hetzner locations as SelectItems to be used for promptui
+func ParseHetznerLocationListResults(list []*hcloud.Location) []SelectItem {
+	selectList := []SelectItem{}
+
+	for _, element := range list {
+		listItem := SelectItem{Name: element.Description, Value: element.Name}
+		selectList = append(selectList, listItem)
+	}
+
+	return selectList
+}
+
+// ParseHetznerImageListResults will return a list of Hetzner images as SelectItems to be used for promptui
+// deprecated images are left out as new servers can't be created from them
+func ParseHetznerImageListResults(list []*hcloud.Image) []SelectItem {
+	selectList := []SelectItem{}
+
+	for _, element := range list {
+		if element.IsDeprecated() {
+			continue
+		}
+		listItem := SelectItem{Name: element.Description, Value: element.Name}
+		selectList = append(selectList, listItem)
+	}
+
+	return selectList
+}
+
+// ParseHetznerServerTypeListResults will return a list of Hetzner server types as SelectItems to be used for promptui
+// deprecated server types are left out as new servers can't be created with them
+func ParseHetznerServerTypeListResults(list []*hcloud.ServerType) []SelectItem {
+	selectList := []SelectItem{}
+
+	for _, element := range list {
+		if element.IsDeprecated() {
+			continue
+		}
+		name := fmt.Sprintf("%s (%d vCPU, %gGB RAM, %dGB disk)", element.Description, element.Cores, element.Memory, element.Disk)
+		listItem := SelectItem{Name: name, Value: element.Name}
+		selectList = append(selectList, listItem)
+	}
+
+	return selectList
+}
+
+// ParseHetznerSSHKeyListResults will return a list of Hetzner ssh keys as SelectItems to be used for promptui
+func ParseHetznerSSHKeyListResults(list []*hcloud.SSHKey) []SelectItem {
+	selectList := []SelectItem{}
+
+	for _, element := range list {
+		id := strconv.FormatInt(element.ID, 10)
+		listItem := SelectItem{Name: element.Name, Value: id}
+		selectList = append(selectList, listItem)
+	}
+
+	return selectList
+}
+
+// ParseLinodeRegionListResults will return a list of Linode regions as SelectItems to be used for promptui
+func ParseLinodeRegionListResults(list []linodego.Region) []SelectItem {
+	selectList := []SelectItem{}
+
+	for _, element := range list {
+		listItem := SelectItem{Name: element.Label, Value: element.ID}
+		selectList = append(selectList, listItem)
+	}
+
+	return selectList
+}
+
+// ParseLinodeImageListResults will return a list of Linode images as SelectItems to be used for promptui
+// deprecated images are left out as new instances can't be created from them
+func ParseLinodeImageListResults(list []linodego.Image) []SelectItem {
+	selectList := []SelectItem{}
+
+	for _, element := range list {
+		if element.Deprecated {
+			continue
+		}
+		listItem := SelectItem{Name: element.Label, Value: element.ID}
+		selectList = append(selectList, listItem)
+	}
+
+	return selectList
+}
+
+// ParseLinodeTypeListResults will return a list of Linode types as SelectItems to be used for promptui
+func ParseLinodeTypeListResults(list []linodego.LinodeType) []SelectItem {
+	selectList := []SelectItem{}
+
+	for _, element := range list {
+		name := fmt.Sprintf("%s (%d vCPU, %gGB RAM, %dGB disk)", element.Label, element.VCPUs, float64(element.Memory)/1024, element.Disk/1024)
+		listItem := SelectItem{Name: name, Value: element.ID}
+		selectList = append(selectList, listItem)
+	}
+
+	return selectList
+}
+
+// ParseLinodeSSHKeyListResults will return a list of Linode ssh keys as SelectItems to be used for promptui
+func ParseLinodeSSHKeyListResults(list []linodego.SSHKey) []SelectItem {
+	selectList := []SelectItem{}
+
+	for _, element := range list {
+		id := strconv.Itoa(element.ID)
+		listItem := SelectItem{Name: element.Label, Value: id}
+		selectList = append(selectList, listItem)
+	}
+
+	return selectList
+}
+
+// ParseAWSRegionListResults will return a list of AWS regions as SelectItems to be used for promptui
+func ParseAWSRegionListResults(list []ec2.Region) []SelectItem {
+	selectList := []SelectItem{}
+
+	for _, element := range list {
+		listItem := SelectItem{Name: element.RegionName, Value: element.RegionName}
+		selectList = append(selectList, listItem)
+	}
+
+	return selectList
+}
+
+// ParseAWSAvailabilityZoneListResults will return a list of AWS availability zones as SelectItems to be used for promptui
+func ParseAWSAvailabilityZoneListResults(list []ec2.AvailabilityZone) []SelectItem {
+	selectList := []SelectItem{}
+
+	for _, element := range list {
+		listItem := SelectItem{Name: element.ZoneName, Value: element.ZoneName}
+		selectList = append(selectList, listItem)
+	}
+
+	return selectList
+}
+
+// ParseAWSImageListResults will return a list of AMIs as SelectItems to be used for promptui
+func ParseAWSImageListResults(list []ec2.Image) []SelectItem {
+	selectList := []SelectItem{}
+
+	for _, element := range list {
+		name := fmt.Sprintf("%s (%s)", element.Name, element.ImageID)
+		listItem := SelectItem{Name: name, Value: element.ImageID}
+		selectList = append(selectList, listItem)
+	}
+
+	return selectList
+}
+
+// ParseAWSInstanceTypeListResults will return a list of EC2 instance types as SelectItems to be used for promptui
+func ParseAWSInstanceTypeListResults(list []ec2.InstanceType) []SelectItem {
+	selectList := []SelectItem{}
+
+	for _, element := range list {
+		name := fmt.Sprintf("%s (%d vCPU, %gGB RAM)", element.InstanceType, element.VCPUs, float64(element.MemoryMiB)/1024)
+		listItem := SelectItem{Name: name, Value: element.InstanceType}
+		selectList = append(selectList, listItem)
+	}
+
+	return selectList
+}
+
+// ParseAWSKeyPairListResults will return a list of EC2 key pairs as SelectItems to be used for promptui
+func ParseAWSKeyPairListResults(list []ec2.KeyPair) []SelectItem {
+	selectList := []SelectItem{}
+
+	for _, element := range list {
+		listItem := SelectItem{Name: element.KeyName, Value: element.KeyName}
+		selectList = append(selectList, listItem)
+	}
+
+	return selectList
+}
+
+// ParseAWSSecurityGroupListResults will return a list of EC2 security groups as SelectItems to be used for promptui
+func ParseAWSSecurityGroupListResults(list []ec2.SecurityGroup) []SelectItem {
+	selectList := []SelectItem{}
+
+	for _, element := range list {
+		name := fmt.Sprintf("%s (%s)", element.GroupName, element.GroupID)
+		listItem := SelectItem{Name: name, Value: element.GroupID}
+		selectList = append(selectList, listItem)
+	}
+
+	return selectList
+}
+
+// AskForProvider will ask the user which of the supported providers they would like to use
 // returns the selected provider as a string
-func AskForProvider() (string, error) {
-	supportedProviders := []SelectItem{}
-	digitalOcean := SelectItem{Name: "DigitalOcean", Value: "DO"}
-	supportedProviders = append(supportedProviders, digitalOcean)
+func AskForProvider(supportedProviders []SelectItem) (string, error) {
+	if len(supportedProviders) == 0 {
+		return "", errors.New("No supported providers")
+	}
+
+	// There is nothing to choose between so don't block scripts on a prompt
+	if len(supportedProviders) == 1 {
+		return supportedProviders[0].Value, nil
+	}
 
 	providerPrompt := CreateCustomSelectPrompt("Select Provider", supportedProviders)
 
@@ -161,6 +588,23 @@ func AskForProvider() (string, error) {
 	return selectedProvider.Value, nil
 }
 
+// AskYesNo will ask the user a y/n question
+// answering with a "y" will return true
+func AskYesNo(label string) (bool, error) {
+	promptAreYouSure := promptui.Prompt{
+		Label:    label,
+		Validate: ValidateAreYouSure,
+	}
+
+	areYouSure, err := promptAreYouSure.Run()
+
+	if err != nil {
+		return false, err
+	}
+
+	return areYouSure == "y", nil
+}
+
 // AskAndAnswerCustomSelect will ask a custom select question and return the selected answer as a string
 func AskAndAnswerCustomSelect(title string, list []SelectItem) (string, error) {
 	prompt := CreateCustomSelectPrompt(title, list)
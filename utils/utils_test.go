@@ -1 +1,628 @@
 package utils
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/digitalocean/godo"
+)
+
+func TestFindSelectItem(t *testing.T) {
+	list := []SelectItem{
+		{Name: "London 1", Value: "lon1"},
+		{Name: "New York 1", Value: "nyc1"},
+	}
+
+	tests := []struct {
+		name     string
+		value    string
+		expected SelectItem
+		found    bool
+	}{
+		{
+			name:     "value in list",
+			value:    "nyc1",
+			expected: SelectItem{Name: "New York 1", Value: "nyc1"},
+			found:    true,
+		},
+		{
+			name:  "value not in list",
+			value: "ams3",
+			found: false,
+		},
+		{
+			name:  "name is not matched",
+			value: "London 1",
+			found: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			item, found := FindSelectItem(list, tt.value)
+
+			if found != tt.found {
+				t.Errorf("expected found = %v, got %v", tt.found, found)
+			}
+			if item != tt.expected {
+				t.Errorf("expected item %+v, got %+v", tt.expected, item)
+			}
+		})
+	}
+}
+
+func TestValidateDropletName(t *testing.T) {
+	tests := []struct {
+		name        string
+		input       string
+		expectError bool
+	}{
+		{
+			name:        "valid name",
+			input:       "web-1",
+			expectError: false,
+		},
+		{
+			name:        "empty name",
+			input:       "",
+			expectError: true,
+		},
+		{
+			name:        "name with space",
+			input:       "web 1",
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateDropletName(tt.input)
+
+			if tt.expectError && err == nil {
+				t.Error("expected error, got nil")
+			}
+			if !tt.expectError && err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+		})
+	}
+}
+
+func TestValidateTags(t *testing.T) {
+	tests := []struct {
+		name        string
+		input       string
+		expected    []string
+		expectError bool
+	}{
+		{
+			name:     "no tags",
+			input:    "",
+			expected: []string{},
+		},
+		{
+			name:     "several tags",
+			input:    "web, env:prod,,team_a ",
+			expected: []string{"web", "env:prod", "team_a"},
+		},
+		{
+			name:        "tag with space",
+			input:       "web, my tag",
+			expectError: true,
+		},
+		{
+			name:        "tag with invalid character",
+			input:       "web/api",
+			expectError: true,
+		},
+		{
+			name:        "tag too long",
+			input:       strings.Repeat("a", 256),
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateTags(tt.input)
+
+			if tt.expectError {
+				if err == nil {
+					t.Error("expected error, got nil")
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			tags := ParseTags(tt.input)
+			if strings.Join(tags, ",") != strings.Join(tt.expected, ",") || len(tags) != len(tt.expected) {
+				t.Errorf("expected tags %v, got %v", tt.expected, tags)
+			}
+		})
+	}
+}
+
+func TestMultiSelection(t *testing.T) {
+	list := []SelectItem{
+		{Name: "laptop", Value: "1"},
+		{Name: "desktop", Value: "2"},
+		{Name: "ci", Value: "3"},
+	}
+
+	tests := []struct {
+		name     string
+		toggles  []int
+		all      int
+		expected []string
+		label    string
+	}{
+		{
+			name:     "nothing selected",
+			expected: []string{},
+			label:    "Select all",
+		},
+		{
+			name:     "values are in list order",
+			toggles:  []int{2, 0},
+			expected: []string{"1", "3"},
+			label:    "Select all",
+		},
+		{
+			name:     "toggling twice deselects",
+			toggles:  []int{1, 1},
+			expected: []string{},
+			label:    "Select all",
+		},
+		{
+			name:     "select all",
+			toggles:  []int{1},
+			all:      1,
+			expected: []string{"1", "2", "3"},
+			label:    "Deselect all",
+		},
+		{
+			name:     "deselect all",
+			all:      2,
+			expected: []string{},
+			label:    "Select all",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			selection := newMultiSelection(list)
+
+			for _, index := range tt.toggles {
+				selection.toggle(index)
+			}
+			for i := 0; i < tt.all; i++ {
+				selection.toggleAll()
+			}
+
+			values := selection.values()
+			if strings.Join(values, ",") != strings.Join(tt.expected, ",") {
+				t.Errorf("expected values %v, got %v", tt.expected, values)
+			}
+
+			options := selection.options()
+			if len(options) != len(list)+2 {
+				t.Fatalf("expected %d options, got %d", len(list)+2, len(options))
+			}
+			if options[1].Name != tt.label {
+				t.Errorf("expected toggle all option %q, got %q", tt.label, options[1].Name)
+			}
+			for index, option := range options[2:] {
+				if option.Checked != selection.selected[index] {
+					t.Errorf("expected option %d checked = %v", index, selection.selected[index])
+				}
+			}
+		})
+	}
+}
+
+func TestFilterSizes(t *testing.T) {
+	list := []godo.Size{
+		{Slug: "s-1vcpu-1gb", Available: true, Regions: []string{"lon1", "ams3"}},
+		{Slug: "s-2vcpu-4gb", Available: true, Regions: []string{"lon1"}},
+		{Slug: "s-8vcpu-16gb", Available: false, Regions: []string{"lon1", "ams3"}},
+	}
+
+	tests := []struct {
+		name     string
+		regions  []string
+		expected []string
+	}{
+		{
+			name:     "no regions hides unavailable sizes",
+			expected: []string{"s-1vcpu-1gb", "s-2vcpu-4gb"},
+		},
+		{
+			name:     "one region",
+			regions:  []string{"ams3"},
+			expected: []string{"s-1vcpu-1gb"},
+		},
+		{
+			name:     "every region has to offer the size",
+			regions:  []string{"lon1", "ams3"},
+			expected: []string{"s-1vcpu-1gb"},
+		},
+		{
+			name:    "region offering nothing",
+			regions: []string{"nyc1"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			slugs := []string{}
+			for _, size := range FilterSizes(list, tt.regions) {
+				slugs = append(slugs, size.Slug)
+			}
+
+			if strings.Join(slugs, ",") != strings.Join(tt.expected, ",") {
+				t.Errorf("FilterSizes() = %v, want %v", slugs, tt.expected)
+			}
+		})
+	}
+}
+
+func TestFilterSizesOfferedInAny(t *testing.T) {
+	list := []godo.Size{
+		{Slug: "s-1vcpu-1gb", Available: true, Regions: []string{"lon1", "ams3"}},
+		{Slug: "s-2vcpu-4gb", Available: true, Regions: []string{"lon1"}},
+		{Slug: "s-8vcpu-16gb", Available: false, Regions: []string{"lon1", "ams3"}},
+	}
+
+	tests := []struct {
+		name     string
+		regions  []string
+		expected []string
+	}{
+		{
+			name:     "one region",
+			regions:  []string{"ams3"},
+			expected: []string{"s-1vcpu-1gb"},
+		},
+		{
+			name:     "any region can offer the size",
+			regions:  []string{"ams3", "lon1"},
+			expected: []string{"s-1vcpu-1gb", "s-2vcpu-4gb"},
+		},
+		{
+			name: "no regions",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			slugs := []string{}
+			for _, size := range FilterSizesOfferedInAny(list, tt.regions) {
+				slugs = append(slugs, size.Slug)
+			}
+
+			if strings.Join(slugs, ",") != strings.Join(tt.expected, ",") {
+				t.Errorf("FilterSizesOfferedInAny() = %v, want %v", slugs, tt.expected)
+			}
+		})
+	}
+}
+
+func TestSortSizes(t *testing.T) {
+	list := []godo.Size{
+		{Slug: "c-2", Memory: 4096, PriceMonthly: 42},
+		{Slug: "s-2vcpu-2gb", Memory: 2048, PriceMonthly: 18},
+		{Slug: "s-1vcpu-2gb", Memory: 2048, PriceMonthly: 12},
+	}
+
+	tests := []struct {
+		by       string
+		expected []string
+	}{
+		{by: SizeSortPrice, expected: []string{"s-1vcpu-2gb", "s-2vcpu-2gb", "c-2"}},
+		{by: SizeSortMemory, expected: []string{"s-2vcpu-2gb", "s-1vcpu-2gb", "c-2"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.by, func(t *testing.T) {
+			slugs := []string{}
+			for _, size := range SortSizes(list, tt.by) {
+				slugs = append(slugs, size.Slug)
+			}
+
+			if strings.Join(slugs, ",") != strings.Join(tt.expected, ",") {
+				t.Errorf("SortSizes() = %v, want %v", slugs, tt.expected)
+			}
+		})
+	}
+
+	if list[0].Slug != "c-2" {
+		t.Errorf("SortSizes() changed the given list")
+	}
+}
+
+func TestMatchSizeSearch(t *testing.T) {
+	size := godo.Size{Slug: "s-2vcpu-4gb", Vcpus: 2, Memory: 4096, Disk: 80, Transfer: 4, PriceMonthly: 24, PriceHourly: 0.03571}
+	row := size.Slug + "  " + sizeColumns(size)
+
+	tests := []struct {
+		term     string
+		expected bool
+	}{
+		{term: "", expected: true},
+		{term: "$<=24", expected: true},
+		{term: "$<24", expected: false},
+		{term: "$>10", expected: true},
+		{term: ">=4gb", expected: true},
+		{term: ">4gb", expected: false},
+		{term: "4gb", expected: true},
+		{term: "2vcpu", expected: true},
+		{term: "premium", expected: false},
+		{term: "$<=30 >=2gb", expected: true},
+		{term: "$<=30 >=8gb", expected: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.term, func(t *testing.T) {
+			if got := matchSizeSearch(size, row, tt.term); got != tt.expected {
+				t.Errorf("matchSizeSearch(%q) = %v, want %v", tt.term, got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestCheckRegion(t *testing.T) {
+	size := godo.Size{Slug: "s-1vcpu-1gb", Regions: []string{"lon1", "ams3", "sfo3"}}
+
+	tests := []struct {
+		name     string
+		region   godo.Region
+		features []string
+		expected string
+	}{
+		{
+			name:   "compatible",
+			region: godo.Region{Slug: "lon1", Available: true, Features: []string{"backups", "ipv6"}},
+		},
+		{
+			name:     "unavailable",
+			region:   godo.Region{Slug: "ams3", Available: false},
+			expected: `region "ams3" is not available`,
+		},
+		{
+			name:     "size not offered",
+			region:   godo.Region{Slug: "nyc1", Available: true},
+			expected: `size "s-1vcpu-1gb" is not offered in nyc1`,
+		},
+		{
+			name:     "feature offered",
+			region:   godo.Region{Slug: "lon1", Available: true, Features: []string{"backups", "ipv6"}},
+			features: RegionFeatures([]string{FeatureBackups, FeatureMonitoring, FeatureIPv6}),
+		},
+		{
+			name:     "feature missing",
+			region:   godo.Region{Slug: "sfo3", Available: true, Features: []string{"backups"}},
+			features: RegionFeatures([]string{FeatureBackups, FeatureIPv6}),
+			expected: `region "sfo3" does not offer ipv6`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := CheckRegion(tt.region, size, tt.features)
+
+			if tt.expected == "" {
+				if err != nil {
+					t.Errorf("CheckRegion() unexpected error: %v", err)
+				}
+				return
+			}
+
+			if err == nil || err.Error() != tt.expected {
+				t.Errorf("CheckRegion() error = %v, want %q", err, tt.expected)
+			}
+		})
+	}
+}
+
+func TestCheckRegion_NoSize(t *testing.T) {
+	// sizes are narrowed down to the regions that fit before one is chosen, so any available region with the features will do
+	if err := CheckRegion(godo.Region{Slug: "nyc1", Available: true}, godo.Size{}, nil); err != nil {
+		t.Errorf("CheckRegion() unexpected error: %v", err)
+	}
+
+	if err := CheckRegion(godo.Region{Slug: "nyc1", Available: false}, godo.Size{}, nil); err == nil {
+		t.Error("CheckRegion() expected an unavailable region to be rejected")
+	}
+}
+
+func TestParseRegionListresults(t *testing.T) {
+	list := ParseRegionListresults([]godo.Region{
+		{Slug: "lon1", Name: "London 1", Features: []string{"metadata", "ipv6", "backups", "storage"}},
+		{Slug: "mock1", Name: "Mock 1"},
+	})
+
+	if list[0].Name != "London 1 [backups, IPv6, volumes, metadata]" || list[0].Value != "lon1" {
+		t.Errorf("unexpected region %+v", list[0])
+	}
+	if list[1].Name != "Mock 1" {
+		t.Errorf("a region without features should have no badges, got %q", list[1].Name)
+	}
+}
+
+func TestFilterImages(t *testing.T) {
+	list := []godo.Image{
+		{ID: 1, Slug: "ubuntu-24-04-x64", Status: "available", Regions: []string{"lon1", "ams3"}},
+		{ID: 2, Slug: "ubuntu-20-04-x64", Status: "retired", Regions: []string{"lon1", "ams3"}},
+		{ID: 3, Name: "snapshot", Regions: []string{"lon1"}},
+		{ID: 4, Slug: "fedora-40-x64", Status: "deleted", Regions: []string{"lon1"}},
+	}
+
+	tests := []struct {
+		name     string
+		regions  []string
+		expected []string
+	}{
+		{
+			name:     "retired and deleted images are hidden",
+			expected: []string{"ubuntu-24-04-x64", "3"},
+		},
+		{
+			name:     "images not in the region are hidden",
+			regions:  []string{"ams3"},
+			expected: []string{"ubuntu-24-04-x64"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			values := []string{}
+			for _, image := range FilterImages(list, tt.regions) {
+				values = append(values, ImageValue(image))
+			}
+
+			if strings.Join(values, ",") != strings.Join(tt.expected, ",") {
+				t.Errorf("FilterImages() = %v, want %v", values, tt.expected)
+			}
+		})
+	}
+}
+
+func TestParseImageDistributionAndVersionList(t *testing.T) {
+	list := []godo.Image{
+		{ID: 1, Slug: "ubuntu-22-04-x64", Name: "22.04 (LTS) x64", Distribution: "Ubuntu", MinDiskSize: 7, Created: "2022-04-21T10:00:00Z"},
+		{ID: 2, Slug: "debian-12-x64", Name: "12 x64", Distribution: "Debian", MinDiskSize: 7, Created: "2023-06-12T10:00:00Z"},
+		{ID: 3, Slug: "ubuntu-24-04-x64", Name: "24.04 (LTS) x64", Distribution: "Ubuntu", MinDiskSize: 7, Created: "2024-04-25T10:00:00Z"},
+		{ID: 4, Name: "My snapshot", Distribution: "Ubuntu"},
+	}
+
+	distributions := ParseImageDistributionList(list)
+	expectedDistributions := []SelectItem{
+		{Name: "Ubuntu (3 versions)", Value: "Ubuntu"},
+		{Name: "Debian (1 version)", Value: "Debian"},
+	}
+
+	if len(distributions) != len(expectedDistributions) {
+		t.Fatalf("ParseImageDistributionList() = %v, want %v", distributions, expectedDistributions)
+	}
+	for index, distribution := range distributions {
+		if distribution != expectedDistributions[index] {
+			t.Errorf("distribution %d = %+v, want %+v", index, distribution, expectedDistributions[index])
+		}
+	}
+
+	versions := ParseImageVersionList(list, "Ubuntu")
+	expectedVersions := []SelectItem{
+		{Name: "24.04 (LTS) x64 (min disk 7 GB, created 2024-04-25)", Value: "ubuntu-24-04-x64"},
+		{Name: "22.04 (LTS) x64 (min disk 7 GB, created 2022-04-21)", Value: "ubuntu-22-04-x64"},
+		{Name: "My snapshot", Value: "4"},
+	}
+
+	if len(versions) != len(expectedVersions) {
+		t.Fatalf("ParseImageVersionList() = %v, want %v", versions, expectedVersions)
+	}
+	for index, version := range versions {
+		if version != expectedVersions[index] {
+			t.Errorf("version %d = %+v, want %+v", index, version, expectedVersions[index])
+		}
+	}
+}
+
+func TestPinRecent(t *testing.T) {
+	list := []SelectItem{
+		{Name: "Amsterdam 3", Value: "ams3"},
+		{Name: "Frankfurt 1", Value: "fra1"},
+		{Name: "London 1", Value: "lon1"},
+	}
+
+	tests := []struct {
+		name     string
+		recent   []string
+		expected []SelectItem
+	}{
+		{
+			name:     "no history",
+			expected: list,
+		},
+		{
+			name:   "recent first, most recent at the top",
+			recent: []string{"lon1", "nyc1", "ams3"},
+			expected: []SelectItem{
+				{Name: "London 1 (recent)", Value: "lon1"},
+				{Name: "Amsterdam 3 (recent)", Value: "ams3"},
+				{Name: "Frankfurt 1", Value: "fra1"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pinned := PinRecent(list, tt.recent)
+
+			if len(pinned) != len(tt.expected) {
+				t.Fatalf("PinRecent() = %v, want %v", pinned, tt.expected)
+			}
+			for index, item := range pinned {
+				if item != tt.expected[index] {
+					t.Errorf("item %d = %+v, want %+v", index, item, tt.expected[index])
+				}
+			}
+		})
+	}
+}
+
+func TestSizeRows_Recent(t *testing.T) {
+	list := []godo.Size{{Slug: "s-1vcpu-1gb"}, {Slug: "s-1vcpu-2gb"}, {Slug: "s-2vcpu-4gb"}}
+
+	rows := sizeRows(list, []string{"s-2vcpu-4gb", "s-1vcpu-1gb"})
+
+	values := []string{}
+	for _, row := range rows {
+		if row.Action == "" {
+			values = append(values, row.Value)
+		}
+	}
+
+	if strings.Join(values, ",") != "s-2vcpu-4gb,s-1vcpu-1gb,s-1vcpu-2gb" {
+		t.Errorf("expected the recent sizes first, got %v", values)
+	}
+	if rows[0].Action == "" || rows[1].Action == "" || !strings.HasSuffix(rows[2].Name, "(recent)") {
+		t.Errorf("expected the sort actions then the most recent size, got %+v", rows[:3])
+	}
+}
+
+func TestResizeSizes(t *testing.T) {
+	list := []godo.Size{
+		{Slug: "s-2vcpu-4gb", Disk: 80, PriceMonthly: 24, Available: true, Regions: []string{"lon1"}},
+		{Slug: "s-1vcpu-1gb", Disk: 25, PriceMonthly: 6, Available: true, Regions: []string{"lon1"}},
+		{Slug: "s-1vcpu-2gb", Disk: 50, PriceMonthly: 12, Available: true, Regions: []string{"lon1"}},
+		{Slug: "s-1vcpu-512mb", Disk: 10, PriceMonthly: 4, Available: true, Regions: []string{"lon1"}},
+		{Slug: "s-4vcpu-8gb", Disk: 160, PriceMonthly: 48, Available: true, Regions: []string{"ams3"}},
+	}
+
+	sizes := ResizeSizes(list, "s-1vcpu-1gb", "lon1", 25)
+
+	slugs := []string{}
+	for _, size := range sizes {
+		slugs = append(slugs, size.Slug)
+	}
+
+	if strings.Join(slugs, ",") != "s-1vcpu-2gb,s-2vcpu-4gb" {
+		t.Errorf("expected the bigger sizes in lon1 cheapest first, got %v", slugs)
+	}
+
+	items := ParseResizeSizeList(append(sizes, godo.Size{Slug: "s-1vcpu-1gb-amd", Disk: 25, PriceMonthly: 7}), list[1], 25)
+
+	for index, expected := range []string{"+$6.00/mo  [disk +25 GB, irreversible]", "+$18.00/mo  [disk +55 GB, irreversible]", "+$1.00/mo"} {
+		if !strings.HasSuffix(items[index].Name, expected) {
+			t.Errorf("expected %q to end with %q", items[index].Name, expected)
+		}
+	}
+
+	cheaper := ParseResizeSizeList([]godo.Size{list[1]}, list[0], 25)
+	if !strings.HasSuffix(cheaper[0].Name, "-$18.00/mo") {
+		t.Errorf("expected a negative price difference, got %q", cheaper[0].Name)
+	}
+}